@@ -52,6 +52,16 @@ func exec(ctx context.Context, configPath string) error {
 		return fmt.Errorf("error serving HTTP: %w", err)
 	}
 
+	redirectServer := state.RedirectServer(ctx)
+
+	if err := state.Jobs().Start(); err != nil {
+		return fmt.Errorf("error starting job runner: %w", err)
+	}
+
+	if err := state.Stream().Start(); err != nil {
+		return fmt.Errorf("error starting event stream: %w", err)
+	}
+
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
 	defer cancel()
 
@@ -60,16 +70,57 @@ func exec(ctx context.Context, configPath string) error {
 
 		log.Printf("received signal: %+v", sig)
 
+		// event streams are never idle, so they are closed before shutting down the server
+		if err := state.Stream().Stop(); err != nil {
+			log.Printf("event stream shutdown faced error: %s", err)
+		}
+
+		if redirectServer != nil {
+			if err := redirectServer.Shutdown(shutdownCtx); err != nil {
+				log.Printf("redirect server shutdown faced error: %s", err)
+			}
+		}
+
 		err := server.Shutdown(shutdownCtx)
 		if err != nil {
 			log.Printf("server shutdown faced error: %s", err)
 		}
 	}()
 
-	err = server.ListenAndServe()
+	if redirectServer != nil {
+		go func() {
+			err := redirectServer.ListenAndServe()
+			if !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("redirect server stopped with error: %s", err)
+			}
+		}()
+	}
+
+	err = listenAndServe(server)
+
+	// running jobs are given the same time to finish as HTTP requests
+	if stopErr := state.Jobs().Stop(shutdownCtx); stopErr != nil {
+		log.Printf("job runner shutdown faced error: %s", stopErr)
+	}
+
+	// jobs can publish events, so event bus is stopped after them
+	if stopErr := state.Events().Stop(shutdownCtx); stopErr != nil {
+		log.Printf("event bus shutdown faced error: %s", stopErr)
+	}
+
 	if !errors.Is(err, http.ErrServerClosed) {
 		return fmt.Errorf("server stopped with error: %w", err)
 	}
 
 	return nil
 }
+
+// listenAndServe starts server using TLS if it's configured.
+func listenAndServe(server *http.Server) error {
+	if server.TLSConfig != nil {
+		// certificates are provided by TLSConfig.GetCertificate
+		return server.ListenAndServeTLS("", "") //nolint:wrapcheck
+	}
+
+	return server.ListenAndServe() //nolint:wrapcheck
+}
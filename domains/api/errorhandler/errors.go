@@ -33,6 +33,14 @@ func errToHTTPError(err error) *echo.HTTPError {
 		return &echo.HTTPError{Code: http.StatusNotFound, Message: err.Error()}
 	case errors.Is(err, errbase.ErrConflict):
 		return &echo.HTTPError{Code: http.StatusConflict, Message: err.Error()}
+	case errors.Is(err, errbase.ErrTooLarge):
+		return &echo.HTTPError{Code: http.StatusRequestEntityTooLarge, Message: err.Error()}
+	case errors.Is(err, errbase.ErrUnsupportedMediaType):
+		return &echo.HTTPError{Code: http.StatusUnsupportedMediaType, Message: err.Error()}
+	case errors.Is(err, errbase.ErrNotAcceptable):
+		return &echo.HTTPError{Code: http.StatusNotAcceptable, Message: err.Error()}
+	case errors.Is(err, errbase.ErrUpstream):
+		return &echo.HTTPError{Code: http.StatusBadGateway, Message: err.Error()}
 	case errors.Is(err, validation.ErrValidationFailed),
 		errors.As(err, &bindErr):
 		return &echo.HTTPError{Code: http.StatusBadRequest, Message: err.Error()}
@@ -54,6 +54,26 @@ func TestConvertErrors(t *testing.T) {
 			http.StatusConflict,
 			errbase.ErrConflict.Error(),
 		},
+		{
+			errbase.ErrTooLarge,
+			http.StatusRequestEntityTooLarge,
+			errbase.ErrTooLarge.Error(),
+		},
+		{
+			errbase.ErrUnsupportedMediaType,
+			http.StatusUnsupportedMediaType,
+			errbase.ErrUnsupportedMediaType.Error(),
+		},
+		{
+			errbase.ErrNotAcceptable,
+			http.StatusNotAcceptable,
+			errbase.ErrNotAcceptable.Error(),
+		},
+		{
+			errbase.ErrUpstream,
+			http.StatusBadGateway,
+			errbase.ErrUpstream.Error(),
+		},
 		{
 			errbase.ErrNotFound,
 			http.StatusNotFound,
@@ -4,13 +4,10 @@ import (
 	echojwt "github.com/labstack/echo-jwt"
 	"github.com/labstack/echo/v4"
 	"github.com/outcatcher/anwil/domains/core/config/schema"
+	"github.com/outcatcher/anwil/domains/users/auth"
 	"github.com/outcatcher/anwil/domains/users/service"
 )
 
-const (
-	contextKeyUsername = "username"
-)
-
 // JWTAuth check JWT and loads user info into Gin context.
 //
 // This middleware happens before request is processed, so we need to abort context early,
@@ -23,10 +20,11 @@ func JWTAuth(state schema.WithConfig) echo.MiddlewareFunc {
 
 	return func(n echo.HandlerFunc) echo.HandlerFunc {
 		return echojwt.WithConfig(echojwt.Config{
-			ContextKey:     contextKeyUsername,
+			ContextKey:     auth.ContextKey,
 			SigningKey:     pKey,
 			KeyFunc:        service.Ed25519KeyFunc(pKey.Public()),
 			ParseTokenFunc: nil,
+			NewClaimsFunc:  auth.NewClaims,
 		})(n)
 	}
 }
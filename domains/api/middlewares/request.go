@@ -0,0 +1,27 @@
+package middlewares
+
+import (
+	"github.com/labstack/echo/v4"
+	auditSchema "github.com/outcatcher/anwil/domains/audit/schema"
+)
+
+// RequestInfo adds request details used by audit log to the request context.
+//
+// Request ID is taken from the response, so the middleware should follow echo RequestID middleware.
+func RequestInfo() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			request := c.Request()
+
+			ctx := auditSchema.WithRequestInfo(request.Context(), auditSchema.RequestInfo{
+				IP:        c.RealIP(),
+				UserAgent: request.UserAgent(),
+				RequestID: c.Response().Header().Get(echo.HeaderXRequestID),
+			})
+
+			c.SetRequest(request.WithContext(ctx))
+
+			return next(c)
+		}
+	}
+}
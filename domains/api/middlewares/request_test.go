@@ -0,0 +1,40 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	auditSchema "github.com/outcatcher/anwil/domains/audit/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestInfo(t *testing.T) {
+	t.Parallel()
+
+	var info auditSchema.RequestInfo
+
+	engine := echo.New()
+	engine.Use(middleware.RequestID(), RequestInfo())
+	engine.GET("/", func(c echo.Context) error {
+		info = auditSchema.RequestInfoFromContext(c.Request().Context())
+
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set(echo.HeaderXRealIP, "203.0.113.7")
+	request.Header.Set("User-Agent", "test-agent")
+
+	recorder := httptest.NewRecorder()
+
+	engine.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusNoContent, recorder.Code)
+	require.Equal(t, "203.0.113.7", info.IP)
+	require.Equal(t, "test-agent", info.UserAgent)
+	require.NotEmpty(t, info.RequestID)
+	require.Equal(t, recorder.Header().Get(echo.HeaderXRequestID), info.RequestID)
+}
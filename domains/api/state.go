@@ -14,13 +14,34 @@ import (
 	"github.com/outcatcher/anwil/domains/api/commonhandlers"
 	"github.com/outcatcher/anwil/domains/api/errorhandler"
 	"github.com/outcatcher/anwil/domains/api/middlewares"
+	"github.com/outcatcher/anwil/domains/api/tlsconfig"
+	audit "github.com/outcatcher/anwil/domains/audit/service"
+	"github.com/outcatcher/anwil/domains/blobs"
+	blobHandlers "github.com/outcatcher/anwil/domains/blobs/handlers"
+	blobsSchema "github.com/outcatcher/anwil/domains/blobs/schema"
 	"github.com/outcatcher/anwil/domains/core/config"
 	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
+	"github.com/outcatcher/anwil/domains/core/negotiation"
 	"github.com/outcatcher/anwil/domains/core/services"
 	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	currency "github.com/outcatcher/anwil/domains/currency/service"
+	"github.com/outcatcher/anwil/domains/events"
+	eventsSchema "github.com/outcatcher/anwil/domains/events/schema"
+	export "github.com/outcatcher/anwil/domains/export/service"
+	"github.com/outcatcher/anwil/domains/jobs"
+	jobsSchema "github.com/outcatcher/anwil/domains/jobs/schema"
+	"github.com/outcatcher/anwil/domains/mail"
+	mailSchema "github.com/outcatcher/anwil/domains/mail/schema"
+	notifications "github.com/outcatcher/anwil/domains/notifications/service"
+	previews "github.com/outcatcher/anwil/domains/previews/service"
+	santa "github.com/outcatcher/anwil/domains/santa/service"
 	"github.com/outcatcher/anwil/domains/storage"
 	storageSchema "github.com/outcatcher/anwil/domains/storage/schema"
+	"github.com/outcatcher/anwil/domains/stream"
+	streamHandlers "github.com/outcatcher/anwil/domains/stream/handlers"
+	streamSchema "github.com/outcatcher/anwil/domains/stream/schema"
 	users "github.com/outcatcher/anwil/domains/users/service"
+	webhooks "github.com/outcatcher/anwil/domains/webhooks/service"
 )
 
 const defaultTimeout = time.Minute
@@ -33,6 +54,21 @@ type State struct {
 	// Shared storage driver, i.e. *sqlx.DB
 	storage storageSchema.QueryExecutor
 
+	// Shared outgoing mail sender
+	mailer mailSchema.Mailer
+
+	// Background job queue and workers
+	jobs *jobs.Runner
+
+	// Domain event bus, relaying events published in transactions with background jobs
+	events *events.Bus
+
+	// Shared storage of uploaded files
+	blobStore blobsSchema.BlobStore
+
+	// Real-time events fan-out
+	stream *stream.Broker
+
 	// Actual initialized services
 	services svcSchema.ServiceMapping
 	// Functions to add handlers after HTTP server is created
@@ -43,12 +79,21 @@ func (s *State) initEngine() (*echo.Echo, error) {
 	engine := echo.New()
 
 	engine.HTTPErrorHandler = errorhandler.HandleErrors()
+	engine.Binder = new(negotiation.Binder)
+
+	ipExtractor, err := s.ipExtractor()
+	if err != nil {
+		return nil, err
+	}
+
+	engine.IPExtractor = ipExtractor
 
 	engine.Use(
 		middleware.LoggerWithConfig(middleware.LoggerConfig{Output: s.Logger().Writer()}),
 		middleware.Recover(),
 		middleware.RemoveTrailingSlash(),
-		middlewares.RequireJSON,
+		middleware.RequestID(),
+		middlewares.RequestInfo(),
 	)
 
 	engine.Static("/static", s.Config().API.StaticPath)
@@ -66,6 +111,30 @@ func (s *State) initEngine() (*echo.Echo, error) {
 	return engine, nil
 }
 
+// ipExtractor returns client IP extractor.
+//
+// X-Forwarded-For header is trusted only for requests coming from configured proxies,
+// otherwise connection IP is used.
+func (s *State) ipExtractor() (echo.IPExtractor, error) {
+	proxies, err := s.Config().API.TrustedProxyNets()
+	if err != nil {
+		return nil, fmt.Errorf("error parsing trusted proxies: %w", err)
+	}
+
+	if len(proxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	options := make([]echo.TrustOption, 0, len(proxies)+3)
+	options = append(options, echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false))
+
+	for _, proxy := range proxies {
+		options = append(options, echo.TrustIPRange(proxy))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
+
 // Server creates new API server instance.
 func (s *State) Server(ctx context.Context) (*http.Server, error) {
 	cfg := s.Config()
@@ -82,17 +151,51 @@ func (s *State) Server(ctx context.Context) (*http.Server, error) {
 		BaseContext:       func(_ net.Listener) context.Context { return ctx },
 	}
 
+	scheme := "http"
+
+	if cfg.API.TLS.Enabled() {
+		tlsConfig, err := tlsconfig.New(cfg.API.TLS, s.Logger())
+		if err != nil {
+			return nil, fmt.Errorf("error creating server: %w", err)
+		}
+
+		server.TLSConfig = tlsConfig
+		scheme = "https"
+	}
+
 	loggedAddr := server.Addr
 
 	if cfg.API.Host == "" {
 		loggedAddr = fmt.Sprintf("localhost:%d", cfg.API.Port)
 	}
 
-	s.Logger().Printf("Anwil API server started at http://%s", loggedAddr)
+	s.Logger().Printf("Anwil API server started at %s://%s", scheme, loggedAddr)
 
 	return server, nil
 }
 
+// RedirectServer creates plain HTTP server redirecting to HTTPS API server.
+//
+// Returns nil if TLS or redirect is not configured.
+func (s *State) RedirectServer(ctx context.Context) *http.Server {
+	cfg := s.Config()
+
+	if !cfg.API.TLS.Enabled() || cfg.API.TLS.RedirectPort == 0 {
+		return nil
+	}
+
+	server := &http.Server{ //nolint:exhaustruct
+		Addr:              fmt.Sprintf("%s:%d", cfg.API.Host, cfg.API.TLS.RedirectPort),
+		Handler:           tlsconfig.RedirectHandler(cfg.API.Port),
+		ReadHeaderTimeout: defaultTimeout,
+		BaseContext:       func(_ net.Listener) context.Context { return ctx },
+	}
+
+	s.Logger().Printf("Anwil HTTP to HTTPS redirect started at %s", server.Addr)
+
+	return server
+}
+
 // Logger returns configured logger.
 func (*State) Logger() *log.Logger {
 	return log.Default()
@@ -108,11 +211,82 @@ func (s *State) Service(id svcSchema.ServiceID) any {
 	return s.services[id]
 }
 
+// RegisterService stores initialized service, making it available for the services depending on it.
+func (s *State) RegisterService(id svcSchema.ServiceID, service any) {
+	if s.services == nil {
+		s.services = make(svcSchema.ServiceMapping)
+	}
+
+	s.services[id] = service
+}
+
 // Storage returns shared query executor (i.e. *sqlx.DB).
 func (s *State) Storage() storageSchema.QueryExecutor {
 	return s.storage
 }
 
+// PrepareErasure prepares erasure of user data in all initialized services.
+func (s *State) PrepareErasure(ctx context.Context, userUUID string) (svcSchema.EraseFunc, error) {
+	erase, err := services.PrepareErasure(ctx, s.services, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("error preparing user data erasure: %w", err)
+	}
+
+	return erase, nil
+}
+
+// CollectUserData collects user data from all initialized services.
+func (s *State) CollectUserData(
+	ctx context.Context, userUUID string,
+) (map[svcSchema.ServiceID]*svcSchema.UserDataExport, error) {
+	data, err := services.CollectUserData(ctx, s.services, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("error collecting user data: %w", err)
+	}
+
+	return data, nil
+}
+
+// JobQueue returns background job queue.
+func (s *State) JobQueue() jobsSchema.Queue {
+	return s.jobs
+}
+
+// Jobs returns background job runner.
+func (s *State) Jobs() *jobs.Runner {
+	return s.jobs
+}
+
+// EventBus returns domain event bus.
+func (s *State) EventBus() eventsSchema.Bus {
+	return s.events
+}
+
+// Events returns domain event bus.
+func (s *State) Events() *events.Bus {
+	return s.events
+}
+
+// BlobStore returns storage of uploaded files.
+func (s *State) BlobStore() blobsSchema.BlobStore {
+	return s.blobStore
+}
+
+// Publisher returns publisher of real-time events.
+func (s *State) Publisher() streamSchema.Publisher {
+	return s.stream
+}
+
+// Stream returns real-time events broker.
+func (s *State) Stream() *stream.Broker {
+	return s.stream
+}
+
+// Mailer returns configured mailer.
+func (s *State) Mailer() mailSchema.Mailer {
+	return s.mailer
+}
+
 // Init initializes API and returns new API instance.
 func Init(ctx context.Context, configPath string) (*State, error) {
 	cfg, err := config.LoadServerConfiguration(ctx, path.Clean(configPath))
@@ -130,7 +304,37 @@ func Init(ctx context.Context, configPath string) (*State, error) {
 		storage: db,
 	}
 
-	usedServices := []svcSchema.ServiceDefinition{users.NewUserService()}
+	mailer, err := mail.New(cfg.Mail, apiState.Logger())
+	if err != nil {
+		return nil, fmt.Errorf("error creating mailer: %w", err)
+	}
+
+	apiState.mailer = mailer
+	apiState.jobs = jobs.New(cfg.Jobs, db, apiState.Logger())
+	apiState.events = events.New(cfg.Events, apiState.jobs, apiState.Logger())
+	apiState.stream = stream.New(cfg.DB, db, apiState.Logger())
+
+	if err := apiState.events.AddJobs(apiState.jobs); err != nil {
+		return nil, fmt.Errorf("error registering event bus jobs: %w", err)
+	}
+
+	blobStore, err := blobs.New(cfg.Blobs)
+	if err != nil {
+		return nil, fmt.Errorf("error creating blob store: %w", err)
+	}
+
+	apiState.blobStore = blobStore
+
+	usedServices := []svcSchema.ServiceDefinition{
+		users.NewUserService(),
+		export.NewExportService(),
+		previews.NewPreviewService(),
+		currency.NewCurrencyService(),
+		notifications.NewNotificationService(),
+		webhooks.NewWebhookService(),
+		audit.NewAuditService(),
+		santa.NewSantaService(),
+	}
 
 	initialized, err := services.Initialize(ctx, apiState, usedServices...)
 	if err != nil {
@@ -139,13 +343,25 @@ func Init(ctx context.Context, configPath string) (*State, error) {
 
 	apiState.services = initialized
 
+	for _, svc := range usedServices {
+		if svc.InitJobsFunc == nil {
+			continue // most services have no background jobs
+		}
+
+		if err := svc.InitJobsFunc(apiState)(apiState.jobs); err != nil {
+			return nil, fmt.Errorf("error registering jobs of service %s: %w", svc.ID, err)
+		}
+	}
+
 	// Pre-initializing handlers. At this point there is no server, so populating the functions to be
 	// called to add handlers when it will be ready.
 	//
 	// To be additionally considered: is there a need for service initialization before creating the server?
-	addHandlerFuncs := make([]svcSchema.AddHandlersFunc, 1, len(usedServices)+1)
+	addHandlerFuncs := make([]svcSchema.AddHandlersFunc, 3, len(usedServices)+3)
 
 	addHandlerFuncs[0] = commonhandlers.AddEchoHandlers
+	addHandlerFuncs[1] = blobHandlers.AddBlobHandlers(blobStore)
+	addHandlerFuncs[2] = streamHandlers.AddStreamHandlers(apiState.stream)
 
 	for _, svc := range usedServices {
 		if svc.InitHandlersFunc == nil {
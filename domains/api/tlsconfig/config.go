@@ -0,0 +1,91 @@
+/*
+Package tlsconfig contains TLS configuration for the API server.
+*/
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/outcatcher/anwil/domains/core/config/schema"
+)
+
+var (
+	errUnsupportedVersion = errors.New("unsupported TLS version")
+	errNoCACertificates   = errors.New("no CA certificates found")
+)
+
+// tlsVersions - supported minimal TLS versions.
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+const defaultMinVersion = tls.VersionTLS12
+
+// parseMinVersion converts version string to TLS version constant.
+//
+// TLS 1.2 is used by default.
+func parseMinVersion(version string) (uint16, error) {
+	if version == "" {
+		return defaultMinVersion, nil
+	}
+
+	parsed, ok := tlsVersions[version]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", errUnsupportedVersion, version)
+	}
+
+	return parsed, nil
+}
+
+// loadCertPool loads PEM-encoded CA certificates from the given path.
+func loadCertPool(caPath string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(filepath.Clean(caPath))
+	if err != nil {
+		return nil, fmt.Errorf("error reading client CA file: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("error loading client CA from %s: %w", caPath, errNoCACertificates)
+	}
+
+	return pool, nil
+}
+
+// New creates TLS configuration reloading server certificate from disk on change.
+func New(cfg schema.TLSConfiguration, logger *log.Logger) (*tls.Config, error) {
+	minVersion, err := parseMinVersion(cfg.MinVersion)
+	if err != nil {
+		return nil, fmt.Errorf("error creating TLS config: %w", err)
+	}
+
+	reloader, err := NewCertReloader(cfg.CertPath, cfg.KeyPath, logger)
+	if err != nil {
+		return nil, fmt.Errorf("error creating TLS config: %w", err)
+	}
+
+	tlsConfig := &tls.Config{ //nolint:exhaustruct
+		MinVersion:     minVersion,
+		GetCertificate: reloader.GetCertificate,
+	}
+
+	if cfg.ClientCAPath != "" {
+		pool, err := loadCertPool(cfg.ClientCAPath)
+		if err != nil {
+			return nil, fmt.Errorf("error creating TLS config: %w", err)
+		}
+
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
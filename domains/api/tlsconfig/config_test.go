@@ -0,0 +1,216 @@
+package tlsconfig
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/outcatcher/anwil/domains/core/config/schema"
+	th "github.com/outcatcher/anwil/domains/core/testhelpers"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCertificate writes new self-signed certificate and key to given paths, returning certificate serial.
+func writeCertificate(t *testing.T, certPath, keyPath string) *big.Int {
+	t.Helper()
+
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		DNSNames:              []string{"localhost"},
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, pub, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+
+	require.NoError(t, os.WriteFile(certPath, certPEM, 0o600))
+	require.NoError(t, os.WriteFile(keyPath, keyPEM, 0o600))
+
+	return serial
+}
+
+func certSerial(t *testing.T, cert *tls.Certificate) *big.Int {
+	t.Helper()
+
+	parsed, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+
+	return parsed.SerialNumber
+}
+
+func TestParseMinVersion(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		version  string
+		expected uint16
+	}{
+		{"", tls.VersionTLS12},
+		{"1.2", tls.VersionTLS12},
+		{"1.3", tls.VersionTLS13},
+	}
+
+	for _, data := range cases {
+		data := data
+
+		t.Run(data.version, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := parseMinVersion(data.version)
+			require.NoError(t, err)
+			require.Equal(t, data.expected, actual)
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := parseMinVersion("1.0")
+		require.ErrorIs(t, err, errUnsupportedVersion)
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "cert.pem")
+	keyPath := filepath.Join(dir, "key.pem")
+
+	writeCertificate(t, certPath, keyPath)
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		tlsConfig, err := New(schema.TLSConfiguration{
+			CertPath:     certPath,
+			KeyPath:      keyPath,
+			MinVersion:   "1.3",
+			ClientCAPath: certPath,
+		}, log.Default())
+		require.NoError(t, err)
+
+		require.EqualValues(t, tls.VersionTLS13, tlsConfig.MinVersion)
+		require.Equal(t, tls.RequireAndVerifyClientCert, tlsConfig.ClientAuth)
+		require.NotNil(t, tlsConfig.ClientCAs)
+	})
+
+	t.Run("invalid CA", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := New(schema.TLSConfiguration{
+			CertPath:     certPath,
+			KeyPath:      keyPath,
+			ClientCAPath: keyPath,
+		}, log.Default())
+		require.ErrorIs(t, err, errNoCACertificates)
+	})
+
+	t.Run("missing certificate", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := New(schema.TLSConfiguration{
+			CertPath: filepath.Join(dir, th.RandomString("missing-", 5)),
+			KeyPath:  keyPath,
+		}, log.Default())
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestCertReloader(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "cert.pem")
+	keyPath := filepath.Join(dir, "key.pem")
+
+	initialSerial := writeCertificate(t, certPath, keyPath)
+
+	reloader, err := NewCertReloader(certPath, keyPath, log.Default())
+	require.NoError(t, err)
+
+	reloader.checkInterval = 0
+
+	cert, err := reloader.GetCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, initialSerial, certSerial(t, cert))
+
+	newSerial := writeCertificate(t, certPath, keyPath)
+
+	// make sure modification time differs on file systems with low mtime resolution
+	newModTime := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(certPath, newModTime, newModTime))
+
+	cert, err = reloader.GetCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, newSerial, certSerial(t, cert))
+
+	// broken files are ignored, keeping previous certificate
+	require.NoError(t, os.WriteFile(keyPath, []byte("broken"), 0o600))
+	require.NoError(t, os.Chtimes(keyPath, newModTime.Add(time.Minute), newModTime.Add(time.Minute)))
+
+	cert, err = reloader.GetCertificate(nil)
+	require.NoError(t, err)
+	require.Equal(t, newSerial, certSerial(t, cert))
+}
+
+func TestRedirectHandler(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		host     string
+		port     int
+		expected string
+	}{
+		{"default port", "example.com:80", 443, "https://example.com/path?q=1"},
+		{"custom port", "example.com:8080", 8443, "https://example.com:8443/path?q=1"},
+		{"no port", "example.com", 8443, "https://example.com:8443/path?q=1"},
+	}
+
+	for _, data := range cases {
+		data := data
+
+		t.Run(data.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := th.ClosingRecorder(t)
+
+			request, err := http.NewRequest(http.MethodGet, "http://"+data.host+"/path?q=1", nil)
+			require.NoError(t, err)
+
+			RedirectHandler(data.port).ServeHTTP(recorder, request)
+
+			result := recorder.Result()
+			require.Equal(t, http.StatusPermanentRedirect, result.StatusCode)
+			require.Equal(t, data.expected, result.Header.Get("Location"))
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package tlsconfig
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const defaultHTTPSPort = 443
+
+// RedirectHandler returns handler redirecting all requests to HTTPS on the given port.
+func RedirectHandler(httpsPort int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host // no port in Host header
+		}
+
+		if httpsPort != defaultHTTPSPort {
+			host = net.JoinHostPort(host, strconv.Itoa(httpsPort))
+		}
+
+		target := url.URL{
+			Scheme:   "https",
+			Host:     host,
+			Path:     r.URL.Path,
+			RawQuery: r.URL.RawQuery,
+		}
+
+		http.Redirect(w, r, target.String(), http.StatusPermanentRedirect)
+	})
+}
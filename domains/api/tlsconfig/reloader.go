@@ -0,0 +1,132 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// defaultCheckInterval - minimal interval between checks of certificate files modification.
+const defaultCheckInterval = 10 * time.Second
+
+// CertReloader holds TLS certificate reloading it from disk when certificate or key file changes.
+type CertReloader struct {
+	certPath string
+	keyPath  string
+
+	log *log.Logger
+
+	checkInterval time.Duration
+
+	lock        sync.Mutex
+	cert        *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+	lastChecked time.Time
+}
+
+// NewCertReloader loads certificate from given paths and creates new reloader.
+func NewCertReloader(certPath, keyPath string, logger *log.Logger) (*CertReloader, error) {
+	reloader := &CertReloader{
+		certPath:      filepath.Clean(certPath),
+		keyPath:       filepath.Clean(keyPath),
+		log:           logger,
+		checkInterval: defaultCheckInterval,
+	}
+
+	if err := reloader.reload(); err != nil {
+		return nil, err
+	}
+
+	return reloader, nil
+}
+
+func modTime(path string) (time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error reading file info: %w", err)
+	}
+
+	return info.ModTime(), nil
+}
+
+// reload loads certificate and key from disk.
+func (r *CertReloader) reload() error {
+	certModTime, err := modTime(r.certPath)
+	if err != nil {
+		return fmt.Errorf("error loading certificate: %w", err)
+	}
+
+	keyModTime, err := modTime(r.keyPath)
+	if err != nil {
+		return fmt.Errorf("error loading certificate key: %w", err)
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certPath, r.keyPath)
+	if err != nil {
+		return fmt.Errorf("error loading certificate: %w", err)
+	}
+
+	r.cert = &cert
+	r.certModTime = certModTime
+	r.keyModTime = keyModTime
+
+	return nil
+}
+
+// changed checks if certificate or key files were modified since last load.
+func (r *CertReloader) changed() (bool, error) {
+	certModTime, err := modTime(r.certPath)
+	if err != nil {
+		return false, err
+	}
+
+	keyModTime, err := modTime(r.keyPath)
+	if err != nil {
+		return false, err
+	}
+
+	return !certModTime.Equal(r.certModTime) || !keyModTime.Equal(r.keyModTime), nil
+}
+
+// GetCertificate returns actual certificate. Can be used as tls.Config.GetCertificate.
+//
+// Files are checked for changes at most once per check interval.
+// In case new certificate can't be loaded, previous one is used.
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := time.Now()
+
+	if now.Sub(r.lastChecked) < r.checkInterval {
+		return r.cert, nil
+	}
+
+	r.lastChecked = now
+
+	changed, err := r.changed()
+	if err != nil {
+		r.log.Printf("error checking TLS certificate changes: %s", err)
+
+		return r.cert, nil
+	}
+
+	if !changed {
+		return r.cert, nil
+	}
+
+	if err := r.reload(); err != nil {
+		r.log.Printf("error reloading TLS certificate, using previous one: %s", err)
+
+		return r.cert, nil
+	}
+
+	r.log.Printf("TLS certificate reloaded from %s", r.certPath)
+
+	return r.cert, nil
+}
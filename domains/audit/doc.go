@@ -0,0 +1,7 @@
+/*
+Package audit contains append-only log of security-relevant and data-changing actions.
+
+Services record entries using Auditor, request details (IP, user agent and request ID) are taken
+from the request context. Entries are available to admins and are pruned after configured retention.
+*/
+package audit
@@ -0,0 +1,47 @@
+/*
+Package handlers contains API handlers for audit log endpoints.
+*/
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/outcatcher/anwil/domains/audit/service/schema"
+	"github.com/outcatcher/anwil/domains/core/negotiation"
+	"github.com/outcatcher/anwil/domains/core/services"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/outcatcher/anwil/domains/users/auth"
+	usersSchema "github.com/outcatcher/anwil/domains/users/service/schema"
+)
+
+// AddAuditHandlers - adds audit-related endpoints.
+func AddAuditHandlers(state svcSchema.ProvidingServices) svcSchema.AddHandlersFunc {
+	return func(_, secGroup *echo.Group) error {
+		auditService, err := services.GetServiceFromProvider[schema.AuditService](state, schema.ServiceID)
+		if err != nil {
+			return fmt.Errorf("error adding audit handlers: %w", err)
+		}
+
+		userService, err := services.GetServiceFromProvider[usersSchema.UserService](state, usersSchema.ServiceID)
+		if err != nil {
+			return fmt.Errorf("error adding audit handlers: %w", err)
+		}
+
+		secGroup.GET("/admin/audit", handleListEntries(auditService), auth.RequireAdmin(userService))
+
+		return nil
+	}
+}
+
+func handleListEntries(aud schema.AuditService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		page, err := aud.ListEntries(c.Request().Context(), c.QueryParams())
+		if err != nil {
+			return fmt.Errorf("error listing audit log: %w", err)
+		}
+
+		return negotiation.Respond(c, http.StatusOK, page.Envelope(c.Request().URL))
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Package schema contains audit log DTOs shared with recording services
+*/
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/outcatcher/anwil/domains/core/services"
+	eventsSchema "github.com/outcatcher/anwil/domains/events/schema"
+)
+
+// Audited actions. Each recording service adds its own actions here.
+const (
+	ActionLogin          = "user.login"
+	ActionLoginFailed    = "user.login_failed"
+	ActionRegister       = "user.register"
+	ActionProfileUpdate  = "user.profile_update"
+	ActionPasswordChange = "user.password_change"
+	ActionPasswordReset  = "user.password_reset"
+	ActionDelete         = "user.delete"
+	// ActionRoleChange and ActionDisable are to be recorded with `role` and `enabled` attributes
+	// by the code changing them. There is no API changing them yet.
+	ActionRoleChange = "user.role_change"
+	ActionDisable    = "user.disable"
+)
+
+// Entry - audit log entry recorded by a service. Entries are published to the event bus.
+type Entry struct {
+	// Action is one of audited actions, e.g. ActionLogin
+	Action string `json:"action"`
+	// ActorUUID is UUID of the user doing the action, empty for anonymous actions
+	ActorUUID string `json:"actor_uuid,omitempty"`
+	// TargetUUID is UUID of the changed entity, e.g. user
+	TargetUUID string `json:"target_uuid,omitempty"`
+	// Before holds changed attributes before the action, JSON-serializable
+	Before map[string]any `json:"before,omitempty"`
+	// After holds changed attributes after the action, JSON-serializable
+	After map[string]any `json:"after,omitempty"`
+}
+
+// EventType returns type of the event recording audit log entry.
+func (Entry) EventType() string {
+	return "audit.entry"
+}
+
+// Auditor records audit log entries.
+type Auditor interface {
+	// Audit records entry with request details taken from the context.
+	Audit(ctx context.Context, entry Entry) error
+}
+
+// RequiresAuditor defines service which can use auditor attached.
+type RequiresAuditor interface {
+	UseAuditor(auditor Auditor)
+}
+
+// AuditorInject adds auditor publishing entries to the event bus to the service.
+//
+// Entries are recorded by audit service subscribed to them, so request details are kept in the context.
+func AuditorInject(consumer, provider any) error {
+	reqAuditor, provBus, err := services.ValidateArgInterfaces[
+		RequiresAuditor, eventsSchema.WithEventBus,
+	](consumer, provider)
+	if err != nil {
+		return fmt.Errorf("error injecting auditor: %w", err)
+	}
+
+	reqAuditor.UseAuditor(busAuditor{bus: provBus.EventBus()})
+
+	return nil
+}
+
+// busAuditor publishes audit log entries to the event bus.
+type busAuditor struct {
+	bus eventsSchema.Publisher
+}
+
+// Audit publishes entry to be recorded by audit service.
+func (a busAuditor) Audit(ctx context.Context, entry Entry) error {
+	if err := a.bus.Publish(ctx, entry); err != nil {
+		return fmt.Errorf("error recording audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// RequestInfo - details of the API request an action is done in.
+type RequestInfo struct {
+	IP        string
+	UserAgent string
+	RequestID string
+}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo returns context of the request with given details.
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFromContext returns details of the request. Details are empty outside of API requests, e.g. in jobs.
+func RequestInfoFromContext(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(RequestInfo)
+
+	return info
+}
+
+// Diff returns JSON attributes of before and after values which differ.
+//
+// Values are expected to be encoded as JSON objects, e.g. structs or maps.
+func Diff(before, after any) (map[string]any, map[string]any, error) {
+	beforeAttrs, err := toAttributes(before)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error building diff: %w", err)
+	}
+
+	afterAttrs, err := toAttributes(after)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error building diff: %w", err)
+	}
+
+	for key, value := range beforeAttrs {
+		if afterValue, ok := afterAttrs[key]; ok && reflect.DeepEqual(value, afterValue) {
+			delete(beforeAttrs, key)
+			delete(afterAttrs, key)
+		}
+	}
+
+	return beforeAttrs, afterAttrs, nil
+}
+
+func toAttributes(value any) (map[string]any, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding value: %w", err)
+	}
+
+	attributes := make(map[string]any)
+
+	if err := json.Unmarshal(encoded, &attributes); err != nil {
+		return nil, fmt.Errorf("error decoding attributes: %w", err)
+	}
+
+	return attributes, nil
+}
@@ -0,0 +1,44 @@
+package schema
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	type profile struct {
+		Name     string `json:"name"`
+		Currency string `json:"currency"`
+		Avatar   string `json:"avatar,omitempty"`
+	}
+
+	before, after, err := Diff(
+		profile{Name: "John", Currency: "EUR", Avatar: "a.png"},
+		profile{Name: "John", Currency: "USD", Avatar: ""},
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]any{"currency": "EUR", "avatar": "a.png"}, before)
+	require.Equal(t, map[string]any{"currency": "USD"}, after)
+}
+
+func TestDiff_notObject(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := Diff("string", map[string]any{})
+	require.Error(t, err)
+}
+
+func TestRequestInfoFromContext(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, RequestInfoFromContext(context.Background()))
+
+	info := RequestInfo{IP: "203.0.113.7", UserAgent: "agent", RequestID: "id"}
+
+	require.Equal(t, info, RequestInfoFromContext(WithRequestInfo(context.Background(), info)))
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	auditSchema "github.com/outcatcher/anwil/domains/audit/schema"
+	"github.com/outcatcher/anwil/domains/audit/service/schema"
+	"github.com/outcatcher/anwil/domains/audit/storage"
+	"github.com/outcatcher/anwil/domains/core/pagination"
+	"github.com/outcatcher/anwil/domains/core/validation"
+)
+
+// Audit records entry with request details taken from the context.
+func (s *service) Audit(ctx context.Context, entry auditSchema.Entry) error {
+	if entry.Action == "" {
+		return fmt.Errorf("%w: audit action is required", validation.ErrValidationFailed)
+	}
+
+	before, err := encodeAttributes(entry.Before)
+	if err != nil {
+		return fmt.Errorf("error recording audit entry %s: %w", entry.Action, err)
+	}
+
+	after, err := encodeAttributes(entry.After)
+	if err != nil {
+		return fmt.Errorf("error recording audit entry %s: %w", entry.Action, err)
+	}
+
+	request := auditSchema.RequestInfoFromContext(ctx)
+
+	err = s.storage.InsertEntry(ctx, storage.Entry{ //nolint:exhaustruct
+		Action:     entry.Action,
+		ActorUUID:  nullString(entry.ActorUUID),
+		TargetUUID: nullString(entry.TargetUUID),
+		IP:         request.IP,
+		UserAgent:  request.UserAgent,
+		RequestID:  request.RequestID,
+		Before:     before,
+		After:      after,
+	})
+	if err != nil {
+		return fmt.Errorf("error recording audit entry %s: %w", entry.Action, err)
+	}
+
+	return nil
+}
+
+// ListEntries returns page of audit log entries selected by list query parameters.
+func (s *service) ListEntries(ctx context.Context, params url.Values) (pagination.Page[schema.Entry], error) {
+	var page pagination.Page[schema.Entry]
+
+	query, err := s.paginator.Parse(params)
+	if err != nil {
+		return page, fmt.Errorf("error listing audit log: %w", err)
+	}
+
+	stored, err := s.storage.ListEntries(ctx, query)
+	if err != nil {
+		return page, fmt.Errorf("error listing audit log: %w", err)
+	}
+
+	entries := make([]schema.Entry, 0, len(stored))
+
+	for _, entry := range stored {
+		converted, err := toEntry(entry)
+		if err != nil {
+			return page, fmt.Errorf("error listing audit log: %w", err)
+		}
+
+		entries = append(entries, *converted)
+	}
+
+	page, err = pagination.NewPage(query, entries, entryKey)
+	if err != nil {
+		return page, fmt.Errorf("error listing audit log: %w", err)
+	}
+
+	return page, nil
+}
+
+// entryKey returns values of entry sort fields.
+func entryKey(entry schema.Entry) map[string]any {
+	return map[string]any{"uuid": entry.UUID, "created_at": entry.CreatedAt}
+}
+
+func toEntry(entry storage.Entry) (*schema.Entry, error) {
+	converted := &schema.Entry{
+		UUID:       entry.UUID,
+		Action:     entry.Action,
+		ActorUUID:  entry.ActorUUID.String,
+		TargetUUID: entry.TargetUUID.String,
+		IP:         entry.IP,
+		UserAgent:  entry.UserAgent,
+		RequestID:  entry.RequestID,
+		CreatedAt:  entry.CreatedAt,
+	}
+
+	if err := json.Unmarshal([]byte(entry.Before), &converted.Before); err != nil {
+		return nil, fmt.Errorf("error decoding audit entry %s: %w", entry.UUID, err)
+	}
+
+	if err := json.Unmarshal([]byte(entry.After), &converted.After); err != nil {
+		return nil, fmt.Errorf("error decoding audit entry %s: %w", entry.UUID, err)
+	}
+
+	return converted, nil
+}
+
+// encodeAttributes encodes changed attributes as JSON object.
+func encodeAttributes(attributes map[string]any) (string, error) {
+	if len(attributes) == 0 {
+		return "{}", nil
+	}
+
+	encoded, err := json.Marshal(attributes)
+	if err != nil {
+		return "", fmt.Errorf("error encoding attributes: %w", err)
+	}
+
+	return string(encoded), nil
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/outcatcher/anwil/domains/audit/service/schema"
+	"github.com/outcatcher/anwil/domains/core/services"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+)
+
+const (
+	pruneJobKind  = "audit.prune"
+	pruneSchedule = "@daily"
+)
+
+// addAuditJobs registers audit service jobs.
+func addAuditJobs(state svcSchema.ProvidingServices) svcSchema.AddJobsFunc {
+	return func(registry svcSchema.JobRegistry) error {
+		svc, err := services.GetServiceFromProvider[*service](state, schema.ServiceID)
+		if err != nil {
+			return fmt.Errorf("error adding audit jobs: %w", err)
+		}
+
+		if err := registry.Handle(pruneJobKind, svc.prune); err != nil {
+			return fmt.Errorf("error adding audit jobs: %w", err)
+		}
+
+		if err := registry.Schedule(pruneJobKind, pruneSchedule); err != nil {
+			return fmt.Errorf("error adding audit jobs: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// prune removes entries older than retention.
+func (s *service) prune(ctx context.Context, _ []byte) error {
+	deleted, err := s.storage.DeleteEntries(ctx, time.Now().Add(-s.retention))
+	if err != nil {
+		return fmt.Errorf("error pruning audit log: %w", err)
+	}
+
+	if deleted > 0 {
+		s.log.Printf("%d audit log entries pruned", deleted)
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+/*
+Package schema contains service definition for Audit service
+*/
+package schema
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/outcatcher/anwil/domains/core/pagination"
+	"github.com/outcatcher/anwil/domains/core/services/schema"
+)
+
+// ServiceID - ID for audit service.
+const ServiceID schema.ServiceID = "audit"
+
+// AuditService - service handling audit log.
+type AuditService interface {
+	// ListEntries returns page of audit log entries selected by list query parameters.
+	ListEntries(ctx context.Context, params url.Values) (pagination.Page[Entry], error)
+}
+
+// Entry - recorded audit log entry.
+type Entry struct {
+	UUID       string         `json:"uuid"`
+	Action     string         `json:"action"`
+	ActorUUID  string         `json:"actor_uuid,omitempty"`
+	TargetUUID string         `json:"target_uuid,omitempty"`
+	IP         string         `json:"ip,omitempty"`
+	UserAgent  string         `json:"user_agent,omitempty"`
+	RequestID  string         `json:"request_id,omitempty"`
+	Before     map[string]any `json:"before,omitempty"`
+	After      map[string]any `json:"after,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
@@ -0,0 +1,103 @@
+/*
+Package service contains audit service methods
+*/
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/outcatcher/anwil/domains/audit/handlers"
+	"github.com/outcatcher/anwil/domains/audit/service/schema"
+	auditStorage "github.com/outcatcher/anwil/domains/audit/storage"
+	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
+	logSchema "github.com/outcatcher/anwil/domains/core/logging/schema"
+	"github.com/outcatcher/anwil/domains/core/pagination"
+	"github.com/outcatcher/anwil/domains/core/services"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/outcatcher/anwil/domains/events"
+	eventsSchema "github.com/outcatcher/anwil/domains/events/schema"
+	storageSchema "github.com/outcatcher/anwil/domains/storage/schema"
+)
+
+const defaultRetention = 365 * 24 * time.Hour
+
+// service - audit service.
+type service struct {
+	cfg     *configSchema.Configuration
+	storage auditStorage.AuditStorage
+
+	log *log.Logger
+	bus eventsSchema.Bus
+
+	paginator *pagination.Paginator
+	retention time.Duration
+}
+
+// UseConfig attaches configuration to the service.
+func (s *service) UseConfig(configuration *configSchema.Configuration) {
+	s.cfg = configuration
+}
+
+// UseStorage attaches given DB storage to the service.
+func (s *service) UseStorage(db storageSchema.QueryExecutor) {
+	s.storage = auditStorage.New(db)
+}
+
+// UseLogger attaches logger to the service.
+func (s *service) UseLogger(logger *log.Logger) {
+	s.log = logger
+}
+
+// UseEventBus attaches domain event bus to the service.
+func (s *service) UseEventBus(bus eventsSchema.Bus) {
+	s.bus = bus
+}
+
+func auditServiceInit(_ context.Context, state any) (any, error) {
+	svc := new(service)
+
+	err := services.InjectServiceWith(
+		svc, state,
+		storageSchema.StorageInject,
+		logSchema.LoggerInject,
+		configSchema.ConfigInject,
+		eventsSchema.EventBusInject,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing audit service: %w", err)
+	}
+
+	// entries are recorded synchronously, so request details are still in the context
+	events.Subscribe(svc.bus, eventsSchema.Sync, svc.Audit)
+
+	key, err := svc.cfg.GetPrivateKey()
+	if err != nil {
+		return nil, fmt.Errorf("error initializing audit service: %w", err)
+	}
+
+	svc.paginator, err = pagination.New(auditStorage.ListSpec, key)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing audit service: %w", err)
+	}
+
+	svc.retention = svc.cfg.Audit.Retention
+	if svc.retention <= 0 {
+		svc.retention = defaultRetention
+	}
+
+	return svc, nil
+}
+
+// NewAuditService returns new audit service definition.
+func NewAuditService() svcSchema.ServiceDefinition {
+	return svcSchema.ServiceDefinition{
+		ID:               schema.ServiceID,
+		Init:             auditServiceInit,
+		DependsOn:        nil,
+		InitHandlersFunc: handlers.AddAuditHandlers,
+		InitJobsFunc:     addAuditJobs,
+	}
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"log"
+	"net/url"
+	"testing"
+	"time"
+
+	auditSchema "github.com/outcatcher/anwil/domains/audit/schema"
+	"github.com/outcatcher/anwil/domains/audit/storage"
+	"github.com/outcatcher/anwil/domains/core/pagination"
+	th "github.com/outcatcher/anwil/domains/core/testhelpers"
+	"github.com/outcatcher/anwil/domains/core/validation"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockStorage struct {
+	mock.Mock
+}
+
+func (m *mockStorage) InsertEntry(ctx context.Context, entry storage.Entry) error {
+	return m.Called(ctx, entry).Error(0)
+}
+
+func (m *mockStorage) ListEntries(ctx context.Context, query pagination.Query) ([]storage.Entry, error) {
+	args := m.Called(ctx, query)
+
+	return args.Get(0).([]storage.Entry), args.Error(1) //nolint:forcetypeassert
+}
+
+func (m *mockStorage) DeleteEntries(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+
+	return args.Get(0).(int64), args.Error(1) //nolint:forcetypeassert
+}
+
+func newTestService(t *testing.T, store storage.AuditStorage) *service {
+	t.Helper()
+
+	paginator, err := pagination.New(storage.ListSpec, []byte("test key"))
+	require.NoError(t, err)
+
+	return &service{
+		storage:   store,
+		log:       log.Default(),
+		paginator: paginator,
+		retention: defaultRetention,
+	}
+}
+
+func TestAudit(t *testing.T) {
+	t.Parallel()
+
+	userUUID := th.RandomString("uuid-", 10)
+
+	ctx := auditSchema.WithRequestInfo(context.Background(), auditSchema.RequestInfo{
+		IP:        "203.0.113.7",
+		UserAgent: "agent",
+		RequestID: "request",
+	})
+
+	store := new(mockStorage)
+	store.
+		On("InsertEntry", ctx, storage.Entry{ //nolint:exhaustruct
+			Action:     auditSchema.ActionProfileUpdate,
+			ActorUUID:  nullString(userUUID),
+			TargetUUID: nullString(userUUID),
+			IP:         "203.0.113.7",
+			UserAgent:  "agent",
+			RequestID:  "request",
+			Before:     `{"currency":"EUR"}`,
+			After:      "{}",
+		}).
+		Return(nil)
+
+	err := newTestService(t, store).Audit(ctx, auditSchema.Entry{
+		Action:     auditSchema.ActionProfileUpdate,
+		ActorUUID:  userUUID,
+		TargetUUID: userUUID,
+		Before:     map[string]any{"currency": "EUR"},
+		After:      nil,
+	})
+	require.NoError(t, err)
+
+	store.AssertNumberOfCalls(t, "InsertEntry", 1)
+}
+
+func TestAudit_noAction(t *testing.T) {
+	t.Parallel()
+
+	err := newTestService(t, new(mockStorage)).Audit(context.Background(), auditSchema.Entry{}) //nolint:exhaustruct
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+}
+
+func TestListEntries(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	params := url.Values{"action": {auditSchema.ActionLogin}, "limit": {"1"}}
+
+	stored := []storage.Entry{
+		{ //nolint:exhaustruct
+			UUID:      "00000000-0000-0000-0000-000000000002",
+			Action:    auditSchema.ActionLogin,
+			Before:    "{}",
+			After:     `{"username":"john"}`,
+			CreatedAt: time.Now(),
+		},
+		{ //nolint:exhaustruct
+			UUID:      "00000000-0000-0000-0000-000000000001",
+			Action:    auditSchema.ActionLogin,
+			Before:    "{}",
+			After:     "{}",
+			CreatedAt: time.Now().Add(-time.Minute),
+		},
+	}
+
+	store := new(mockStorage)
+	store.
+		On("ListEntries", ctx, mock.MatchedBy(func(query pagination.Query) bool {
+			clause := query.Build(1)
+
+			return query.Limit() == 1 && clause.Where == "action = $1" && clause.Args[0] == auditSchema.ActionLogin
+		})).
+		Return(stored, nil)
+
+	svc := newTestService(t, store)
+
+	page, err := svc.ListEntries(ctx, params)
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	require.Equal(t, "john", page.Items[0].After["username"])
+	require.Empty(t, page.Items[0].Before)
+	require.NotEmpty(t, page.Next)
+
+	_, err = svc.ListEntries(ctx, url.Values{"actor": {"not-uuid"}})
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+}
+
+func TestPrune(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	store := new(mockStorage)
+	store.
+		On("DeleteEntries", ctx, mock.MatchedBy(func(before time.Time) bool {
+			return before.Before(time.Now().Add(-defaultRetention + time.Minute))
+		})).
+		Return(int64(3), nil)
+
+	require.NoError(t, newTestService(t, store).prune(ctx, nil))
+
+	store.AssertNumberOfCalls(t, "DeleteEntries", 1)
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/outcatcher/anwil/domains/core/pagination"
+)
+
+// Entry - entity of `audit_log` table.
+type Entry struct {
+	UUID       string         `db:"uuid"`
+	Action     string         `db:"action"`
+	ActorUUID  sql.NullString `db:"actor_uuid"`
+	TargetUUID sql.NullString `db:"target_uuid"`
+	IP         string         `db:"ip"`
+	UserAgent  string         `db:"user_agent"`
+	RequestID  string         `db:"request_id"`
+	// Before and After are JSON-encoded objects
+	Before    string    `db:"before"`
+	After     string    `db:"after"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// ListSpec - fields of audit log entries available in list queries.
+var ListSpec = pagination.Spec{ //nolint:gochecknoglobals
+	Fields: map[string]pagination.Field{
+		"uuid": {Column: "uuid", Parse: pagination.ParseUUID, Operators: nil, Sortable: true},
+		"action": {
+			Column:    "action",
+			Parse:     nil,
+			Operators: []pagination.Operator{pagination.Eq, pagination.Ne},
+			Sortable:  false,
+		},
+		"actor": {
+			Column:    "actor_uuid",
+			Parse:     pagination.ParseUUID,
+			Operators: []pagination.Operator{pagination.Eq},
+			Sortable:  false,
+		},
+		"target": {
+			Column:    "target_uuid",
+			Parse:     pagination.ParseUUID,
+			Operators: []pagination.Operator{pagination.Eq},
+			Sortable:  false,
+		},
+		"created_at": {
+			Column:    "created_at",
+			Parse:     pagination.ParseTime,
+			Operators: []pagination.Operator{pagination.Gt, pagination.Gte, pagination.Lt, pagination.Lte},
+			Sortable:  true,
+		},
+	},
+	Key:          "uuid",
+	DefaultSort:  "-created_at",
+	DefaultLimit: 50,
+	MaxLimit:     500,
+}
@@ -0,0 +1,74 @@
+/*
+Package storage contains db-related operations with audit log.
+*/
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/outcatcher/anwil/domains/core/pagination"
+	storageSchema "github.com/outcatcher/anwil/domains/storage/schema"
+)
+
+// auditStorage - append-only storage of audit log entries.
+type auditStorage struct {
+	db storageSchema.QueryExecutor
+}
+
+// New creates a new AuditStorage instance.
+func New(db storageSchema.QueryExecutor) AuditStorage {
+	return &auditStorage{db: db}
+}
+
+// InsertEntry stores new audit log entry.
+func (a *auditStorage) InsertEntry(ctx context.Context, entry Entry) error {
+	_, err := a.db.ExecContext(
+		ctx,
+		`INSERT INTO audit_log (action, actor_uuid, target_uuid, ip, user_agent, request_id, before, after)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8);`,
+		entry.Action, entry.ActorUUID, entry.TargetUUID, entry.IP, entry.UserAgent, entry.RequestID,
+		entry.Before, entry.After,
+	)
+	if err != nil {
+		return fmt.Errorf("error inserting audit log entry: %w", err)
+	}
+
+	return nil
+}
+
+// ListEntries returns entries selected by the list query.
+func (a *auditStorage) ListEntries(ctx context.Context, query pagination.Query) ([]Entry, error) {
+	var entries []Entry
+
+	clause := query.Build(1)
+
+	err := sqlx.SelectContext(
+		ctx, a.db, &entries,
+		// clauses contain column names from the spec and placeholders only
+		`SELECT * FROM audit_log WHERE `+clause.Where+` `+clause.OrderLimit+`;`, //nolint:gosec
+		clause.Args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting audit log entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// DeleteEntries removes entries recorded before given time.
+func (a *auditStorage) DeleteEntries(ctx context.Context, before time.Time) (int64, error) {
+	result, err := a.db.ExecContext(ctx, `DELETE FROM audit_log WHERE created_at < $1;`, before)
+	if err != nil {
+		return 0, fmt.Errorf("error deleting audit log entries: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error deleting audit log entries: %w", err)
+	}
+
+	return affected, nil
+}
@@ -0,0 +1,17 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/outcatcher/anwil/domains/core/pagination"
+)
+
+// AuditStorage - append-only storage of audit log entries.
+type AuditStorage interface {
+	InsertEntry(ctx context.Context, entry Entry) error
+	// ListEntries returns entries selected by the list query.
+	ListEntries(ctx context.Context, query pagination.Query) ([]Entry, error)
+	// DeleteEntries removes entries recorded before given time.
+	DeleteEntries(ctx context.Context, before time.Time) (int64, error)
+}
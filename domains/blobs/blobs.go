@@ -0,0 +1,39 @@
+package blobs
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/outcatcher/anwil/domains/blobs/schema"
+	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
+)
+
+// Supported blob store drivers.
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+var errUnknownDriver = errors.New("unknown blob store driver")
+
+// New creates blob store using configured driver.
+func New(cfg configSchema.BlobsConfiguration) (schema.BlobStore, error) {
+	switch cfg.Driver {
+	case "", DriverLocal:
+		store, err := newLocalStore(cfg.Directory)
+		if err != nil {
+			return nil, fmt.Errorf("error creating local blob store: %w", err)
+		}
+
+		return store, nil
+	case DriverS3:
+		store, err := newS3Store(cfg.S3)
+		if err != nil {
+			return nil, fmt.Errorf("error creating S3 blob store: %w", err)
+		}
+
+		return store, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownDriver, cfg.Driver)
+	}
+}
@@ -0,0 +1,199 @@
+package blobs
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/outcatcher/anwil/domains/blobs/schema"
+	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	th "github.com/outcatcher/anwil/domains/core/testhelpers"
+	"github.com/outcatcher/anwil/domains/core/validation"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testBucket    = "anwil"
+	testAccessKey = "access"
+	testSecretKey = "secret"
+)
+
+type fakeObject struct {
+	contentType string
+	data        []byte
+}
+
+// fakeS3 - minimal in-memory stand-in of S3-compatible storage verifying request signatures.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]fakeObject
+}
+
+// verifySignature re-signs request with the same date, comparing signatures.
+func verifySignature(r *http.Request) bool {
+	signedAt, err := time.Parse(sigV4TimeFormat, r.Header.Get(headerDate))
+	if err != nil {
+		return false
+	}
+
+	authorization := r.Header.Get("Authorization")
+
+	_, signedPart, ok := strings.Cut(authorization, "SignedHeaders=")
+	if !ok {
+		return false
+	}
+
+	signedList, _, _ := strings.Cut(signedPart, ",")
+
+	copied, err := http.NewRequest(r.Method, "http://"+r.Host+r.URL.RequestURI(), nil)
+	if err != nil {
+		return false
+	}
+
+	for _, name := range strings.Split(signedList, ";") {
+		if name != "host" && name != "x-amz-date" {
+			copied.Header.Set(name, r.Header.Get(name))
+		}
+	}
+
+	signRequest(copied, credentials{accessKey: testAccessKey, secretKey: testSecretKey, region: "us-east-1"}, signedAt)
+
+	return copied.Header.Get("Authorization") == authorization
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !verifySignature(r) {
+		w.WriteHeader(http.StatusForbidden)
+
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, "/"+testBucket+"/")
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+
+		if hashHex(data) != r.Header.Get(headerContentSHA) {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		f.objects[key] = fakeObject{contentType: r.Header.Get("Content-Type"), data: data}
+	case http.MethodGet:
+		object, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", object.contentType)
+		_, _ = w.Write(object.data)
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func testStores(t *testing.T) map[string]schema.BlobStore {
+	t.Helper()
+
+	server := httptest.NewServer(&fakeS3{objects: make(map[string]fakeObject)})
+	t.Cleanup(server.Close)
+
+	local, err := New(configSchema.BlobsConfiguration{Driver: DriverLocal, Directory: t.TempDir()})
+	require.NoError(t, err)
+
+	s3, err := New(configSchema.BlobsConfiguration{
+		Driver: DriverS3,
+		S3: configSchema.S3Configuration{
+			Endpoint:  server.URL,
+			Bucket:    testBucket,
+			AccessKey: testAccessKey,
+			SecretKey: testSecretKey,
+		},
+	})
+	require.NoError(t, err)
+
+	return map[string]schema.BlobStore{DriverLocal: local, DriverS3: s3}
+}
+
+func TestBlobStores(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	for name, store := range testStores(t) {
+		store := store
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			key := "avatars/" + th.RandomString("", 10) + "/image.png"
+			data := []byte(th.RandomString("data-", 100))
+
+			require.NoError(t, store.Put(ctx, key, "image/png", data))
+
+			blob, err := store.Get(ctx, key)
+			require.NoError(t, err)
+
+			stored, err := io.ReadAll(blob)
+			require.NoError(t, err)
+			require.NoError(t, blob.Close())
+
+			require.Equal(t, data, stored)
+			require.Equal(t, "image/png", blob.ContentType)
+			require.EqualValues(t, len(data), blob.Size)
+
+			require.NoError(t, store.Delete(ctx, key))
+			require.NoError(t, store.Delete(ctx, key))
+
+			_, err = store.Get(ctx, key)
+			require.ErrorIs(t, err, errbase.ErrNotFound)
+
+			require.ErrorIs(t, store.Put(ctx, "../escape", "text/plain", data), validation.ErrValidationFailed)
+		})
+	}
+}
+
+func TestS3Store_invalidCredentials(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(&fakeS3{objects: make(map[string]fakeObject)})
+	t.Cleanup(server.Close)
+
+	store, err := New(configSchema.BlobsConfiguration{
+		Driver: DriverS3,
+		S3:     configSchema.S3Configuration{Endpoint: server.URL, Bucket: testBucket, AccessKey: testAccessKey},
+	})
+	require.NoError(t, err)
+
+	err = store.Put(context.Background(), "key", "text/plain", []byte("data"))
+	require.ErrorIs(t, err, errS3Response)
+}
+
+func TestValidateKey(t *testing.T) {
+	t.Parallel()
+
+	valid := []string{"a", "a/b.png", "avatars/uuid/1.jpg"}
+	invalid := []string{"", "/a", "a/../b", "../a", "..", "a/", "a//b", "./a", "a\\b"}
+
+	for _, key := range valid {
+		require.NoError(t, ValidateKey(key), key)
+	}
+
+	for _, key := range invalid {
+		require.ErrorIs(t, ValidateKey(key), validation.ErrValidationFailed, key)
+	}
+}
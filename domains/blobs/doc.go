@@ -0,0 +1,4 @@
+/*
+Package blobs contains storage of uploaded files.
+*/
+package blobs
@@ -0,0 +1,60 @@
+/*
+Package handlers contains API handlers serving stored blobs.
+*/
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/outcatcher/anwil/domains/blobs"
+	"github.com/outcatcher/anwil/domains/blobs/schema"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+)
+
+// blobs have unique keys, so they can be cached forever
+const cacheControl = "public, max-age=31536000, immutable"
+
+// AddBlobHandlers - adds endpoint serving public blobs, i.e. uploaded avatars.
+func AddBlobHandlers(store schema.BlobStore) svcSchema.AddHandlersFunc {
+	return func(baseGroup, _ *echo.Group) error {
+		baseGroup.GET("/blobs/*", handleGetBlob(store))
+
+		return nil
+	}
+}
+
+func handleGetBlob(store schema.BlobStore) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		key := c.Param("*")
+
+		if err := blobs.ValidateKey(key); err != nil {
+			return fmt.Errorf("error getting blob: %w", err)
+		}
+
+		blob, err := store.Get(c.Request().Context(), key)
+		if err != nil {
+			return fmt.Errorf("error getting blob: %w", err)
+		}
+
+		defer blob.Close()
+
+		header := c.Response().Header()
+		header.Set(echo.HeaderCacheControl, cacheControl)
+		header.Set(echo.HeaderXContentTypeOptions, "nosniff")
+
+		if blob.Size >= 0 {
+			header.Set(echo.HeaderContentLength, strconv.FormatInt(blob.Size, 10))
+		}
+
+		contentType := blob.ContentType
+		if contentType == "" {
+			contentType = echo.MIMEOctetStream
+		}
+
+		return c.Stream(http.StatusOK, contentType, io.Reader(blob)) //nolint:wrapcheck
+	}
+}
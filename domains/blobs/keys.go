@@ -0,0 +1,19 @@
+package blobs
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/outcatcher/anwil/domains/core/validation"
+)
+
+// ValidateKey checks that key is a clean relative slash-separated path.
+func ValidateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key ||
+		key == ".." || strings.HasPrefix(key, "../") || strings.ContainsAny(key, "\\\x00") {
+		return fmt.Errorf("%w: invalid blob key %q", validation.ErrValidationFailed, key)
+	}
+
+	return nil
+}
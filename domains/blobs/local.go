@@ -0,0 +1,119 @@
+package blobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+
+	"github.com/outcatcher/anwil/domains/blobs/schema"
+	"github.com/outcatcher/anwil/domains/core/errbase"
+)
+
+const (
+	dirPermissions  = 0o750
+	filePermissions = 0o640
+)
+
+// localStore stores blobs in local directory.
+//
+// Content type is not stored and is detected by the key extension.
+type localStore struct {
+	dir string
+}
+
+// defaultDirectory - directory used if none is configured.
+const defaultDirectory = "blobs"
+
+func newLocalStore(dir string) (*localStore, error) {
+	if dir == "" {
+		dir = defaultDirectory
+	}
+
+	if err := os.MkdirAll(filepath.Clean(dir), dirPermissions); err != nil {
+		return nil, fmt.Errorf("error creating blob directory: %w", err)
+	}
+
+	return &localStore{dir: dir}, nil
+}
+
+func (s *localStore) path(key string) (string, error) {
+	if err := ValidateKey(key); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+// Put stores blob, replacing existing one with the same key.
+//
+// Blob is written to temporary file first, so partially written blobs are never read.
+func (s *localStore) Put(_ context.Context, key, _ string, data []byte) error {
+	blobPath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(blobPath), dirPermissions); err != nil {
+		return fmt.Errorf("error creating blob directory: %w", err)
+	}
+
+	tmpPath := blobPath + ".tmp"
+
+	if err := os.WriteFile(tmpPath, data, filePermissions); err != nil {
+		return fmt.Errorf("error writing blob %s: %w", key, err)
+	}
+
+	if err := os.Rename(tmpPath, blobPath); err != nil {
+		return fmt.Errorf("error writing blob %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// Get returns blob contents.
+func (s *localStore) Get(_ context.Context, key string) (*schema.Blob, error) {
+	blobPath, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filepath.Clean(blobPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("blob %s: %w", key, errbase.ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error opening blob %s: %w", key, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+
+		return nil, fmt.Errorf("error opening blob %s: %w", key, err)
+	}
+
+	return &schema.Blob{
+		ReadCloser:  file,
+		ContentType: mime.TypeByExtension(filepath.Ext(blobPath)),
+		Size:        info.Size(),
+	}, nil
+}
+
+// Delete removes blob.
+func (s *localStore) Delete(_ context.Context, key string) error {
+	blobPath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(blobPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error deleting blob %s: %w", key, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package blobs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/outcatcher/anwil/domains/blobs/schema"
+	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
+	"github.com/outcatcher/anwil/domains/core/errbase"
+)
+
+const s3RequestTimeout = time.Minute
+
+// maxErrorBodySize limits S3 error response included in errors.
+const maxErrorBodySize = 1024
+
+var (
+	errS3Misconfigured = errors.New("S3 endpoint and bucket are required")
+	errS3Response      = errors.New("unexpected S3 response")
+)
+
+// s3Store stores blobs in S3-compatible object storage, addressing bucket by path.
+type s3Store struct {
+	endpoint *url.URL
+	bucket   string
+	creds    credentials
+	client   *http.Client
+}
+
+func newS3Store(cfg configSchema.S3Configuration) (*s3Store, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, errS3Misconfigured
+	}
+
+	endpoint, err := url.Parse(strings.TrimSuffix(cfg.Endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing S3 endpoint: %w", err)
+	}
+
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	return &s3Store{
+		endpoint: endpoint,
+		bucket:   cfg.Bucket,
+		creds:    credentials{accessKey: cfg.AccessKey, secretKey: cfg.SecretKey, region: region},
+		client:   &http.Client{Timeout: s3RequestTimeout}, //nolint:exhaustruct
+	}, nil
+}
+
+// newRequest creates signed request to the object with given key.
+func (s *s3Store) newRequest(
+	ctx context.Context, method, key string, body []byte, headers map[string]string,
+) (*http.Request, error) {
+	if err := ValidateKey(key); err != nil {
+		return nil, err
+	}
+
+	objectURL := *s.endpoint
+	objectURL.Path = strings.TrimSuffix(objectURL.Path, "/") + "/" + s.bucket + "/" + key
+
+	req, err := http.NewRequestWithContext(ctx, method, objectURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error creating S3 request: %w", err)
+	}
+
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	req.Header.Set(headerContentSHA, hashHex(body))
+
+	signRequest(req, s.creds, time.Now())
+
+	return req, nil
+}
+
+func (s *s3Store) do(req *http.Request, expectedStatuses ...int) (*http.Response, error) {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending S3 request: %w", err)
+	}
+
+	for _, status := range expectedStatuses {
+		if resp.StatusCode == status {
+			return resp, nil
+		}
+	}
+
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("object %s: %w", req.URL.Path, errbase.ErrNotFound)
+	}
+
+	return nil, fmt.Errorf("%w: %s %s: %s", errS3Response, req.Method, resp.Status, body)
+}
+
+// Put stores blob, replacing existing one with the same key.
+func (s *s3Store) Put(ctx context.Context, key, contentType string, data []byte) error {
+	req, err := s.newRequest(ctx, http.MethodPut, key, data, map[string]string{"Content-Type": contentType})
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req, http.StatusOK)
+	if err != nil {
+		return fmt.Errorf("error putting blob %s: %w", key, err)
+	}
+
+	return resp.Body.Close() //nolint:wrapcheck
+}
+
+// Get returns blob contents.
+func (s *s3Store) Get(ctx context.Context, key string) (*schema.Blob, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(req, http.StatusOK)
+	if err != nil {
+		return nil, fmt.Errorf("error getting blob %s: %w", key, err)
+	}
+
+	return &schema.Blob{
+		ReadCloser:  resp.Body,
+		ContentType: resp.Header.Get("Content-Type"),
+		Size:        resp.ContentLength,
+	}, nil
+}
+
+// Delete removes blob. S3 reports success for missing objects.
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req, http.StatusNoContent, http.StatusOK)
+	if err != nil {
+		return fmt.Errorf("error deleting blob %s: %w", key, err)
+	}
+
+	return resp.Body.Close() //nolint:wrapcheck
+}
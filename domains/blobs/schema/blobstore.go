@@ -0,0 +1,55 @@
+/*
+Package schema contains blob storage DTOs
+*/
+package schema
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/outcatcher/anwil/domains/core/services"
+)
+
+// Blob - stored file contents.
+type Blob struct {
+	io.ReadCloser
+
+	ContentType string
+	Size        int64
+}
+
+// BlobStore stores files by keys.
+//
+// Keys are slash-separated paths, i.e. `avatars/<uuid>/<name>.jpg`.
+// Missing blobs are reported with errbase.ErrNotFound.
+type BlobStore interface {
+	// Put stores blob, replacing existing one with the same key.
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	// Get returns blob contents. Returned blob must be closed.
+	Get(ctx context.Context, key string) (*Blob, error)
+	// Delete removes blob. Removing missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// WithBlobStore defines service or state having blob store attached.
+type WithBlobStore interface {
+	BlobStore() BlobStore
+}
+
+// RequiresBlobStore defines service which can use blob store attached.
+type RequiresBlobStore interface {
+	UseBlobStore(store BlobStore)
+}
+
+// BlobStoreInject adds blob store to the service.
+func BlobStoreInject(consumer, provider any) error {
+	reqStore, provStore, err := services.ValidateArgInterfaces[RequiresBlobStore, WithBlobStore](consumer, provider)
+	if err != nil {
+		return fmt.Errorf("error injecting blob store: %w", err)
+	}
+
+	reqStore.UseBlobStore(provStore.BlobStore())
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package blobs
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	sigV4TimeFormat = "20060102T150405Z"
+	sigV4DateFormat = "20060102"
+	sigV4Service    = "s3"
+
+	headerContentSHA = "X-Amz-Content-Sha256"
+	headerDate       = "X-Amz-Date"
+)
+
+// emptyPayloadHash - SHA256 of empty payload.
+var emptyPayloadHash = hashHex(nil)
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+
+	_, _ = mac.Write([]byte(data)) // hash writes never fail
+
+	return mac.Sum(nil)
+}
+
+// credentials - S3 access credentials.
+type credentials struct {
+	accessKey, secretKey, region string
+}
+
+// signRequest signs request with AWS Signature Version 4.
+//
+// Host and all the headers already set are signed. Payload hash is expected to be set in
+// `x-amz-content-sha256` header.
+func signRequest(req *http.Request, creds credentials, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format(sigV4TimeFormat)
+
+	req.Header.Set(headerDate, amzDate)
+
+	if req.Header.Get(headerContentSHA) == "" {
+		req.Header.Set(headerContentSHA, emptyPayloadHash)
+	}
+
+	signedHeaders, canonicalHeaders := canonicalHeaders(req)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL),
+		canonicalQuery(req.URL),
+		canonicalHeaders,
+		signedHeaders,
+		req.Header.Get(headerContentSHA),
+	}, "\n")
+
+	scope := strings.Join([]string{now.Format(sigV4DateFormat), creds.region, sigV4Service, "aws4_request"}, "/")
+
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		amzDate,
+		scope,
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+creds.secretKey), now.Format(sigV4DateFormat))
+	signingKey = hmacSHA256(signingKey, creds.region)
+	signingKey = hmacSHA256(signingKey, sigV4Service)
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, creds.accessKey, scope, signedHeaders, signature,
+	))
+}
+
+func canonicalHeaders(req *http.Request) (string, string) {
+	values := map[string]string{"host": req.URL.Host}
+
+	if req.Host != "" {
+		values["host"] = req.Host
+	}
+
+	for name, headerValues := range req.Header {
+		values[strings.ToLower(name)] = strings.TrimSpace(strings.Join(headerValues, ","))
+	}
+
+	names := make([]string, 0, len(values))
+
+	for name := range values {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	builder := new(strings.Builder)
+
+	for _, name := range names {
+		builder.WriteString(name + ":" + values[name] + "\n")
+	}
+
+	return strings.Join(names, ";"), builder.String()
+}
+
+// canonicalURI returns URI-encoded path. S3 paths are not normalized and encoded once.
+func canonicalURI(u *url.URL) string {
+	if u.Path == "" {
+		return "/"
+	}
+
+	segments := strings.Split(u.Path, "/")
+
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func canonicalQuery(u *url.URL) string {
+	query := u.Query()
+
+	keys := make([]string, 0, len(query))
+
+	for key := range query {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(key)+"="+uriEncode(value))
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode encodes everything except RFC 3986 unreserved characters.
+func uriEncode(value string) string {
+	builder := new(strings.Builder)
+
+	for _, b := range []byte(value) {
+		if ('A' <= b && b <= 'Z') || ('a' <= b && b <= 'z') || ('0' <= b && b <= '9') ||
+			b == '-' || b == '_' || b == '.' || b == '~' {
+			builder.WriteByte(b)
+
+			continue
+		}
+
+		fmt.Fprintf(builder, "%%%02X", b)
+	}
+
+	return builder.String()
+}
@@ -69,6 +69,14 @@ func LoadServerConfiguration(ctx context.Context, path string) (*schema.Configur
 		return nil, fmt.Errorf("error merging configurations: %w", err)
 	}
 
+	if err := cfg.API.TLS.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid TLS configuration: %w", err)
+	}
+
+	if _, err := cfg.API.TrustedProxyNets(); err != nil {
+		return nil, fmt.Errorf("invalid API configuration: %w", err)
+	}
+
 	configFileLRU.Add(absPath, *cfg)
 
 	return cfg, nil
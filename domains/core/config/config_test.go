@@ -75,6 +75,34 @@ func TestLoadServerConfiguration(t *testing.T) {
 		require.ErrorIs(t, err, os.ErrNotExist)
 	})
 
+	t.Run("Incomplete TLS", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeCfg(t, `
+---
+api:
+  tls:
+    certPath: /etc/anwil/cert.pem
+`)
+
+		_, err := LoadServerConfiguration(ctx, path)
+		require.ErrorIs(t, err, schema.ErrIncompleteTLS)
+	})
+
+	t.Run("Invalid trusted proxy", func(t *testing.T) {
+		t.Parallel()
+
+		path := writeCfg(t, `
+---
+api:
+  trustedProxies:
+    - 10.0.0.0/33
+`)
+
+		_, err := LoadServerConfiguration(ctx, path)
+		require.Error(t, err)
+	})
+
 	t.Run("OK", func(t *testing.T) {
 		t.Parallel()
 
@@ -8,11 +8,15 @@ package schema
 import (
 	"crypto/ed25519"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/outcatcher/anwil/domains/core/services"
 )
@@ -53,17 +57,226 @@ type DatabaseConfiguration struct {
 	MigrationsDir string `yaml:"migrationsDir"`
 }
 
+// TLSConfiguration - API TLS-related configuration.
+//
+// TLS is enabled when both certificate and key paths are set, setting only one of them is an error.
+type TLSConfiguration struct {
+	CertPath string `yaml:"certPath"`
+	KeyPath  string `yaml:"keyPath"`
+	// MinVersion is a minimal accepted TLS version, i.e. "1.2" or "1.3"
+	MinVersion string `yaml:"minVersion"`
+	// ClientCAPath is a path to PEM-encoded CA certificates used to verify client certificates (mTLS).
+	// Client certificates are not requested if empty.
+	ClientCAPath string `yaml:"clientCAPath"`
+	// RedirectPort is a port for plain HTTP listener redirecting to HTTPS.
+	// Listener is not started if empty.
+	RedirectPort int `yaml:"redirectPort"`
+}
+
+// ErrIncompleteTLS is returned when only one of TLS certificate and key paths is set.
+var ErrIncompleteTLS = errors.New("both TLS certificate and key paths must be set")
+
+// Enabled returns true if TLS should be used.
+func (c TLSConfiguration) Enabled() bool {
+	return c.CertPath != "" && c.KeyPath != ""
+}
+
+// Validate checks that TLS is either fully configured or not configured at all.
+func (c TLSConfiguration) Validate() error {
+	if (c.CertPath == "") != (c.KeyPath == "") {
+		return ErrIncompleteTLS
+	}
+
+	return nil
+}
+
 // APIConfiguration - API-related configuration.
 type APIConfiguration struct {
-	Host       string `yaml:"host"`
-	Port       int    `yaml:"port"`
-	StaticPath string `yaml:"staticPath"`
+	Host       string           `yaml:"host"`
+	Port       int              `yaml:"port"`
+	StaticPath string           `yaml:"staticPath"`
+	TLS        TLSConfiguration `yaml:"tls"`
+	// PublicURL is a base URL of the API as seen by users, used for links sent outside, e.g. in emails
+	PublicURL string `yaml:"publicURL"`
+	// TrustedProxies is a list of CIDRs of reverse proxies allowed to set X-Forwarded-For header.
+	// Client IP is taken from the connection if empty.
+	TrustedProxies []string `yaml:"trustedProxies"`
+}
+
+// TrustedProxyNets parses trusted proxies CIDRs.
+func (c APIConfiguration) TrustedProxyNets() ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(c.TrustedProxies))
+
+	for _, cidr := range c.TrustedProxies {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", cidr, err)
+		}
+
+		nets = append(nets, ipNet)
+	}
+
+	return nets, nil
+}
+
+// BaseURL returns base URL used in links sent to users.
+//
+// Falls back to localhost URL if public URL is not configured.
+func (c APIConfiguration) BaseURL() string {
+	if c.PublicURL != "" {
+		return strings.TrimSuffix(c.PublicURL, "/")
+	}
+
+	scheme := "http"
+
+	if c.TLS.Enabled() {
+		scheme = "https"
+	}
+
+	return fmt.Sprintf("%s://localhost:%d", scheme, c.Port)
+}
+
+// ExportConfiguration - user data export configuration.
+type ExportConfiguration struct {
+	// Directory is a directory to store built archives in
+	Directory string `yaml:"directory"`
+	// TTL is a duration archive is available for download, i.e. "24h"
+	TTL time.Duration `yaml:"ttl"`
+}
+
+// JobsConfiguration - background jobs configuration.
+type JobsConfiguration struct {
+	// Workers is a number of jobs processed concurrently
+	Workers int `yaml:"workers"`
+	// PollInterval is a period of checking queue for new jobs, i.e. "1s"
+	PollInterval time.Duration `yaml:"pollInterval"`
+	// MaxAttempts is a default number of attempts before job is marked as failed
+	MaxAttempts int `yaml:"maxAttempts"`
+	// Timeout is a max duration of single job attempt, i.e. "10m"
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// EventsConfiguration - domain event bus configuration.
+type EventsConfiguration struct {
+	// MaxAttempts is a number of attempts to deliver event published in transaction to subscribers,
+	// jobs.maxAttempts is used if not set
+	MaxAttempts int `yaml:"maxAttempts"`
+}
+
+// AuditConfiguration - audit log configuration.
+type AuditConfiguration struct {
+	// Retention is a duration audit log entries are kept for, i.e. "8760h"
+	Retention time.Duration `yaml:"retention"`
+}
+
+// S3Configuration - S3-compatible object storage configuration.
+//
+// Note that for fields with `env` tag, environment variable value has priority over yaml value.
+type S3Configuration struct {
+	// Endpoint is a base URL of the storage, i.e. "https://s3.eu-central-1.amazonaws.com"
+	Endpoint  string `yaml:"endpoint"`
+	Region    string `yaml:"region"`
+	Bucket    string `yaml:"bucket"`
+	AccessKey string `yaml:"accessKey" env:"S3_ACCESS_KEY"`
+	SecretKey string `yaml:"secretKey" env:"S3_SECRET_KEY"`
+}
+
+// BlobsConfiguration - uploaded files storage configuration.
+type BlobsConfiguration struct {
+	// Driver is one of `local` or `s3`. Files are stored locally if empty.
+	Driver string `yaml:"driver"`
+	// Directory is a directory used by `local` driver to store files
+	Directory string          `yaml:"directory"`
+	S3        S3Configuration `yaml:"s3"`
+}
+
+// ImagesConfiguration - uploaded images configuration.
+type ImagesConfiguration struct {
+	// MaxUploadSize is a max size of uploaded image in bytes
+	MaxUploadSize int64 `yaml:"maxUploadSize"`
+	// ThumbnailSize is a max width and height of generated thumbnails in pixels
+	ThumbnailSize int `yaml:"thumbnailSize"`
+}
+
+// PreviewsConfiguration - link preview scraper configuration.
+type PreviewsConfiguration struct {
+	// Timeout is a max duration of fetching single page, i.e. "5s"
+	Timeout time.Duration `yaml:"timeout"`
+	// MaxBodySize is a max number of page bytes read
+	MaxBodySize int64 `yaml:"maxBodySize"`
+	// CacheTTL is a duration fetched previews are cached for, i.e. "1h"
+	CacheTTL time.Duration `yaml:"cacheTTL"`
+	// CacheSize is a max number of cached previews
+	CacheSize int `yaml:"cacheSize"`
+}
+
+// WebhooksConfiguration - outgoing webhooks configuration.
+type WebhooksConfiguration struct {
+	// Timeout is a max duration of single delivery request, i.e. "10s"
+	Timeout time.Duration `yaml:"timeout"`
+	// AllowPrivate allows webhook URLs in private networks, e.g. for home automation in the same LAN
+	AllowPrivate bool `yaml:"allowPrivate"`
+}
+
+// CurrencyConfiguration - currencies and exchange rates configuration.
+type CurrencyConfiguration struct {
+	// Default is a currency used for users without preferred currency, i.e. "EUR"
+	Default string `yaml:"default"`
+	// Provider is one of `file` or `http`. Rates are loaded from file if empty.
+	// Only default currency is available if neither provider nor file is set.
+	Provider string `yaml:"provider"`
+	// File is a path to JSON or YAML file with rates, used by `file` provider
+	File string `yaml:"file"`
+	// URL is an address of rates API, used by `http` provider
+	URL string `yaml:"url"`
+	// Schedule is a schedule of rates refresh, i.e. "@daily"
+	Schedule string `yaml:"schedule"`
+}
+
+// DefaultCurrency returns configured default currency, falling back to EUR.
+func (c CurrencyConfiguration) DefaultCurrency() string {
+	if c.Default != "" {
+		return strings.ToUpper(c.Default)
+	}
+
+	return "EUR"
+}
+
+// SMTPConfiguration - SMTP server configuration.
+//
+// Note that for fields with `env` tag, environment variable value has priority over yaml value.
+type SMTPConfiguration struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password" env:"SMTP_PASSWORD"`
+}
+
+// MailConfiguration - outgoing mail configuration.
+type MailConfiguration struct {
+	// Driver is one of `smtp`, `file` or `log`. Messages are written to the log if empty.
+	Driver string `yaml:"driver"`
+	// From is a sender address of the messages
+	From string            `yaml:"from"`
+	SMTP SMTPConfiguration `yaml:"smtp"`
+	// Directory is a directory used by `file` driver to store messages
+	Directory string `yaml:"directory"`
 }
 
 // Configuration - overall system configuration.
 type Configuration struct {
 	API            APIConfiguration      `yaml:"api"`
 	DB             DatabaseConfiguration `yaml:"db"`
+	Mail           MailConfiguration     `yaml:"mail"`
+	Export         ExportConfiguration   `yaml:"export"`
+	Jobs           JobsConfiguration     `yaml:"jobs"`
+	Events         EventsConfiguration   `yaml:"events"`
+	Audit          AuditConfiguration    `yaml:"audit"`
+	Blobs          BlobsConfiguration    `yaml:"blobs"`
+	Images         ImagesConfiguration   `yaml:"images"`
+	Previews       PreviewsConfiguration `yaml:"previews"`
+	Currency       CurrencyConfiguration `yaml:"currency"`
+	Webhooks       WebhooksConfiguration `yaml:"webhooks"`
 	PrivateKeyPath string                `yaml:"privateKeyPath"`
 	Debug          bool                  `yaml:"debug"`
 
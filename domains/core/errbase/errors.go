@@ -16,4 +16,12 @@ var (
 	ErrUnauthorized = errors.New("not authorized")
 	// ErrConflict - error for conflicting data.
 	ErrConflict = errors.New("conflict")
+	// ErrTooLarge - error for input exceeding size limits.
+	ErrTooLarge = errors.New("too large")
+	// ErrUnsupportedMediaType - error for input of not supported type.
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	// ErrNotAcceptable - error for requested output type not being supported.
+	ErrNotAcceptable = errors.New("not acceptable")
+	// ErrUpstream - error for failures of external services.
+	ErrUpstream = errors.New("upstream failure")
 )
@@ -0,0 +1,49 @@
+package negotiation
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// structTag is a tag used for field names in all structured formats.
+const structTag = "json"
+
+// Binder binds request data to structures.
+//
+// In addition to formats supported by echo.DefaultBinder, MessagePack body is supported.
+// MessagePack uses the same field names as JSON.
+type Binder struct {
+	echo.DefaultBinder
+}
+
+// Bind implements echo.Binder.
+func (b *Binder) Bind(i any, c echo.Context) error {
+	mType, err := mediaType(c)
+	if err != nil {
+		return err
+	}
+
+	if mType != MIMEMsgpack || c.Request().ContentLength == 0 {
+		return b.DefaultBinder.Bind(i, c) //nolint:wrapcheck
+	}
+
+	if err := b.BindPathParams(c, i); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	decoder := msgpack.NewDecoder(c.Request().Body)
+	decoder.SetCustomStructTag(structTag)
+
+	if err := decoder.Decode(i); err != nil && !errors.Is(err, io.EOF) {
+		return echo.NewHTTPError(
+			http.StatusBadRequest, fmt.Sprintf("invalid MessagePack body: %s", err),
+		).SetInternal(err)
+	}
+
+	return nil
+}
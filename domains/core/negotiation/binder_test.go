@@ -0,0 +1,102 @@
+package negotiation
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	th "github.com/outcatcher/anwil/domains/core/testhelpers"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type bindRequest struct {
+	Name  string  `json:"name" form:"name"`
+	Count int     `json:"count" form:"count"`
+	Note  *string `json:"note" form:"note"`
+}
+
+func msgpackBody(t *testing.T, data any) io.Reader {
+	t.Helper()
+
+	buf := new(bytes.Buffer)
+
+	encoder := msgpack.NewEncoder(buf)
+	encoder.SetCustomStructTag(structTag)
+
+	require.NoError(t, encoder.Encode(data))
+
+	return buf
+}
+
+func TestBinder(t *testing.T) {
+	t.Parallel()
+
+	note := "some note"
+	expected := bindRequest{Name: "wish", Count: 3, Note: &note}
+
+	cases := []struct {
+		name        string
+		contentType string
+		body        func(t *testing.T) io.Reader
+	}{
+		{
+			"json", MIMEJSON,
+			func(*testing.T) io.Reader {
+				return strings.NewReader(`{"name":"wish","count":3,"note":"some note"}`)
+			},
+		},
+		{
+			"form", MIMEForm,
+			func(*testing.T) io.Reader {
+				return strings.NewReader("name=wish&count=3&note=some+note")
+			},
+		},
+		{
+			"msgpack", MIMEMsgpack,
+			func(t *testing.T) io.Reader {
+				t.Helper()
+
+				return msgpackBody(t, map[string]any{"name": "wish", "count": 3, "note": "some note"})
+			},
+		},
+	}
+
+	for _, data := range cases {
+		data := data
+
+		t.Run(data.name, func(t *testing.T) {
+			t.Parallel()
+
+			request := httptest.NewRequest(http.MethodPost, "/", data.body(t))
+			request.Header.Set(echo.HeaderContentType, data.contentType)
+
+			echoCtx := echo.New().NewContext(request, th.ClosingRecorder(t))
+
+			result := new(bindRequest)
+
+			require.NoError(t, new(Binder).Bind(result, echoCtx))
+			require.Equal(t, expected, *result)
+		})
+	}
+}
+
+func TestBinderInvalidMsgpack(t *testing.T) {
+	t.Parallel()
+
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("\xc1"))
+	request.Header.Set(echo.HeaderContentType, MIMEMsgpack)
+
+	echoCtx := echo.New().NewContext(request, th.ClosingRecorder(t))
+
+	err := new(Binder).Bind(new(bindRequest), echoCtx)
+	require.Error(t, err)
+
+	httpErr := new(echo.HTTPError)
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusBadRequest, httpErr.Code)
+}
@@ -0,0 +1,65 @@
+package negotiation
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/outcatcher/anwil/domains/core/errbase"
+)
+
+// Supported content types.
+const (
+	MIMEJSON      = echo.MIMEApplicationJSON
+	MIMEMsgpack   = echo.MIMEApplicationMsgpack
+	MIMEForm      = echo.MIMEApplicationForm
+	MIMEMultipart = echo.MIMEMultipartForm
+)
+
+// DataTypes are content types structured request data can be sent with.
+var DataTypes = []string{MIMEJSON, MIMEMsgpack, MIMEForm, MIMEMultipart} //nolint:gochecknoglobals
+
+// mediaType returns media type of the request body without parameters.
+func mediaType(c echo.Context) (string, error) {
+	contentType := c.Request().Header.Get(echo.HeaderContentType)
+	if contentType == "" {
+		return "", nil
+	}
+
+	parsed, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid content type %q", errbase.ErrUnsupportedMediaType, contentType)
+	}
+
+	return parsed, nil
+}
+
+// Consumes restricts request body content type of the route to the given types.
+//
+// Requests without body are not restricted, whatever `Content-Type` they have.
+func Consumes(types ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Request().ContentLength == 0 {
+				return next(c)
+			}
+
+			mType, err := mediaType(c)
+			if err != nil {
+				return err
+			}
+
+			for _, allowed := range types {
+				if mType == allowed {
+					return next(c)
+				}
+			}
+
+			return fmt.Errorf(
+				"%w: content type %q is not allowed, expected one of: %s",
+				errbase.ErrUnsupportedMediaType, mType, strings.Join(types, ", "),
+			)
+		}
+	}
+}
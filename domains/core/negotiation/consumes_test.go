@@ -0,0 +1,68 @@
+package negotiation
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	th "github.com/outcatcher/anwil/domains/core/testhelpers"
+	"github.com/stretchr/testify/require"
+)
+
+func okResponse(c echo.Context) error {
+	return c.String(http.StatusOK, "OK")
+}
+
+func TestConsumes(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		contentType string
+		body        string
+		allowed     []string
+		expectedErr error
+	}{
+		{"json", MIMEJSON, "{}", DataTypes, nil},
+		{"json with charset", MIMEJSON + "; charset=utf-8", "{}", DataTypes, nil},
+		{"form", MIMEForm, "a=b", DataTypes, nil},
+		{"msgpack", MIMEMsgpack, "\x80", DataTypes, nil},
+		{"multipart", MIMEMultipart + "; boundary=xxx", "--xxx--", []string{MIMEMultipart}, nil},
+		{"no body", "", "", []string{MIMEJSON}, nil},
+		{"no body with type", MIMEJSON, "", []string{MIMEMultipart}, nil},
+		{"not allowed", MIMEJSON, "{}", []string{MIMEMultipart}, errbase.ErrUnsupportedMediaType},
+		{"missing type", "", "{}", DataTypes, errbase.ErrUnsupportedMediaType},
+		{"unknown type", "text/plain", "{}", DataTypes, errbase.ErrUnsupportedMediaType},
+		{"invalid type", "application/", "{}", DataTypes, errbase.ErrUnsupportedMediaType},
+	}
+
+	for _, data := range cases {
+		data := data
+
+		t.Run(data.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := th.ClosingRecorder(t)
+
+			request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(data.body))
+			if data.contentType != "" {
+				request.Header.Set(echo.HeaderContentType, data.contentType)
+			}
+
+			echoCtx := echo.New().NewContext(request, recorder)
+
+			err := Consumes(data.allowed...)(okResponse)(echoCtx)
+			if data.expectedErr != nil {
+				require.ErrorIs(t, err, data.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, recorder.Code)
+		})
+	}
+}
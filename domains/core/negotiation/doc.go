@@ -0,0 +1,7 @@
+/*
+Package negotiation contains request and response content type negotiation.
+
+Routes declare accepted request content types with Consumes, request body is decoded by Binder
+according to `Content-Type` header and response format is chosen by Respond according to `Accept` header.
+*/
+package negotiation
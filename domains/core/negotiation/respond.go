@@ -0,0 +1,122 @@
+package negotiation
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// responseTypes are content types structured responses can be sent with, the first one is the default.
+var responseTypes = []string{MIMEJSON, MIMEMsgpack} //nolint:gochecknoglobals
+
+// acceptRange - single media range of `Accept` header.
+type acceptRange struct {
+	mediaType string
+	quality   float64
+}
+
+// matches checks if the range includes given media type.
+func (r acceptRange) matches(mType string) bool {
+	if r.mediaType == "*/*" || r.mediaType == mType {
+		return true
+	}
+
+	return strings.HasSuffix(r.mediaType, "/*") && strings.HasPrefix(mType, strings.TrimSuffix(r.mediaType, "*"))
+}
+
+// parseAccept parses `Accept` header value, invalid ranges are skipped.
+func parseAccept(header string) []acceptRange {
+	ranges := make([]acceptRange, 0)
+
+	for _, part := range strings.Split(header, ",") {
+		mType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+
+		if qValue, ok := params["q"]; ok {
+			quality, err = strconv.ParseFloat(qValue, 64)
+			if err != nil {
+				continue
+			}
+		}
+
+		ranges = append(ranges, acceptRange{mediaType: mType, quality: quality})
+	}
+
+	return ranges
+}
+
+// Negotiate returns the offered content type best matching `Accept` header value.
+//
+// The first offer is returned for empty header. Empty string is returned if no offer is acceptable.
+func Negotiate(accept string, offers ...string) string {
+	if strings.TrimSpace(accept) == "" && len(offers) > 0 {
+		return offers[0]
+	}
+
+	ranges := parseAccept(accept)
+
+	best, bestQuality := "", 0.0
+
+	for _, offer := range offers {
+		quality, specificity := 0.0, -1
+
+		// the most specific matching range defines offer quality
+		for _, rng := range ranges {
+			if !rng.matches(offer) {
+				continue
+			}
+
+			rangeSpecificity := 2 - strings.Count(rng.mediaType, "*")
+			if rangeSpecificity > specificity {
+				quality, specificity = rng.quality, rangeSpecificity
+			}
+		}
+
+		if quality > bestQuality {
+			best, bestQuality = offer, quality
+		}
+	}
+
+	return best
+}
+
+// Respond sends structured response in format requested by `Accept` header.
+//
+// JSON is used by default.
+func Respond(c echo.Context, code int, data any) error {
+	accept := c.Request().Header.Get(echo.HeaderAccept)
+
+	c.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+
+	switch Negotiate(accept, responseTypes...) {
+	case MIMEJSON:
+		return c.JSON(code, data) //nolint:wrapcheck
+	case MIMEMsgpack:
+		buf := new(bytes.Buffer)
+
+		encoder := msgpack.NewEncoder(buf)
+		encoder.SetCustomStructTag(structTag)
+		encoder.UseCompactInts(true)
+
+		if err := encoder.Encode(data); err != nil {
+			return fmt.Errorf("error encoding MessagePack response: %w", err)
+		}
+
+		return c.Blob(code, MIMEMsgpack, buf.Bytes()) //nolint:wrapcheck
+	default:
+		return fmt.Errorf(
+			"%w: %q can't be satisfied, supported types: %s",
+			errbase.ErrNotAcceptable, accept, strings.Join(responseTypes, ", "),
+		)
+	}
+}
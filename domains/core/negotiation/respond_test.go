@@ -0,0 +1,100 @@
+package negotiation
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	th "github.com/outcatcher/anwil/domains/core/testhelpers"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type respondData struct {
+	Name  string `json:"name"`
+	Empty string `json:"empty,omitempty"`
+}
+
+func TestNegotiate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		accept   string
+		expected string
+	}{
+		{"", MIMEJSON},
+		{"*/*", MIMEJSON},
+		{"application/*", MIMEJSON},
+		{MIMEMsgpack, MIMEMsgpack},
+		{"application/json;q=0.5, application/msgpack", MIMEMsgpack},
+		{"application/msgpack;q=0.1, */*;q=0.5", MIMEJSON},
+		{"text/html,application/xhtml+xml,*/*;q=0.8", MIMEJSON},
+		{"*/*, application/json;q=0", MIMEMsgpack},
+		{"text/plain", ""},
+		{"application/json;q=0", ""},
+	}
+
+	for _, data := range cases {
+		data := data
+
+		t.Run(data.accept, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, data.expected, Negotiate(data.accept, MIMEJSON, MIMEMsgpack))
+		})
+	}
+}
+
+func respond(t *testing.T, accept string) (*httptest.ResponseRecorder, error) {
+	t.Helper()
+
+	recorder := th.ClosingRecorder(t)
+
+	request := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	request.Header.Set(echo.HeaderAccept, accept)
+
+	err := Respond(echo.New().NewContext(request, recorder), http.StatusCreated, respondData{Name: "wish"})
+
+	return recorder, err
+}
+
+func TestRespondJSON(t *testing.T) {
+	t.Parallel()
+
+	recorder, err := respond(t, "")
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusCreated, recorder.Code)
+	require.Contains(t, recorder.Header().Get(echo.HeaderContentType), MIMEJSON)
+	require.Equal(t, echo.HeaderAccept, recorder.Header().Get(echo.HeaderVary))
+
+	result := new(respondData)
+
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), result))
+	require.Equal(t, "wish", result.Name)
+}
+
+func TestRespondMsgpack(t *testing.T) {
+	t.Parallel()
+
+	recorder, err := respond(t, MIMEMsgpack)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusCreated, recorder.Code)
+	require.Equal(t, MIMEMsgpack, recorder.Header().Get(echo.HeaderContentType))
+
+	result := make(map[string]any)
+
+	require.NoError(t, msgpack.Unmarshal(recorder.Body.Bytes(), &result))
+	require.Equal(t, map[string]any{"name": "wish"}, result)
+}
+
+func TestRespondNotAcceptable(t *testing.T) {
+	t.Parallel()
+
+	_, err := respond(t, "text/plain")
+	require.ErrorIs(t, err, errbase.ErrNotAcceptable)
+}
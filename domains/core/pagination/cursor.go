@@ -0,0 +1,87 @@
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/outcatcher/anwil/domains/core/validation"
+)
+
+// keyContext is used to derive cursor signing key from the secret.
+const keyContext = "anwil pagination cursor"
+
+// ErrInvalidCursor - error for cursors which are malformed, forged or don't match the query.
+var ErrInvalidCursor = fmt.Errorf("%w: invalid cursor", validation.ErrValidationFailed)
+
+// cursor - position in the list.
+type cursor struct {
+	// Sort is the canonical sort the cursor is created for
+	Sort string `json:"s"`
+	// Values are encoded values of sort fields of the item the page starts after (or before)
+	Values []string `json:"v"`
+	// Backward is true for the cursors pointing to the previous page
+	Backward bool `json:"b,omitempty"`
+}
+
+// codec - signs and verifies cursors.
+type codec struct {
+	key []byte
+}
+
+// newCodec creates codec with the key derived from given secret.
+func newCodec(secret []byte) codec {
+	mac := hmac.New(sha256.New, secret)
+
+	_, _ = mac.Write([]byte(keyContext)) // hash writes never fail
+
+	return codec{key: mac.Sum(nil)}
+}
+
+func (c codec) sign(payload string) string {
+	mac := hmac.New(sha256.New, c.key)
+
+	_, _ = mac.Write([]byte(payload)) // hash writes never fail
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// encode returns opaque signed cursor string.
+func (c codec) encode(cur cursor) (string, error) {
+	data, err := json.Marshal(cur)
+	if err != nil {
+		return "", fmt.Errorf("error encoding cursor: %w", err)
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(data)
+
+	return payload + "." + c.sign(payload), nil
+}
+
+// decode verifies cursor signature and decodes it.
+func (c codec) decode(value string) (cursor, error) {
+	var cur cursor
+
+	payload, signature, found := strings.Cut(value, ".")
+	if !found {
+		return cur, ErrInvalidCursor
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(c.sign(payload))) {
+		return cur, ErrInvalidCursor
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return cur, ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(data, &cur); err != nil {
+		return cur, ErrInvalidCursor
+	}
+
+	return cur, nil
+}
@@ -0,0 +1,20 @@
+/*
+Package pagination contains cursor-based pagination, filtering and sorting of list endpoints.
+
+List is described by Spec defining fields which can be filtered and sorted by query parameters:
+
+	GET /items?name=foo&created_at[gte]=2023-04-01T00:00:00Z&sort=-created_at&limit=20
+
+Filter parameters have `field=value` or `field[operator]=value` form, `sort` is a comma-separated list
+of fields, descending ones are prefixed with `-`. Paginator parses query parameters into Query,
+which is used by storage to build `WHERE` and `ORDER BY` clauses with Query.Build.
+
+Pages are selected using keyset (seek) pagination: opaque cursor contains sort field values
+of the item the page starts after, so pages stay stable while items are added or removed.
+Cursors are signed, so clients can't craft cursors with arbitrary values.
+
+Storage selects one more item than the limit, NewPage uses it to find out if more items are available
+and creates cursors of the next and the previous pages, Page.Envelope returns standard response
+with links to them.
+*/
+package pagination
@@ -0,0 +1,129 @@
+package pagination
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// KeyFunc returns values of the item sort fields by field name.
+// Values are formatted with fmt, except time.Time which is formatted as RFC 3339 with nanoseconds.
+type KeyFunc[T any] func(item T) map[string]any
+
+// Page - single page of the list.
+type Page[T any] struct {
+	Items []T
+	// Next is the cursor of the next page, empty for the last page
+	Next string
+	// Prev is the cursor of the previous page, empty for the first page
+	Prev string
+}
+
+// NewPage creates page from the items selected using query clauses.
+func NewPage[T any](query Query, items []T, key KeyFunc[T]) (Page[T], error) {
+	page := Page[T]{Items: items} //nolint:exhaustruct
+
+	hasMore := len(items) > query.limit
+	if hasMore {
+		page.Items = items[:query.limit]
+	}
+
+	if query.backward {
+		reverse(page.Items)
+	}
+
+	if len(page.Items) == 0 {
+		return page, nil
+	}
+
+	first, last := page.Items[0], page.Items[len(page.Items)-1]
+	fromCursor := query.after != nil
+
+	var err error
+
+	// page reached going backward always has the next page, the one the cursor was created from
+	if (hasMore && !query.backward) || (fromCursor && query.backward) {
+		page.Next, err = query.cursorOf(key(last), false)
+		if err != nil {
+			return page, err
+		}
+	}
+
+	if (fromCursor && !query.backward) || (hasMore && query.backward) {
+		page.Prev, err = query.cursorOf(key(first), true)
+		if err != nil {
+			return page, err
+		}
+	}
+
+	return page, nil
+}
+
+// cursorOf creates signed cursor pointing after (or before) the item with given values.
+func (q Query) cursorOf(values map[string]any, backward bool) (string, error) {
+	cur := cursor{Sort: q.sort, Values: make([]string, len(q.order)), Backward: backward}
+
+	for i, key := range q.order {
+		value, ok := values[key.name]
+		if !ok {
+			return "", fmt.Errorf("error creating cursor: missing value of '%s'", key.name) //nolint:goerr113
+		}
+
+		cur.Values[i] = formatValue(value)
+	}
+
+	return q.codec.encode(cur)
+}
+
+func formatValue(value any) string {
+	if moment, ok := value.(time.Time); ok {
+		return moment.Format(time.RFC3339Nano)
+	}
+
+	return fmt.Sprint(value)
+}
+
+func reverse[T any](items []T) {
+	for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+		items[i], items[j] = items[j], items[i]
+	}
+}
+
+// Envelope - standard list response.
+type Envelope[T any] struct {
+	Items []T `json:"items"`
+	// Next is the link to the next page
+	Next string `json:"next,omitempty"`
+	// Prev is the link to the previous page
+	Prev string `json:"prev,omitempty"`
+}
+
+// Envelope returns response with links to adjacent pages made from the request URL.
+func (p Page[T]) Envelope(requestURL *url.URL) Envelope[T] {
+	envelope := Envelope[T]{Items: p.Items} //nolint:exhaustruct
+
+	if envelope.Items == nil {
+		envelope.Items = make([]T, 0)
+	}
+
+	if p.Next != "" {
+		envelope.Next = pageLink(requestURL, p.Next)
+	}
+
+	if p.Prev != "" {
+		envelope.Prev = pageLink(requestURL, p.Prev)
+	}
+
+	return envelope
+}
+
+func pageLink(requestURL *url.URL, cur string) string {
+	link := *requestURL
+
+	query := link.Query()
+	query.Set(ParamCursor, cur)
+
+	link.RawQuery = query.Encode()
+
+	return link.String()
+}
@@ -0,0 +1,116 @@
+package pagination
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testItem struct {
+	UUID      string
+	CreatedAt time.Time
+}
+
+func testItemKey(item testItem) map[string]any {
+	return map[string]any{"uuid": item.UUID, "created_at": item.CreatedAt}
+}
+
+func testItems(count int) []testItem {
+	uuids := []string{
+		"00000000-0000-0000-0000-000000000001",
+		"00000000-0000-0000-0000-000000000002",
+		"00000000-0000-0000-0000-000000000003",
+		"00000000-0000-0000-0000-000000000004",
+	}
+
+	items := make([]testItem, count)
+
+	for i := range items {
+		items[i] = testItem{UUID: uuids[i], CreatedAt: time.Date(2023, 4, 1, 0, 0, 0, i, time.UTC)}
+	}
+
+	return items
+}
+
+func TestNewPage(t *testing.T) {
+	t.Parallel()
+
+	paginator := newTestPaginator(t)
+	items := testItems(3)
+
+	first, err := paginator.Parse(nil)
+	require.NoError(t, err)
+
+	page, err := NewPage(first, items, testItemKey)
+	require.NoError(t, err)
+
+	require.Equal(t, items[:2], page.Items)
+	require.Empty(t, page.Prev)
+	require.NotEmpty(t, page.Next)
+
+	next, err := paginator.Parse(url.Values{"cursor": {page.Next}})
+	require.NoError(t, err)
+	require.False(t, next.backward)
+	require.Equal(t, []any{items[1].CreatedAt, items[1].UUID}, next.after)
+
+	page, err = NewPage(next, items[2:], testItemKey)
+	require.NoError(t, err)
+
+	require.Equal(t, items[2:], page.Items)
+	require.Empty(t, page.Next, "last page has next page")
+	require.NotEmpty(t, page.Prev)
+
+	prev, err := paginator.Parse(url.Values{"cursor": {page.Prev}})
+	require.NoError(t, err)
+	require.True(t, prev.backward)
+
+	// previous page items are selected in reverse order
+	page, err = NewPage(prev, []testItem{items[1], items[0]}, testItemKey)
+	require.NoError(t, err)
+
+	require.Equal(t, items[:2], page.Items)
+	require.NotEmpty(t, page.Next)
+	require.Empty(t, page.Prev, "first page has previous page")
+}
+
+func TestCursorValidation(t *testing.T) {
+	t.Parallel()
+
+	paginator := newTestPaginator(t)
+
+	query, err := paginator.Parse(nil)
+	require.NoError(t, err)
+
+	page, err := NewPage(query, testItems(3), testItemKey)
+	require.NoError(t, err)
+
+	_, err = paginator.Parse(url.Values{"cursor": {page.Next}, "sort": {"name"}})
+	require.ErrorIs(t, err, ErrInvalidCursor, "cursor is accepted for another sort")
+
+	_, err = paginator.Parse(url.Values{"cursor": {"x" + page.Next}})
+	require.ErrorIs(t, err, ErrInvalidCursor, "modified cursor is accepted")
+
+	other, err := New(testSpec(), []byte("other secret"))
+	require.NoError(t, err)
+
+	_, err = other.Parse(url.Values{"cursor": {page.Next}})
+	require.ErrorIs(t, err, ErrInvalidCursor, "cursor signed by other key is accepted")
+
+	_, err = NewPage(query, testItems(3), func(testItem) map[string]any { return nil })
+	require.Error(t, err)
+}
+
+func TestEnvelope(t *testing.T) {
+	t.Parallel()
+
+	requestURL, err := url.Parse("/api/v1/items?name=value&cursor=old")
+	require.NoError(t, err)
+
+	envelope := Page[testItem]{Items: nil, Next: "next", Prev: ""}.Envelope(requestURL)
+
+	require.Equal(t, []testItem{}, envelope.Items)
+	require.Equal(t, "/api/v1/items?cursor=next&name=value", envelope.Next)
+	require.Empty(t, envelope.Prev)
+}
@@ -0,0 +1,250 @@
+package pagination
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/outcatcher/anwil/domains/core/validation"
+)
+
+// Reserved query parameters.
+const (
+	ParamSort   = "sort"
+	ParamLimit  = "limit"
+	ParamCursor = "cursor"
+)
+
+// condition - single filter condition.
+type condition struct {
+	column   string
+	operator Operator
+	value    any
+}
+
+// order - single sort key.
+type order struct {
+	name       string
+	field      Field
+	descending bool
+}
+
+// Query - parsed list query parameters.
+type Query struct {
+	codec codec
+
+	conditions []condition
+	order      []order
+	limit      int
+
+	// sort is canonical sort parameter value
+	sort string
+	// after contains parsed values of the cursor, nil for the first page
+	after    []any
+	backward bool
+}
+
+// Limit returns requested page size.
+func (q Query) Limit() int {
+	return q.limit
+}
+
+// Paginator - parser of list query parameters for the single list spec.
+type Paginator struct {
+	spec  Spec
+	codec codec
+}
+
+// New creates paginator for given spec. Cursors are signed with a key derived from the secret.
+func New(spec Spec, secret []byte) (*Paginator, error) {
+	if err := spec.validate(); err != nil {
+		return nil, fmt.Errorf("error creating paginator: %w", err)
+	}
+
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("error creating paginator: cursor secret is empty") //nolint:goerr113
+	}
+
+	return &Paginator{spec: spec, codec: newCodec(secret)}, nil
+}
+
+// Parse parses list query parameters. Parameters not matching any spec field are ignored.
+func (p *Paginator) Parse(params url.Values) (Query, error) {
+	query := Query{codec: p.codec} //nolint:exhaustruct
+
+	conditions, err := p.spec.parseFilters(params)
+	if err != nil {
+		return query, err
+	}
+
+	query.conditions = conditions
+
+	sortValue := params.Get(ParamSort)
+	if sortValue == "" {
+		sortValue = p.spec.DefaultSort
+	}
+
+	query.order, err = p.spec.parseSort(sortValue)
+	if err != nil {
+		return query, err
+	}
+
+	query.sort = formatSort(query.order)
+
+	query.limit, err = p.spec.parseLimit(params.Get(ParamLimit))
+	if err != nil {
+		return query, err
+	}
+
+	if value := params.Get(ParamCursor); value != "" {
+		cur, err := p.codec.decode(value)
+		if err != nil {
+			return query, err
+		}
+
+		query.after, err = parseCursorValues(cur, query)
+		if err != nil {
+			return query, err
+		}
+
+		query.backward = cur.Backward
+	}
+
+	return query, nil
+}
+
+// parseFilters parses filter parameters in `field=value` or `field[operator]=value` form.
+func (s Spec) parseFilters(params url.Values) ([]condition, error) {
+	names := make([]string, 0, len(params))
+
+	for name := range params {
+		names = append(names, name)
+	}
+
+	// conditions are sorted to make built queries stable
+	sort.Strings(names)
+
+	conditions := make([]condition, 0)
+
+	for _, param := range names {
+		name, operator := param, Eq
+
+		if open := strings.IndexByte(param, '['); open > 0 && strings.HasSuffix(param, "]") {
+			name, operator = param[:open], Operator(param[open+1:len(param)-1])
+		}
+
+		field, ok := s.Fields[name]
+		if !ok {
+			continue
+		}
+
+		if !field.allows(operator) {
+			return nil, fmt.Errorf("%w: query parameter '%s' is not supported",
+				validation.ErrValidationFailed, param)
+		}
+
+		for _, value := range params[param] {
+			parsed, err := field.parse(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: query parameter '%s' %s", validation.ErrValidationFailed, param, err)
+			}
+
+			conditions = append(conditions, condition{column: field.Column, operator: operator, value: parsed})
+		}
+	}
+
+	return conditions, nil
+}
+
+// parseSort parses comma-separated list of sort fields. Key field is appended if missing.
+func (s Spec) parseSort(value string) ([]order, error) {
+	keys := make([]order, 0)
+	used := make(map[string]bool)
+
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		key := order{name: strings.TrimPrefix(item, "-"), descending: strings.HasPrefix(item, "-")} //nolint:exhaustruct
+
+		field, ok := s.Fields[key.name]
+		if !ok || !field.Sortable {
+			return nil, fmt.Errorf("%w: can't sort by '%s'", validation.ErrValidationFailed, key.name)
+		}
+
+		if used[key.name] {
+			return nil, fmt.Errorf("%w: duplicated sort field '%s'", validation.ErrValidationFailed, key.name)
+		}
+
+		used[key.name] = true
+		key.field = field
+
+		keys = append(keys, key)
+	}
+
+	if !used[s.Key] {
+		descending := len(keys) > 0 && keys[len(keys)-1].descending
+
+		keys = append(keys, order{name: s.Key, field: s.Fields[s.Key], descending: descending})
+	}
+
+	return keys, nil
+}
+
+// parseLimit parses page size.
+func (s Spec) parseLimit(value string) (int, error) {
+	if value == "" {
+		return s.DefaultLimit, nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 {
+		return 0, fmt.Errorf("%w: query parameter '%s' should be positive integer",
+			validation.ErrValidationFailed, ParamLimit)
+	}
+
+	if limit > s.MaxLimit {
+		limit = s.MaxLimit
+	}
+
+	return limit, nil
+}
+
+// formatSort returns canonical sort parameter value.
+func formatSort(keys []order) string {
+	items := make([]string, len(keys))
+
+	for i, key := range keys {
+		items[i] = key.name
+
+		if key.descending {
+			items[i] = "-" + key.name
+		}
+	}
+
+	return strings.Join(items, ",")
+}
+
+// parseCursorValues checks the cursor matches the query and parses its values.
+func parseCursorValues(cur cursor, query Query) ([]any, error) {
+	if cur.Sort != query.sort || len(cur.Values) != len(query.order) {
+		return nil, fmt.Errorf("%w: cursor doesn't match sort", ErrInvalidCursor)
+	}
+
+	values := make([]any, len(cur.Values))
+
+	for i, value := range cur.Values {
+		parsed, err := query.order[i].field.parse(value)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+
+		values[i] = parsed
+	}
+
+	return values, nil
+}
@@ -0,0 +1,127 @@
+package pagination
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/outcatcher/anwil/domains/core/validation"
+	"github.com/stretchr/testify/require"
+)
+
+var testSecret = []byte("test secret") //nolint:gochecknoglobals
+
+func testSpec() Spec {
+	return Spec{
+		Fields: map[string]Field{
+			"uuid":       {Column: "uuid", Parse: ParseUUID, Operators: []Operator{Eq}, Sortable: true},
+			"name":       {Column: "name", Parse: nil, Operators: []Operator{Eq, Ne}, Sortable: true},
+			"count":      {Column: "count", Parse: ParseInt, Operators: nil, Sortable: true},
+			"created_at": {Column: "created_at", Parse: ParseTime, Operators: []Operator{Gte, Lt}, Sortable: true},
+			"hidden":     {Column: "hidden", Parse: nil, Operators: []Operator{Eq}, Sortable: false},
+		},
+		Key:          "uuid",
+		DefaultSort:  "-created_at",
+		DefaultLimit: 2,
+		MaxLimit:     10,
+	}
+}
+
+func newTestPaginator(t *testing.T) *Paginator {
+	t.Helper()
+
+	paginator, err := New(testSpec(), testSecret)
+	require.NoError(t, err)
+
+	return paginator
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	invalid := map[string]func(spec *Spec){
+		"unknown key":          func(spec *Spec) { spec.Key = "unknown" },
+		"unsortable key":       func(spec *Spec) { spec.Key = "hidden" },
+		"invalid default sort": func(spec *Spec) { spec.DefaultSort = "hidden" },
+		"invalid limits":       func(spec *Spec) { spec.MaxLimit = 1 },
+		"unknown operator": func(spec *Spec) {
+			spec.Fields["name"] = Field{Column: "name", Parse: nil, Operators: []Operator{"like"}, Sortable: false}
+		},
+	}
+
+	for name, modify := range invalid {
+		name, modify := name, modify
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			spec := testSpec()
+			modify(&spec)
+
+			_, err := New(spec, testSecret)
+			require.Error(t, err)
+		})
+	}
+
+	_, err := New(testSpec(), nil)
+	require.Error(t, err)
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	paginator := newTestPaginator(t)
+
+	t.Run("defaults", func(t *testing.T) {
+		t.Parallel()
+
+		query, err := paginator.Parse(url.Values{"unknown": {"value"}})
+		require.NoError(t, err)
+
+		require.Empty(t, query.conditions)
+		require.Equal(t, "-created_at,-uuid", query.sort)
+		require.Equal(t, 2, query.Limit())
+		require.Nil(t, query.after)
+	})
+
+	t.Run("filters and sort", func(t *testing.T) {
+		t.Parallel()
+
+		query, err := paginator.Parse(url.Values{
+			"name":            {"first", "second"},
+			"created_at[gte]": {"2023-04-01T00:00:00Z"},
+			"sort":            {"count,-name"},
+			"limit":           {"100"},
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, []condition{
+			{column: "created_at", operator: Gte, value: time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)},
+			{column: "name", operator: Eq, value: "first"},
+			{column: "name", operator: Eq, value: "second"},
+		}, query.conditions)
+		require.Equal(t, "count,-name,-uuid", query.sort)
+		require.Equal(t, 10, query.Limit())
+	})
+
+	for name, params := range map[string]url.Values{
+		"unsupported operator": {"name[gt]": {"value"}},
+		"not filterable":       {"count": {"1"}},
+		"invalid value":        {"created_at[lt]": {"yesterday"}},
+		"invalid uuid":         {"uuid": {"not-uuid"}},
+		"not sortable":         {"sort": {"hidden"}},
+		"unknown sort":         {"sort": {"-unknown"}},
+		"duplicated sort":      {"sort": {"name,-name"}},
+		"invalid limit":        {"limit": {"0"}},
+		"invalid cursor":       {"cursor": {"invalid"}},
+	} {
+		params := params
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := paginator.Parse(params)
+			require.ErrorIs(t, err, validation.ErrValidationFailed)
+		})
+	}
+}
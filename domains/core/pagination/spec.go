@@ -0,0 +1,151 @@
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Operator - filter comparison operator.
+type Operator string
+
+// Operators which can be used in filter query parameters as `field[operator]=value`.
+const (
+	Eq  Operator = "eq"
+	Ne  Operator = "ne"
+	Lt  Operator = "lt"
+	Lte Operator = "lte"
+	Gt  Operator = "gt"
+	Gte Operator = "gte"
+)
+
+// sqlOperators maps operators to SQL ones.
+var sqlOperators = map[Operator]string{ //nolint:gochecknoglobals
+	Eq:  "=",
+	Ne:  "<>",
+	Lt:  "<",
+	Lte: "<=",
+	Gt:  ">",
+	Gte: ">=",
+}
+
+// ParseFunc converts query parameter value to SQL argument.
+// Returned error should describe expected value, e.g. "should be integer".
+type ParseFunc func(value string) (any, error)
+
+var (
+	errNotInteger = errors.New("should be integer")
+	errNotTime    = errors.New("should be RFC 3339 time")
+	errNotUUID    = errors.New("should be UUID")
+)
+
+// ParseString uses value as is.
+func ParseString(value string) (any, error) {
+	return value, nil
+}
+
+// ParseInt parses integer value.
+func ParseInt(value string) (any, error) {
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, errNotInteger
+	}
+
+	return parsed, nil
+}
+
+// ParseTime parses RFC 3339 time.
+func ParseTime(value string) (any, error) {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errNotTime
+	}
+
+	return parsed, nil
+}
+
+// ParseUUID validates UUID value.
+func ParseUUID(value string) (any, error) {
+	if _, err := uuid.Parse(value); err != nil {
+		return nil, errNotUUID
+	}
+
+	return value, nil
+}
+
+// Field - list item attribute which can be used in query parameters.
+type Field struct {
+	// Column is SQL expression of the field. It's used in queries as is, so it should never come from user input.
+	Column string
+	// Parse converts parameter values, ParseString is used by default
+	Parse ParseFunc
+	// Operators allowed in filters of the field. Field is not filterable if empty.
+	Operators []Operator
+	// Sortable fields can be used in `sort` parameter. Sortable columns should not be nullable.
+	Sortable bool
+}
+
+func (f Field) parse(value string) (any, error) {
+	if f.Parse == nil {
+		return ParseString(value)
+	}
+
+	return f.Parse(value)
+}
+
+func (f Field) allows(operator Operator) bool {
+	for _, allowed := range f.Operators {
+		if allowed == operator {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Spec - allow-list of fields of the list endpoint.
+type Spec struct {
+	// Fields maps names used in query parameters to fields
+	Fields map[string]Field
+	// Key is the name of unique sortable field, it's appended to any sort to make the order stable
+	Key string
+	// DefaultSort is used when `sort` parameter is missing, e.g. "-created_at"
+	DefaultSort string
+	// DefaultLimit is page size used when `limit` parameter is missing
+	DefaultLimit int
+	// MaxLimit is max page size, greater limits are reduced to it
+	MaxLimit int
+}
+
+// validate checks the spec is consistent.
+func (s Spec) validate() error {
+	key, ok := s.Fields[s.Key]
+	if !ok || !key.Sortable {
+		return fmt.Errorf("key field '%s' should be sortable field", s.Key) //nolint:goerr113
+	}
+
+	for name, field := range s.Fields {
+		if field.Column == "" {
+			return fmt.Errorf("field '%s' has no column", name) //nolint:goerr113
+		}
+
+		for _, op := range field.Operators {
+			if _, ok := sqlOperators[op]; !ok {
+				return fmt.Errorf("field '%s' has unknown operator '%s'", name, op) //nolint:goerr113
+			}
+		}
+	}
+
+	if s.DefaultLimit <= 0 || s.MaxLimit < s.DefaultLimit {
+		return fmt.Errorf("invalid page limits %d/%d", s.DefaultLimit, s.MaxLimit) //nolint:goerr113
+	}
+
+	if _, err := s.parseSort(s.DefaultSort); err != nil {
+		return fmt.Errorf("invalid default sort: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package pagination
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Clause - SQL parts built from the query.
+type Clause struct {
+	// Where is the condition for `WHERE` clause, `TRUE` if nothing is filtered
+	Where string
+	// OrderLimit is `ORDER BY ... LIMIT ...` tail of the query
+	OrderLimit string
+	// Args are query arguments referenced by Where and OrderLimit
+	Args []any
+}
+
+// Build builds SQL clauses of the query. Arguments are numbered starting with firstArg,
+// so clauses can be appended to a query having own arguments.
+//
+// One item more than the limit is selected, so NewPage can tell if there is a next page.
+// Column names are taken from the spec only, all values are passed as arguments.
+func (q Query) Build(firstArg int) Clause {
+	clause := Clause{Args: make([]any, 0)} //nolint:exhaustruct
+
+	addArg := func(value any) string {
+		clause.Args = append(clause.Args, value)
+
+		return fmt.Sprintf("$%d", firstArg+len(clause.Args)-1)
+	}
+
+	conditions := make([]string, 0, len(q.conditions)+1)
+
+	for _, cond := range q.conditions {
+		conditions = append(conditions, fmt.Sprintf("%s %s %s", cond.column, sqlOperators[cond.operator], addArg(cond.value)))
+	}
+
+	if q.after != nil {
+		conditions = append(conditions, q.seekCondition(addArg))
+	}
+
+	clause.Where = "TRUE"
+	if len(conditions) > 0 {
+		clause.Where = strings.Join(conditions, " AND ")
+	}
+
+	orderBy := make([]string, len(q.order))
+
+	for i, key := range q.order {
+		direction := "ASC"
+		// previous page is selected in reverse order
+		if key.descending != q.backward {
+			direction = "DESC"
+		}
+
+		orderBy[i] = key.field.Column + " " + direction
+	}
+
+	clause.OrderLimit = fmt.Sprintf("ORDER BY %s LIMIT %s", strings.Join(orderBy, ", "), addArg(q.limit+1))
+
+	return clause
+}
+
+// seekCondition returns condition selecting items after the cursor (or before it for backward cursor):
+// `(a > $1) OR (a = $1 AND b > $2) OR ...`.
+func (q Query) seekCondition(addArg func(value any) string) string {
+	placeholders := make([]string, len(q.after))
+
+	for i, value := range q.after {
+		placeholders[i] = addArg(value)
+	}
+
+	alternatives := make([]string, len(q.order))
+
+	for i, key := range q.order {
+		parts := make([]string, 0, i+1)
+
+		for j := 0; j < i; j++ {
+			parts = append(parts, fmt.Sprintf("%s = %s", q.order[j].field.Column, placeholders[j]))
+		}
+
+		operator := ">"
+		if key.descending != q.backward {
+			operator = "<"
+		}
+
+		parts = append(parts, fmt.Sprintf("%s %s %s", key.field.Column, operator, placeholders[i]))
+
+		alternatives[i] = "(" + strings.Join(parts, " AND ") + ")"
+	}
+
+	return "(" + strings.Join(alternatives, " OR ") + ")"
+}
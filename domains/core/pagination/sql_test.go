@@ -0,0 +1,81 @@
+package pagination
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuild(t *testing.T) {
+	t.Parallel()
+
+	paginator := newTestPaginator(t)
+
+	t.Run("first page", func(t *testing.T) {
+		t.Parallel()
+
+		query, err := paginator.Parse(url.Values{"name[ne]": {"value"}, "sort": {"count"}})
+		require.NoError(t, err)
+
+		clause := query.Build(2)
+
+		require.Equal(t, "name <> $2", clause.Where)
+		require.Equal(t, "ORDER BY count ASC, uuid ASC LIMIT $3", clause.OrderLimit)
+		require.Equal(t, []any{"value", 3}, clause.Args)
+	})
+
+	t.Run("no filters", func(t *testing.T) {
+		t.Parallel()
+
+		query, err := paginator.Parse(nil)
+		require.NoError(t, err)
+
+		clause := query.Build(1)
+
+		require.Equal(t, "TRUE", clause.Where)
+		require.Equal(t, "ORDER BY created_at DESC, uuid DESC LIMIT $1", clause.OrderLimit)
+	})
+
+	cases := map[string]struct {
+		backward bool
+		where    string
+		order    string
+	}{
+		"next page cursor": {
+			backward: false,
+			where:    "name = $1 AND ((count > $2) OR (count = $2 AND name < $3) OR (count = $2 AND name = $3 AND uuid < $4))",
+			order:    "ORDER BY count ASC, name DESC, uuid DESC LIMIT $5",
+		},
+		"previous page cursor": {
+			backward: true,
+			where:    "name = $1 AND ((count < $2) OR (count = $2 AND name > $3) OR (count = $2 AND name = $3 AND uuid > $4))",
+			order:    "ORDER BY count DESC, name ASC, uuid ASC LIMIT $5",
+		},
+	}
+
+	for name, expected := range cases {
+		expected := expected
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			query, err := paginator.Parse(url.Values{"name": {"value"}, "sort": {"count,-name"}})
+			require.NoError(t, err)
+
+			cur, err := query.cursorOf(map[string]any{
+				"count": 5, "name": "last", "uuid": "6b1f3c1e-0f4a-4c8e-9d0e-2f0a0e6a3b1c",
+			}, expected.backward)
+			require.NoError(t, err)
+
+			query, err = paginator.Parse(url.Values{"name": {"value"}, "sort": {"count,-name"}, "cursor": {cur}})
+			require.NoError(t, err)
+
+			clause := query.Build(1)
+
+			require.Equal(t, expected.where, clause.Where)
+			require.Equal(t, expected.order, clause.OrderLimit)
+			require.Equal(t, []any{"value", int64(5), "last", "6b1f3c1e-0f4a-4c8e-9d0e-2f0a0e6a3b1c", 3}, clause.Args)
+		})
+	}
+}
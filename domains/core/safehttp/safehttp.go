@@ -0,0 +1,132 @@
+/*
+Package safehttp contains HTTP client for requesting user-provided URLs.
+*/
+package safehttp
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	"github.com/outcatcher/anwil/domains/core/validation"
+)
+
+const maxRedirects = 5
+
+// ErrAddressNotAllowed - error for URLs resolving to internal addresses.
+var ErrAddressNotAllowed = fmt.Errorf("%w: address is not allowed", validation.ErrValidationFailed)
+
+// blockedPrefixes are special-purpose networks not covered by netip.Addr methods.
+var blockedPrefixes = []netip.Prefix{ //nolint:gochecknoglobals
+	netip.MustParsePrefix("0.0.0.0/8"),          // "this" network
+	netip.MustParsePrefix("100.64.0.0/10"),      // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),       // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),      // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),        // reserved
+	netip.MustParsePrefix("64:ff9b::/96"),       // NAT64
+	netip.MustParsePrefix("2001:db8::/32"),      // documentation
+	netip.MustParsePrefix("fec0::/10"),          // deprecated site-local
+	netip.MustParsePrefix("2002::/16"),          // 6to4, can embed private IPv4
+	netip.MustParsePrefix("ff00::/8"),           // multicast
+	netip.MustParsePrefix("255.255.255.255/32"), // broadcast
+}
+
+// IsPublicAddr checks if address is a public unicast one.
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	if !addr.IsValid() || addr.IsUnspecified() || addr.IsLoopback() || addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() {
+		return false
+	}
+
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// dialControl rejects connections to not public addresses.
+//
+// Check is done for the resolved address right before connecting, so DNS rebinding can't bypass it.
+func dialControl(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("error parsing dialed address: %w", err)
+	}
+
+	if !IsPublicAddr(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrAddressNotAllowed, addrPort.Addr())
+	}
+
+	return nil
+}
+
+// NewClient creates HTTP client safe for requesting user-provided URLs.
+//
+// Connections to private networks are rejected unless allowPrivate is set.
+func NewClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout} //nolint:exhaustruct
+	if !allowPrivate {
+		dialer.Control = dialControl
+	}
+
+	transport := &http.Transport{ //nolint:exhaustruct
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   timeout,
+		ResponseHeaderTimeout: timeout,
+		MaxIdleConns:          10, //nolint:gomnd
+		IdleConnTimeout:       time.Minute,
+	}
+
+	return &http.Client{ //nolint:exhaustruct
+		Transport: transport,
+		Timeout:   timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("%w: too many redirects", errbase.ErrUpstream)
+			}
+
+			return ValidateURL(req.URL)
+		},
+	}
+}
+
+// ValidateURL checks if URL can be requested.
+func ValidateURL(target *url.URL) error {
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return fmt.Errorf("%w: only http and https URLs are supported", validation.ErrValidationFailed)
+	}
+
+	if target.Hostname() == "" || target.User != nil {
+		return fmt.Errorf("%w: invalid URL", validation.ErrValidationFailed)
+	}
+
+	return nil
+}
+
+// ParseURL parses and validates user-provided URL. URL fragment is removed.
+func ParseURL(rawURL string) (*url.URL, error) {
+	target, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid URL", validation.ErrValidationFailed)
+	}
+
+	if err := ValidateURL(target); err != nil {
+		return nil, err
+	}
+
+	target.Fragment = ""
+
+	return target, nil
+}
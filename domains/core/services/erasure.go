@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/outcatcher/anwil/domains/core/services/schema"
+)
+
+// UserDataEraserInject attaches user data eraser to the service.
+func UserDataEraserInject(consumer, provider any) error {
+	reqEraser, provEraser, err := ValidateArgInterfaces[schema.RequiresUserDataEraser, schema.UserDataEraser](
+		consumer, provider,
+	)
+	if err != nil {
+		return fmt.Errorf("error injecting user data eraser: %w", err)
+	}
+
+	reqEraser.UseUserDataEraser(provEraser)
+
+	return nil
+}
+
+// PrepareErasure prepares erasure of the user data in all services implementing schema.UserDataEraser.
+//
+// Services are processed in order of their IDs, preparation stops on the first error.
+// Returned function calls erase functions of all services, failure of one of them doesn't stop the others.
+func PrepareErasure(ctx context.Context, mapping schema.ServiceMapping, userUUID string) (schema.EraseFunc, error) {
+	ids := make([]string, 0, len(mapping))
+
+	for id := range mapping {
+		ids = append(ids, string(id))
+	}
+
+	sort.Strings(ids)
+
+	eraseFuncs := make([]schema.EraseFunc, 0, len(ids))
+
+	for _, id := range ids {
+		eraser, ok := mapping[schema.ServiceID(id)].(schema.UserDataEraser)
+		if !ok {
+			continue
+		}
+
+		erase, err := eraser.PrepareErasure(ctx, userUUID)
+		if err != nil {
+			return nil, fmt.Errorf("error preparing user data erasure in service %s: %w", id, err)
+		}
+
+		eraseFuncs = append(eraseFuncs, erase)
+	}
+
+	return func(ctx context.Context) error {
+		var firstErr error
+
+		for _, erase := range eraseFuncs {
+			if err := erase(ctx); err != nil && firstErr == nil {
+				firstErr = fmt.Errorf("error erasing user data: %w", err)
+			}
+		}
+
+		return firstErr
+	}, nil
+}
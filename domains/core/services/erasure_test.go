@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type testEraser struct {
+	mock.Mock
+}
+
+func (e *testEraser) PrepareErasure(ctx context.Context, userUUID string) (svcSchema.EraseFunc, error) {
+	args := e.Called(ctx, userUUID)
+
+	if err := args.Error(1); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context) error {
+		return e.MethodCalled("Erase", ctx).Error(0)
+	}, nil
+}
+
+func TestPrepareErasure(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	userUUID := randomString("uuid-", 10)
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		eraser1 := new(testEraser)
+		eraser1.On("PrepareErasure", ctx, userUUID).Return(nil, nil)
+		eraser1.On("Erase", ctx).Return(nil)
+
+		eraser2 := new(testEraser)
+		eraser2.On("PrepareErasure", ctx, userUUID).Return(nil, nil)
+		eraser2.On("Erase", ctx).Return(nil)
+
+		mapping := svcSchema.ServiceMapping{
+			"first":      eraser1,
+			"second":     eraser2,
+			"not-eraser": new(testService),
+		}
+
+		erase, err := PrepareErasure(ctx, mapping, userUUID)
+		require.NoError(t, err)
+
+		eraser1.AssertNotCalled(t, "Erase", ctx)
+
+		require.NoError(t, erase(ctx))
+
+		eraser1.AssertNumberOfCalls(t, "Erase", 1)
+		eraser2.AssertNumberOfCalls(t, "Erase", 1)
+	})
+
+	t.Run("prepare error", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected prepare error") //nolint:goerr113
+
+		eraser := new(testEraser)
+		eraser.On("PrepareErasure", ctx, userUUID).Return(nil, expectedErr)
+
+		_, err := PrepareErasure(ctx, svcSchema.ServiceMapping{"failing": eraser}, userUUID)
+		require.ErrorIs(t, err, expectedErr)
+	})
+
+	t.Run("erase error", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected erase error") //nolint:goerr113
+
+		failing := new(testEraser)
+		failing.On("PrepareErasure", ctx, userUUID).Return(nil, nil)
+		failing.On("Erase", ctx).Return(expectedErr)
+
+		next := new(testEraser)
+		next.On("PrepareErasure", ctx, userUUID).Return(nil, nil)
+		next.On("Erase", ctx).Return(nil)
+
+		erase, err := PrepareErasure(ctx, svcSchema.ServiceMapping{"a": failing, "b": next}, userUUID)
+		require.NoError(t, err)
+
+		require.ErrorIs(t, erase(ctx), expectedErr)
+
+		next.AssertNumberOfCalls(t, "Erase", 1)
+	})
+}
+
+func TestUserDataEraserInject(t *testing.T) {
+	t.Parallel()
+
+	consumer := new(eraserConsumer)
+	provider := new(testEraser)
+
+	require.NoError(t, UserDataEraserInject(consumer, provider))
+	require.Equal(t, provider, consumer.eraser)
+
+	err := UserDataEraserInject(consumer, new(testState))
+	require.ErrorIs(t, err, errNotProvided)
+}
+
+type eraserConsumer struct {
+	eraser svcSchema.UserDataEraser
+}
+
+func (c *eraserConsumer) UseUserDataEraser(eraser svcSchema.UserDataEraser) {
+	c.eraser = eraser
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/outcatcher/anwil/domains/core/services/schema"
+)
+
+// UserDataCollectorInject attaches user data collector to the service.
+func UserDataCollectorInject(consumer, provider any) error {
+	reqCollector, provCollector, err := ValidateArgInterfaces[
+		schema.RequiresUserDataCollector, schema.UserDataCollector,
+	](consumer, provider)
+	if err != nil {
+		return fmt.Errorf("error injecting user data collector: %w", err)
+	}
+
+	reqCollector.UseUserDataCollector(provCollector)
+
+	return nil
+}
+
+// CollectUserData collects data of the user from all services implementing schema.UserDataExporter.
+//
+// Services returning nil export are skipped.
+func CollectUserData(
+	ctx context.Context, mapping schema.ServiceMapping, userUUID string,
+) (map[schema.ServiceID]*schema.UserDataExport, error) {
+	result := make(map[schema.ServiceID]*schema.UserDataExport)
+
+	for id, svc := range mapping {
+		exporter, ok := svc.(schema.UserDataExporter)
+		if !ok {
+			continue
+		}
+
+		export, err := exporter.ExportUserData(ctx, userUUID)
+		if err != nil {
+			return nil, fmt.Errorf("error exporting user data from service %s: %w", id, err)
+		}
+
+		if export == nil {
+			continue
+		}
+
+		result[id] = export
+	}
+
+	return result, nil
+}
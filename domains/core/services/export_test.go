@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type testExporter struct {
+	mock.Mock
+}
+
+func (e *testExporter) ExportUserData(ctx context.Context, userUUID string) (*svcSchema.UserDataExport, error) {
+	args := e.Called(ctx, userUUID)
+
+	export, _ := args.Get(0).(*svcSchema.UserDataExport)
+
+	return export, args.Error(1)
+}
+
+func TestCollectUserData(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	userUUID := randomString("uuid-", 10)
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		expected := &svcSchema.UserDataExport{Data: map[string]string{"name": "value"}}
+
+		exporter := new(testExporter)
+		exporter.On("ExportUserData", ctx, userUUID).Return(expected, nil)
+
+		emptyExporter := new(testExporter)
+		emptyExporter.On("ExportUserData", ctx, userUUID).Return(nil, nil)
+
+		mapping := svcSchema.ServiceMapping{
+			"exporter":     exporter,
+			"empty":        emptyExporter,
+			"not-exporter": new(testService),
+		}
+
+		result, err := CollectUserData(ctx, mapping, userUUID)
+		require.NoError(t, err)
+		require.Equal(t, map[svcSchema.ServiceID]*svcSchema.UserDataExport{"exporter": expected}, result)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected export error") //nolint:goerr113
+
+		exporter := new(testExporter)
+		exporter.On("ExportUserData", ctx, userUUID).Return(nil, expectedErr)
+
+		_, err := CollectUserData(ctx, svcSchema.ServiceMapping{"failing": exporter}, userUUID)
+		require.ErrorIs(t, err, expectedErr)
+	})
+}
@@ -68,6 +68,10 @@ func (init *initializer) initWithDependencies(
 	init.services[id] = initialized
 	init.serviceStates[id] = serviceReady
 
+	if registry, ok := init.state.(schema.RegistersServices); ok {
+		registry.RegisterService(id, initialized)
+	}
+
 	return nil
 }
 
@@ -75,7 +79,8 @@ func (init *initializer) initWithDependencies(
 //
 // Service dependencies will be checked for existing cycles and initialized in the dependency order.
 //
-// State will be passed to each service in mapping `Init` method.
+// State will be passed to each service in mapping `Init` method. If state implements `RegistersServices`,
+// each service is registered in it right after initialization, so it's available for the dependent services.
 func Initialize(
 	ctx context.Context, state any, services ...schema.ServiceDefinition,
 ) (schema.ServiceMapping, error) {
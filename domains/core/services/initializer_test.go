@@ -7,7 +7,6 @@ import (
 
 	"github.com/google/uuid"
 	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
-	th "github.com/outcatcher/anwil/domains/core/testhelpers"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -36,6 +35,11 @@ func (ts *testState) Service(id svcSchema.ServiceID) any {
 	return args.Get(0)
 }
 
+// RegisterService - mock method to match RegistersServices interface.
+func (ts *testState) RegisterService(id svcSchema.ServiceID, service any) {
+	ts.Called(id, service)
+}
+
 func testServiceInit(svc *testService) svcSchema.ServiceInitFunc {
 	return func(ctx context.Context, state any) (any, error) {
 		if svc == nil {
@@ -54,7 +58,7 @@ func testServiceInit(svc *testService) svcSchema.ServiceInitFunc {
 
 func testServiceDefinition(svc *testService, dependencies ...svcSchema.ServiceID) svcSchema.ServiceDefinition {
 	return svcSchema.ServiceDefinition{
-		ID:        svcSchema.ServiceID(th.RandomString("id-", 5)),
+		ID:        svcSchema.ServiceID(randomString("id-", 5)),
 		Init:      testServiceInit(svc),
 		DependsOn: dependencies,
 	}
@@ -107,6 +111,36 @@ func TestInitialize(t *testing.T) {
 		mocked.AssertNumberOfCalls(t, "init", 1)
 	})
 
+	t.Run("registered in state", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		state := new(testState)
+
+		mocked := &testService{}
+		mocked.On("init", ctx, state).Return(nil)
+
+		svc1 := testServiceDefinition(mocked)
+		svc2 := svcSchema.ServiceDefinition{
+			ID: svcSchema.ServiceID(randomString("id-", 5)),
+			Init: func(context.Context, any) (any, error) {
+				// dependency is available during initialization
+				state.AssertCalled(t, "RegisterService", svc1.ID, mocked)
+
+				return new(testService), nil
+			},
+			DependsOn: []svcSchema.ServiceID{svc1.ID},
+		}
+
+		state.On("RegisterService", svc1.ID, mocked).Once()
+		state.On("RegisterService", svc2.ID, mock.Anything).Once()
+
+		_, err := Initialize(ctx, state, svc2, svc1)
+		require.NoError(t, err)
+
+		state.AssertExpectations(t)
+	})
+
 	t.Run("cyclic direct", func(t *testing.T) {
 		t.Parallel()
 
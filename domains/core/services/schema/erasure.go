@@ -0,0 +1,22 @@
+package schema
+
+import (
+	"context"
+)
+
+// EraseFunc removes user data stored outside of the DB, e.g. uploaded files.
+type EraseFunc func(ctx context.Context) error
+
+// UserDataEraser is implemented by services storing user-related data outside of the DB.
+//
+// PrepareErasure is called on user account deletion before user record itself is deleted, so the data
+// can still be looked up. Returned function is called only after user record deletion is committed.
+// DB records of the user are expected to be removed with the user record.
+type UserDataEraser interface {
+	PrepareErasure(ctx context.Context, userUUID string) (EraseFunc, error)
+}
+
+// RequiresUserDataEraser defines service which can use user data eraser.
+type RequiresUserDataEraser interface {
+	UseUserDataEraser(eraser UserDataEraser)
+}
@@ -0,0 +1,39 @@
+package schema
+
+import (
+	"context"
+	"io"
+)
+
+// ExportedFile - file included into user data export, e.g. uploaded image.
+type ExportedFile struct {
+	// Name is a file name relative to the service directory in the export
+	Name string
+	// Open opens file content for reading
+	Open func(ctx context.Context) (io.ReadCloser, error)
+}
+
+// UserDataExport - part of user data stored by a single service.
+type UserDataExport struct {
+	// Data is JSON-serializable representation of user data
+	Data any
+	// Files are additional files stored by the service
+	Files []ExportedFile
+}
+
+// UserDataExporter is implemented by services storing user-related data.
+//
+// ExportUserData is called when user requests personal data export.
+type UserDataExporter interface {
+	ExportUserData(ctx context.Context, userUUID string) (*UserDataExport, error)
+}
+
+// UserDataCollector collects user data from all exporting services.
+type UserDataCollector interface {
+	CollectUserData(ctx context.Context, userUUID string) (map[ServiceID]*UserDataExport, error)
+}
+
+// RequiresUserDataCollector defines service which can use user data collector.
+type RequiresUserDataCollector interface {
+	UseUserDataCollector(collector UserDataCollector)
+}
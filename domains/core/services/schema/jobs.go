@@ -0,0 +1,19 @@
+package schema
+
+import (
+	"context"
+)
+
+// JobHandler - function processing single job with raw JSON payload.
+type JobHandler func(ctx context.Context, payload []byte) error
+
+// JobRegistry allows services to register background jobs.
+type JobRegistry interface {
+	// Handle registers handler for the jobs of given kind.
+	Handle(kind string, handler JobHandler) error
+	// Schedule registers periodic enqueuing of the job of given kind using cron expression.
+	Schedule(kind, spec string) error
+}
+
+// AddJobsFunc - function registering service jobs.
+type AddJobsFunc func(registry JobRegistry) error
@@ -34,6 +34,10 @@ type ServiceDefinition struct {
 	// after the service is initialized.
 	// Can remain `nil` if service does not provide API endpoints.
 	InitHandlersFunc func(state ProvidingServices) AddHandlersFunc
+	// InitJobsFunc is a function for registering service-related background jobs
+	// after the service is initialized.
+	// Can remain `nil` if service does not process background jobs.
+	InitJobsFunc func(state ProvidingServices) AddJobsFunc
 }
 
 // ServiceMapping - ServiceID to Service mapping.
@@ -43,3 +47,9 @@ type ServiceMapping map[ServiceID]any
 type ProvidingServices interface {
 	Service(id ServiceID) any
 }
+
+// RegistersServices describes state storing services as soon as they are initialized,
+// making them available to the services depending on them.
+type RegistersServices interface {
+	RegisterService(id ServiceID, service any)
+}
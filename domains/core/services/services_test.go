@@ -1,6 +1,7 @@
 package services
 
 import (
+	"crypto/rand"
 	"testing"
 
 	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
@@ -8,6 +9,23 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+const alphanum = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// randomString generates random string with given prefix.
+//
+// testhelpers can't be used here, as its fakes depend on service schemas importing this package.
+func randomString(prefix string, length int) string {
+	bytes := make([]byte, length)
+
+	_, _ = rand.Read(bytes)
+
+	for i, b := range bytes {
+		bytes[i] = alphanum[b%byte(len(alphanum))]
+	}
+
+	return prefix + string(bytes)
+}
+
 func TestGetServiceFromProvider(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,18 @@
+package testhelpers
+
+import (
+	"context"
+
+	notificationsSchema "github.com/outcatcher/anwil/domains/notifications/schema"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockNotifier - notifications publisher replacement for testing.
+type MockNotifier struct {
+	mock.Mock
+}
+
+// Notify mocks publishing notification.
+func (m *MockNotifier) Notify(ctx context.Context, notification notificationsSchema.Notification) error {
+	return m.Called(ctx, notification).Error(0)
+}
@@ -0,0 +1,26 @@
+package testhelpers
+
+import (
+	"context"
+
+	jobsSchema "github.com/outcatcher/anwil/domains/jobs/schema"
+	storageSchema "github.com/outcatcher/anwil/domains/storage/schema"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockQueue - jobs queue replacement for testing.
+//
+// Enqueue options and transactions are not passed to the mock.
+type MockQueue struct {
+	mock.Mock
+}
+
+// Enqueue mocks adding job to the queue.
+func (m *MockQueue) Enqueue(ctx context.Context, kind string, payload any, _ ...jobsSchema.EnqueueOption) error {
+	return m.Called(ctx, kind, payload).Error(0)
+}
+
+// DeleteJobs mocks removing jobs from the queue.
+func (m *MockQueue) DeleteJobs(ctx context.Context, _ storageSchema.QueryExecutor, kind string, payload any) error {
+	return m.Called(ctx, kind, payload).Error(0)
+}
@@ -0,0 +1,41 @@
+package testhelpers
+
+import (
+	"context"
+
+	usersSchema "github.com/outcatcher/anwil/domains/users/service/schema"
+)
+
+// FakeUsers - users service replacement for testing, returning a copy of User for any username or UUID.
+//
+// Empty UUID and username of the returned user are set to the requested value.
+// Only user lookup methods are implemented.
+type FakeUsers struct {
+	usersSchema.UserService
+
+	User usersSchema.User
+}
+
+// GetUser returns user with given username.
+func (f *FakeUsers) GetUser(_ context.Context, username string) (*usersSchema.User, error) {
+	return f.user(username), nil
+}
+
+// GetUserByUUID returns user with given UUID.
+func (f *FakeUsers) GetUserByUUID(_ context.Context, uuid string) (*usersSchema.User, error) {
+	return f.user(uuid), nil
+}
+
+func (f *FakeUsers) user(key string) *usersSchema.User {
+	user := f.User
+
+	if user.UUID == "" {
+		user.UUID = key
+	}
+
+	if user.Username == "" {
+		user.Username = key
+	}
+
+	return &user
+}
@@ -0,0 +1,6 @@
+/*
+Package currency contains functions and entities of currencies and exchange rates domain.
+
+Money amounts are always stored as integer minor units (e.g. cents) together with ISO 4217 currency code.
+*/
+package currency
@@ -0,0 +1,135 @@
+/*
+Package handlers contains API handlers for currency endpoints.
+*/
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/outcatcher/anwil/domains/core/negotiation"
+	"github.com/outcatcher/anwil/domains/core/services"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/outcatcher/anwil/domains/core/validation"
+	"github.com/outcatcher/anwil/domains/currency/schema"
+	"github.com/outcatcher/anwil/domains/users/auth"
+	usersSchema "github.com/outcatcher/anwil/domains/users/service/schema"
+)
+
+type totalRequest struct {
+	Amounts []schema.Money `json:"amounts" validate:"required,dive"`
+	// Currency is a currency of the total, preferred currency of the user is used if empty
+	Currency string `json:"currency" validate:"omitempty,iso4217"`
+}
+
+// AddCurrencyHandlers - adds currency-related endpoints.
+func AddCurrencyHandlers(state svcSchema.ProvidingServices) svcSchema.AddHandlersFunc {
+	return func(baseGroup, secGroup *echo.Group) error {
+		currencyService, err := services.GetServiceFromProvider[schema.CurrencyService](state, schema.ServiceID)
+		if err != nil {
+			return fmt.Errorf("error adding currency handlers: %w", err)
+		}
+
+		userService, err := services.GetServiceFromProvider[usersSchema.UserService](state, usersSchema.ServiceID)
+		if err != nil {
+			return fmt.Errorf("error adding currency handlers: %w", err)
+		}
+
+		baseGroup.GET("/currencies", handleListCurrencies(currencyService))
+
+		secGroup.GET("/currencies/convert", handleConvert(currencyService, userService))
+		secGroup.POST(
+			"/currencies/total",
+			handleTotal(currencyService, userService),
+			negotiation.Consumes(negotiation.MIMEJSON, negotiation.MIMEMsgpack),
+		)
+
+		return nil
+	}
+}
+
+// targetCurrency returns requested currency or preferred currency of the current user if empty.
+func targetCurrency(c echo.Context, usr usersSchema.UserService, requested string) (string, error) {
+	if requested != "" {
+		return requested, nil
+	}
+
+	claims, err := auth.ClaimsFromContext(c)
+	if err != nil {
+		return "", fmt.Errorf("error getting preferred currency: %w", err)
+	}
+
+	user, err := usr.GetUser(c.Request().Context(), claims.Username)
+	if err != nil {
+		return "", fmt.Errorf("error getting preferred currency: %w", err)
+	}
+
+	return user.Currency, nil
+}
+
+func handleListCurrencies(cur schema.CurrencyService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		currencies, err := cur.Currencies(c.Request().Context())
+		if err != nil {
+			return fmt.Errorf("error listing currencies: %w", err)
+		}
+
+		return negotiation.Respond(c, http.StatusOK, currencies)
+	}
+}
+
+// handleConvert converts amount given in minor units.
+func handleConvert(cur schema.CurrencyService, usr usersSchema.UserService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		amount, err := strconv.ParseInt(c.QueryParam("amount"), 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: query parameter 'amount' should be integer amount in minor units",
+				validation.ErrValidationFailed)
+		}
+
+		from := c.QueryParam("from")
+		if from == "" {
+			return fmt.Errorf("%w: query parameter 'from' is missing", validation.ErrValidationFailed)
+		}
+
+		currency, err := targetCurrency(c, usr, c.QueryParam("to"))
+		if err != nil {
+			return fmt.Errorf("error converting money: %w", err)
+		}
+
+		converted, err := cur.Convert(c.Request().Context(), schema.Money{Amount: amount, Currency: from}, currency)
+		if err != nil {
+			return fmt.Errorf("error converting money: %w", err)
+		}
+
+		return negotiation.Respond(c, http.StatusOK, converted)
+	}
+}
+
+func handleTotal(cur schema.CurrencyService, usr usersSchema.UserService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := new(totalRequest)
+
+		if err := c.Bind(req); err != nil {
+			return fmt.Errorf("error binding request: %w", err)
+		}
+
+		if err := validation.ValidateJSONCtx(c.Request().Context(), req); err != nil {
+			return fmt.Errorf("error validating request: %w", err)
+		}
+
+		currency, err := targetCurrency(c, usr, req.Currency)
+		if err != nil {
+			return fmt.Errorf("error calculating total: %w", err)
+		}
+
+		total, err := cur.Total(c.Request().Context(), req.Amounts, currency)
+		if err != nil {
+			return fmt.Errorf("error calculating total: %w", err)
+		}
+
+		return negotiation.Respond(c, http.StatusOK, total)
+	}
+}
@@ -0,0 +1,39 @@
+package rates
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ratesFile - contents of the rates file. Rates are decimal strings or numbers.
+//
+// File can be written either in YAML or in JSON.
+type ratesFile struct {
+	Base  string            `yaml:"base"`
+	Rates map[string]string `yaml:"rates"`
+}
+
+// fileProvider loads rates from local file, can be used offline.
+type fileProvider struct {
+	path string
+}
+
+// Rates returns rates stored in the file.
+func (f *fileProvider) Rates(context.Context) (*Rates, error) {
+	data, err := os.ReadFile(filepath.Clean(f.path))
+	if err != nil {
+		return nil, fmt.Errorf("error reading rates file: %w", err)
+	}
+
+	contents := new(ratesFile)
+
+	if err := yaml.Unmarshal(data, contents); err != nil {
+		return nil, fmt.Errorf("error parsing rates file: %w", err)
+	}
+
+	return newRates(contents.Base, contents.Rates)
+}
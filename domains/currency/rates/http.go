@@ -0,0 +1,73 @@
+package rates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	httpTimeout = 30 * time.Second
+	// maxResponseSize is a max size of rates API response
+	maxResponseSize = 1 << 20
+)
+
+// ratesResponse - response of rates API, e.g. https://api.frankfurter.app/latest.
+type ratesResponse struct {
+	Base  string                 `json:"base"`
+	Rates map[string]json.Number `json:"rates"`
+}
+
+// httpProvider loads rates from rates API.
+type httpProvider struct {
+	url    string
+	client *http.Client
+}
+
+func newHTTPProvider(url string) *httpProvider {
+	return &httpProvider{
+		url:    url,
+		client: &http.Client{Timeout: httpTimeout}, //nolint:exhaustruct
+	}
+}
+
+// Rates returns rates loaded from API.
+func (h *httpProvider) Rates(ctx context.Context) (*Rates, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.url, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("error creating rates request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting rates: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: rates API responded with status %d", errInvalidRates, resp.StatusCode)
+	}
+
+	decoder := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize))
+	decoder.UseNumber()
+
+	contents := new(ratesResponse)
+
+	if err := decoder.Decode(contents); err != nil {
+		return nil, fmt.Errorf("error decoding rates response: %w", err)
+	}
+
+	values := make(map[string]string, len(contents.Rates))
+
+	for code, rate := range contents.Rates {
+		values[code] = rate.String()
+	}
+
+	return newRates(contents.Base, values)
+}
@@ -0,0 +1,103 @@
+/*
+Package rates contains exchange rate providers.
+*/
+package rates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
+	"github.com/outcatcher/anwil/domains/currency/schema"
+)
+
+// Supported providers.
+const (
+	ProviderFile = "file"
+	ProviderHTTP = "http"
+)
+
+var (
+	errUnknownProvider = errors.New("unknown rates provider")
+	errInvalidRates    = errors.New("invalid rates")
+)
+
+// Rates - exchange rates relative to the base currency.
+type Rates struct {
+	Base string
+	// Values hold number of currency units per unit of base currency, including base currency itself
+	Values map[string]*big.Rat
+}
+
+// Provider loads actual exchange rates.
+type Provider interface {
+	// Rates returns actual exchange rates.
+	Rates(ctx context.Context) (*Rates, error)
+}
+
+// New creates rates provider using configured driver.
+//
+// If neither provider nor rates file is configured, only default currency is available.
+func New(cfg configSchema.CurrencyConfiguration) (Provider, error) {
+	if cfg.Provider == "" && cfg.File == "" {
+		return &staticProvider{base: cfg.DefaultCurrency()}, nil
+	}
+
+	switch cfg.Provider {
+	case ProviderFile, "":
+		if cfg.File == "" {
+			return nil, fmt.Errorf("%w: rates file is not configured", errInvalidRates)
+		}
+
+		return &fileProvider{path: cfg.File}, nil
+	case ProviderHTTP:
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("%w: rates URL is not configured", errInvalidRates)
+		}
+
+		return newHTTPProvider(cfg.URL), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownProvider, cfg.Provider)
+	}
+}
+
+// newRates validates and converts decimal rates.
+func newRates(base string, values map[string]string) (*Rates, error) {
+	base = strings.ToUpper(base)
+
+	if err := schema.ValidateCurrency(base); err != nil {
+		return nil, fmt.Errorf("%w: base currency: %s", errInvalidRates, err.Error())
+	}
+
+	result := &Rates{Base: base, Values: map[string]*big.Rat{base: big.NewRat(1, 1)}}
+
+	for code, value := range values {
+		code = strings.ToUpper(code)
+
+		if err := schema.ValidateCurrency(code); err != nil {
+			return nil, fmt.Errorf("%w: %s", errInvalidRates, err.Error())
+		}
+
+		rate, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+		if !ok || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("%w: invalid rate %q of %s", errInvalidRates, value, code)
+		}
+
+		result.Values[code] = rate
+	}
+
+	return result, nil
+}
+
+// staticProvider provides only the base currency.
+type staticProvider struct {
+	base string
+}
+
+// Rates returns base currency rate.
+func (s *staticProvider) Rates(context.Context) (*Rates, error) {
+	return newRates(s.base, nil)
+}
@@ -0,0 +1,114 @@
+package rates
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func requireRates(t *testing.T, expected map[string]string, actual *Rates) {
+	t.Helper()
+
+	require.Len(t, actual.Values, len(expected))
+
+	for code, value := range expected {
+		rate, ok := new(big.Rat).SetString(value)
+		require.True(t, ok)
+		require.Zero(t, rate.Cmp(actual.Values[code]), "rate of %s: %s", code, actual.Values[code])
+	}
+}
+
+func TestFileProvider(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"rates.yaml": "base: EUR\nrates:\n  USD: 1.0832\n  rub: \"98.123456789012345\"\n",
+		"rates.json": `{"base": "EUR", "rates": {"USD": 1.0832, "RUB": 98.123456789012345}}`,
+	}
+
+	for name, contents := range cases {
+		name, contents := name, contents
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), name)
+			require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+
+			provider, err := New(configSchema.CurrencyConfiguration{Provider: ProviderFile, File: path})
+			require.NoError(t, err)
+
+			rates, err := provider.Rates(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, "EUR", rates.Base)
+			requireRates(t, map[string]string{"EUR": "1", "USD": "1.0832", "RUB": "98.123456789012345"}, rates)
+		})
+	}
+}
+
+func TestFileProviderInvalid(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"base":     "base: EURO\nrates:\n  USD: 1\n",
+		"currency": "base: EUR\nrates:\n  DOLLAR: 1\n",
+		"negative": "base: EUR\nrates:\n  USD: -1\n",
+		"text":     "base: EUR\nrates:\n  USD: much\n",
+	}
+
+	for name, contents := range cases {
+		name, contents := name, contents
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "rates.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+
+			_, err := (&fileProvider{path: path}).Rates(context.Background())
+			require.ErrorIs(t, err, errInvalidRates)
+		})
+	}
+}
+
+func TestHTTPProvider(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"amount":1.0,"base":"USD","date":"2023-04-01","rates":{"EUR":0.92,"JPY":132.86}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	provider, err := New(configSchema.CurrencyConfiguration{Provider: ProviderHTTP, URL: server.URL})
+	require.NoError(t, err)
+
+	rates, err := provider.Rates(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "USD", rates.Base)
+	requireRates(t, map[string]string{"USD": "1", "EUR": "0.92", "JPY": "132.86"}, rates)
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	provider, err := New(configSchema.CurrencyConfiguration{Default: "rub"})
+	require.NoError(t, err)
+
+	rates, err := provider.Rates(context.Background())
+	require.NoError(t, err)
+	requireRates(t, map[string]string{"RUB": "1"}, rates)
+
+	_, err = New(configSchema.CurrencyConfiguration{Provider: "ftp"})
+	require.ErrorIs(t, err, errUnknownProvider)
+
+	_, err = New(configSchema.CurrencyConfiguration{Provider: ProviderHTTP})
+	require.ErrorIs(t, err, errInvalidRates)
+}
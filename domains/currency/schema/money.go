@@ -0,0 +1,105 @@
+package schema
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"sync"
+
+	v10 "github.com/go-playground/validator/v10"
+	"github.com/outcatcher/anwil/domains/core/validation"
+)
+
+// minorUnits - ISO 4217 currencies with number of minor units other than 2.
+var minorUnits = map[string]int{ //nolint:gochecknoglobals
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0,
+	"RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLF": 4, "UYW": 4,
+}
+
+var (
+	currencyValidate     *v10.Validate //nolint:gochecknoglobals
+	currencyValidateOnce sync.Once     //nolint:gochecknoglobals
+)
+
+// ValidateCurrency checks if code is a valid ISO 4217 currency code.
+func ValidateCurrency(code string) error {
+	currencyValidateOnce.Do(func() {
+		currencyValidate = v10.New()
+	})
+
+	if err := currencyValidate.Var(code, "required,iso4217"); err != nil {
+		return fmt.Errorf("%w: invalid currency code %q", validation.ErrValidationFailed, code)
+	}
+
+	return nil
+}
+
+// MinorUnits returns number of digits after decimal separator for the currency.
+func MinorUnits(code string) int {
+	if units, ok := minorUnits[code]; ok {
+		return units
+	}
+
+	return 2 //nolint:gomnd
+}
+
+// minorUnitsScale returns number of minor units in a single major unit, i.e. 100 for EUR.
+func minorUnitsScale(code string) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(MinorUnits(code))), nil) //nolint:gomnd
+}
+
+// Money - amount of money in minor units of the currency, e.g. 1250 EUR is 12.50 EUR.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency" validate:"required"`
+}
+
+// Rat returns amount in major units.
+func (m Money) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(big.NewInt(m.Amount), minorUnitsScale(m.Currency))
+}
+
+// Decimal returns amount in major units as decimal string, i.e. "12.50".
+func (m Money) Decimal() string {
+	return m.Rat().FloatString(MinorUnits(m.Currency))
+}
+
+// String returns amount with currency, i.e. "12.50 EUR".
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+// FromRat returns money with given amount in major units, rounded half away from zero to minor units.
+func FromRat(amount *big.Rat, currency string) (Money, error) {
+	if err := ValidateCurrency(currency); err != nil {
+		return Money{}, err
+	}
+
+	scaled := new(big.Rat).Mul(amount, new(big.Rat).SetInt(minorUnitsScale(currency)))
+
+	// rounding half away from zero: trunc(x + sign(x)/2)
+	half := big.NewRat(int64(scaled.Sign()), 2) //nolint:gomnd
+	shifted := new(big.Rat).Add(scaled, half)
+	rounded := new(big.Int).Quo(shifted.Num(), shifted.Denom())
+
+	if !rounded.IsInt64() || rounded.Int64() == math.MinInt64 {
+		return Money{}, fmt.Errorf("%w: amount is too large", validation.ErrValidationFailed)
+	}
+
+	return Money{Amount: rounded.Int64(), Currency: currency}, nil
+}
+
+// ParseMoney parses decimal amount in major units, i.e. "12.50".
+//
+// Amount is rounded to minor units of the currency.
+func ParseMoney(amount, currency string) (Money, error) {
+	value, ok := new(big.Rat).SetString(strings.TrimSpace(amount))
+	if !ok || strings.ContainsAny(amount, "/eE") {
+		return Money{}, fmt.Errorf("%w: invalid amount %q", validation.ErrValidationFailed, amount)
+	}
+
+	return FromRat(value, currency)
+}
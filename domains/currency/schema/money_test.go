@@ -0,0 +1,77 @@
+package schema
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/outcatcher/anwil/domains/core/validation"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoneyDecimal(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		money    Money
+		expected string
+	}{
+		{Money{Amount: 1250, Currency: "EUR"}, "12.50"},
+		{Money{Amount: -5, Currency: "USD"}, "-0.05"},
+		{Money{Amount: 1250, Currency: "JPY"}, "1250"},
+		{Money{Amount: 1250, Currency: "KWD"}, "1.250"},
+	}
+
+	for _, data := range cases {
+		data := data
+
+		t.Run(data.money.String(), func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, data.expected, data.money.Decimal())
+		})
+	}
+}
+
+func TestFromRat(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		amount   *big.Rat
+		currency string
+		expected int64
+	}{
+		{big.NewRat(1, 3), "EUR", 33},
+		{big.NewRat(1, 200), "EUR", 1},     // 0.005 rounds up
+		{big.NewRat(-1, 200), "EUR", -1},   // half away from zero
+		{big.NewRat(1249, 10), "JPY", 125}, // 124.9
+		{big.NewRat(12345, 10000), "BHD", 1235},
+	}
+
+	for _, data := range cases {
+		data := data
+
+		t.Run(data.amount.String()+data.currency, func(t *testing.T) {
+			t.Parallel()
+
+			money, err := FromRat(data.amount, data.currency)
+			require.NoError(t, err)
+			require.Equal(t, Money{Amount: data.expected, Currency: data.currency}, money)
+		})
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	t.Parallel()
+
+	money, err := ParseMoney("19.99", "USD")
+	require.NoError(t, err)
+	require.Equal(t, Money{Amount: 1999, Currency: "USD"}, money)
+
+	for _, invalid := range [][2]string{
+		{"1/3", "USD"}, {"1e3", "USD"}, {"abc", "USD"}, {"1", "XXY"}, {"1", "usd"}, {"1e30", "EUR"},
+		{"92233720368547758.08", "EUR"},
+	} {
+		_, err := ParseMoney(invalid[0], invalid[1])
+		require.ErrorIs(t, err, validation.ErrValidationFailed, invalid)
+	}
+}
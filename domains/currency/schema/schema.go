@@ -0,0 +1,39 @@
+/*
+Package schema contains service definition for Currency service
+*/
+package schema
+
+import (
+	"context"
+	"time"
+
+	"github.com/outcatcher/anwil/domains/core/services/schema"
+)
+
+// ServiceID - ID for currency service.
+const ServiceID schema.ServiceID = "currency"
+
+// CurrencyService - service converting money between currencies.
+type CurrencyService interface {
+	// Currencies returns all currencies with known exchange rates.
+	Currencies(ctx context.Context) ([]Currency, error)
+	// Convert converts amount to the given currency.
+	Convert(ctx context.Context, amount Money, currency string) (Money, error)
+	// Total returns sum of the amounts converted to the given currency.
+	//
+	// Amounts are summed before rounding, so total can differ from sum of converted amounts.
+	Total(ctx context.Context, amounts []Money, currency string) (Money, error)
+	// DefaultCurrency returns currency used for users without preferred currency.
+	DefaultCurrency() string
+}
+
+// Currency holds exchange rate of the currency.
+type Currency struct {
+	// Code is ISO 4217 currency code
+	Code string `json:"code"`
+	// MinorUnits is a number of digits after decimal separator
+	MinorUnits int `json:"minor_units"`
+	// Rate is a decimal number of currency units per unit of base currency of the rates
+	Rate      string    `json:"rate"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/outcatcher/anwil/domains/core/validation"
+	"github.com/outcatcher/anwil/domains/currency/schema"
+)
+
+// rateTable - exchange rates by currency code.
+type rateTable map[string]*big.Rat
+
+// loadRates returns all stored rates.
+func (s *service) loadRates(ctx context.Context) (rateTable, error) {
+	stored, err := s.storage.ListRates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error loading exchange rates: %w", err)
+	}
+
+	table := make(rateTable, len(stored))
+
+	for _, rate := range stored {
+		value, ok := new(big.Rat).SetString(rate.Rate)
+		if !ok {
+			return nil, fmt.Errorf("error loading exchange rates: invalid stored rate %q of %s", rate.Rate, rate.Currency)
+		}
+
+		table[rate.Currency] = value
+	}
+
+	return table, nil
+}
+
+// rate returns rate of the currency.
+func (t rateTable) rate(currency string) (*big.Rat, error) {
+	if err := schema.ValidateCurrency(currency); err != nil {
+		return nil, err
+	}
+
+	rate, ok := t[currency]
+	if !ok {
+		return nil, fmt.Errorf("%w: no exchange rate for %s", validation.ErrValidationFailed, currency)
+	}
+
+	return rate, nil
+}
+
+// convert returns not rounded amount in major units of the target currency.
+func (t rateTable) convert(amount schema.Money, currency string) (*big.Rat, error) {
+	if amount.Currency == currency {
+		return amount.Rat(), nil
+	}
+
+	fromRate, err := t.rate(amount.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	toRate, err := t.rate(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(big.Rat).Mul(amount.Rat(), toRate)
+
+	return result.Quo(result, fromRate), nil
+}
+
+// Currencies returns all currencies with known exchange rates.
+func (s *service) Currencies(ctx context.Context) ([]schema.Currency, error) {
+	stored, err := s.storage.ListRates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing currencies: %w", err)
+	}
+
+	result := make([]schema.Currency, len(stored))
+
+	for i, rate := range stored {
+		result[i] = schema.Currency{
+			Code:       rate.Currency,
+			MinorUnits: schema.MinorUnits(rate.Currency),
+			Rate:       rate.Rate,
+			UpdatedAt:  rate.UpdatedAt,
+		}
+	}
+
+	return result, nil
+}
+
+// Convert converts amount to the given currency.
+func (s *service) Convert(ctx context.Context, amount schema.Money, currency string) (schema.Money, error) {
+	return s.Total(ctx, []schema.Money{amount}, currency)
+}
+
+// Total returns sum of the amounts converted to the given currency.
+func (s *service) Total(ctx context.Context, amounts []schema.Money, currency string) (schema.Money, error) {
+	currency = strings.ToUpper(currency)
+
+	if err := schema.ValidateCurrency(currency); err != nil {
+		return schema.Money{}, fmt.Errorf("error converting money: %w", err)
+	}
+
+	table, err := s.loadRates(ctx)
+	if err != nil {
+		return schema.Money{}, fmt.Errorf("error converting money: %w", err)
+	}
+
+	sum := new(big.Rat)
+
+	for _, amount := range amounts {
+		amount.Currency = strings.ToUpper(amount.Currency)
+
+		converted, err := table.convert(amount, currency)
+		if err != nil {
+			return schema.Money{}, fmt.Errorf("error converting money: %w", err)
+		}
+
+		sum.Add(sum, converted)
+	}
+
+	total, err := schema.FromRat(sum, currency)
+	if err != nil {
+		return schema.Money{}, fmt.Errorf("error converting money: %w", err)
+	}
+
+	return total, nil
+}
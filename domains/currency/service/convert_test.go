@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log"
+	"math/big"
+	"testing"
+
+	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
+	"github.com/outcatcher/anwil/domains/core/validation"
+	"github.com/outcatcher/anwil/domains/currency/rates"
+	"github.com/outcatcher/anwil/domains/currency/schema"
+	ratesStorage "github.com/outcatcher/anwil/domains/currency/storage"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRates - in-memory rates storage.
+type memoryRates struct {
+	rates []ratesStorage.ExchangeRate
+}
+
+func (m *memoryRates) ListRates(context.Context) ([]ratesStorage.ExchangeRate, error) {
+	return m.rates, nil
+}
+
+func (m *memoryRates) ReplaceRates(_ context.Context, rates []ratesStorage.ExchangeRate) error {
+	m.rates = rates
+
+	return nil
+}
+
+// staticRates - rates provider returning fixed rates.
+type staticRates map[string]string
+
+func (s staticRates) Rates(context.Context) (*rates.Rates, error) {
+	result := &rates.Rates{Base: "EUR", Values: make(map[string]*big.Rat, len(s))}
+
+	for code, value := range s {
+		rat, _ := new(big.Rat).SetString(value)
+		result.Values[code] = rat
+	}
+
+	return result, nil
+}
+
+func newTestService(t *testing.T) *service {
+	t.Helper()
+
+	svc := &service{
+		cfg:     new(configSchema.Configuration),
+		storage: new(memoryRates),
+		log:     log.New(io.Discard, "", 0),
+		queue:   nil,
+		provider: staticRates{
+			"EUR": "1",
+			"USD": "1.08",
+			"RUB": "98.5",
+			"JPY": "160",
+		},
+	}
+
+	require.NoError(t, svc.refreshRates(context.Background(), nil))
+
+	return svc
+}
+
+func TestRefreshRates(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestService(t)
+
+	currencies, err := svc.Currencies(context.Background())
+	require.NoError(t, err)
+	require.Len(t, currencies, 4)
+
+	require.Equal(t, "EUR", currencies[0].Code)
+	require.Equal(t, "1", currencies[0].Rate)
+	require.Equal(t, "JPY", currencies[1].Code)
+	require.Equal(t, 0, currencies[1].MinorUnits)
+	require.Equal(t, "98.5", currencies[2].Rate)
+}
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	cases := []struct {
+		amount   schema.Money
+		currency string
+		expected schema.Money
+	}{
+		{schema.Money{Amount: 1000, Currency: "EUR"}, "USD", schema.Money{Amount: 1080, Currency: "USD"}},
+		{schema.Money{Amount: 1080, Currency: "USD"}, "eur", schema.Money{Amount: 1000, Currency: "EUR"}},
+		{schema.Money{Amount: 100, Currency: "USD"}, "JPY", schema.Money{Amount: 148, Currency: "JPY"}},
+		{schema.Money{Amount: 148, Currency: "JPY"}, "RUB", schema.Money{Amount: 9111, Currency: "RUB"}},
+		{schema.Money{Amount: 77, Currency: "RUB"}, "RUB", schema.Money{Amount: 77, Currency: "RUB"}},
+	}
+
+	for _, data := range cases {
+		data := data
+
+		t.Run(data.amount.String()+" to "+data.currency, func(t *testing.T) {
+			t.Parallel()
+
+			converted, err := svc.Convert(ctx, data.amount, data.currency)
+			require.NoError(t, err)
+			require.Equal(t, data.expected, converted)
+		})
+	}
+}
+
+func TestTotal(t *testing.T) {
+	t.Parallel()
+
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	// 3 x 0.01 RUB is 0.0003 EUR, rounded only once
+	total, err := svc.Total(ctx, []schema.Money{
+		{Amount: 1, Currency: "RUB"},
+		{Amount: 1, Currency: "RUB"},
+		{Amount: 1, Currency: "RUB"},
+		{Amount: 500, Currency: "EUR"},
+		{Amount: 108, Currency: "USD"},
+	}, "EUR")
+	require.NoError(t, err)
+	require.Equal(t, schema.Money{Amount: 600, Currency: "EUR"}, total)
+
+	empty, err := svc.Total(ctx, nil, "USD")
+	require.NoError(t, err)
+	require.Equal(t, schema.Money{Amount: 0, Currency: "USD"}, empty)
+
+	_, err = svc.Total(ctx, []schema.Money{{Amount: 1, Currency: "GBP"}}, "EUR")
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+
+	_, err = svc.Total(ctx, []schema.Money{{Amount: 1, Currency: "EUR"}}, "EURO")
+	require.ErrorIs(t, err, validation.ErrValidationFailed)
+}
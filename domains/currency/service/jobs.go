@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/outcatcher/anwil/domains/core/services"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/outcatcher/anwil/domains/currency/schema"
+	ratesStorage "github.com/outcatcher/anwil/domains/currency/storage"
+)
+
+const (
+	// ratePrecision is a max number of digits after decimal separator of stored rates
+	ratePrecision = 12
+
+	refreshJobKind         = "currency.refresh_rates"
+	defaultRefreshSchedule = "@daily"
+)
+
+// addCurrencyJobs registers currency service jobs.
+func addCurrencyJobs(state svcSchema.ProvidingServices) svcSchema.AddJobsFunc {
+	return func(registry svcSchema.JobRegistry) error {
+		svc, err := services.GetServiceFromProvider[*service](state, schema.ServiceID)
+		if err != nil {
+			return fmt.Errorf("error adding currency jobs: %w", err)
+		}
+
+		if err := registry.Handle(refreshJobKind, svc.refreshRates); err != nil {
+			return fmt.Errorf("error adding currency jobs: %w", err)
+		}
+
+		schedule := svc.cfg.Currency.Schedule
+		if schedule == "" {
+			schedule = defaultRefreshSchedule
+		}
+
+		if err := registry.Schedule(refreshJobKind, schedule); err != nil {
+			return fmt.Errorf("error adding currency jobs: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// refreshRates replaces stored exchange rates with the ones loaded from provider.
+func (s *service) refreshRates(ctx context.Context, _ []byte) error {
+	loaded, err := s.provider.Rates(ctx)
+	if err != nil {
+		return fmt.Errorf("error refreshing exchange rates: %w", err)
+	}
+
+	rates := make([]ratesStorage.ExchangeRate, 0, len(loaded.Values))
+
+	for code, rate := range loaded.Values {
+		rates = append(rates, ratesStorage.ExchangeRate{ //nolint:exhaustruct
+			Currency: code,
+			Rate:     decimalString(rate),
+		})
+	}
+
+	sort.Slice(rates, func(i, j int) bool { return rates[i].Currency < rates[j].Currency })
+
+	if err := s.storage.ReplaceRates(ctx, rates); err != nil {
+		return fmt.Errorf("error refreshing exchange rates: %w", err)
+	}
+
+	s.log.Printf("%d exchange rates relative to %s refreshed", len(rates), loaded.Base)
+
+	return nil
+}
+
+// decimalString returns rate as decimal string without trailing zeros.
+func decimalString(rate *big.Rat) string {
+	value := rate.FloatString(ratePrecision)
+
+	if strings.Contains(value, ".") {
+		value = strings.TrimRight(strings.TrimRight(value, "0"), ".")
+	}
+
+	return value
+}
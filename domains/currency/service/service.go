@@ -0,0 +1,98 @@
+/*
+Package service contains currency service methods
+*/
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
+	logSchema "github.com/outcatcher/anwil/domains/core/logging/schema"
+	"github.com/outcatcher/anwil/domains/core/services"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/outcatcher/anwil/domains/currency/handlers"
+	"github.com/outcatcher/anwil/domains/currency/rates"
+	"github.com/outcatcher/anwil/domains/currency/schema"
+	ratesStorage "github.com/outcatcher/anwil/domains/currency/storage"
+	jobsSchema "github.com/outcatcher/anwil/domains/jobs/schema"
+	storageSchema "github.com/outcatcher/anwil/domains/storage/schema"
+)
+
+// service - currency service.
+type service struct {
+	cfg     *configSchema.Configuration
+	storage ratesStorage.RatesStorage
+
+	log      *log.Logger
+	queue    jobsSchema.Queue
+	provider rates.Provider
+}
+
+// UseConfig attaches configuration to the service.
+func (s *service) UseConfig(configuration *configSchema.Configuration) {
+	s.cfg = configuration
+}
+
+// UseStorage attaches given DB storage to the service.
+func (s *service) UseStorage(db storageSchema.QueryExecutor) {
+	s.storage = ratesStorage.New(db)
+}
+
+// UseLogger attaches logger to the service.
+func (s *service) UseLogger(logger *log.Logger) {
+	s.log = logger
+}
+
+// UseJobQueue attaches background job queue to the service.
+func (s *service) UseJobQueue(queue jobsSchema.Queue) {
+	s.queue = queue
+}
+
+// DefaultCurrency returns currency used for users without preferred currency.
+func (s *service) DefaultCurrency() string {
+	return s.cfg.Currency.DefaultCurrency()
+}
+
+func currencyServiceInit(ctx context.Context, state any) (any, error) {
+	svc := new(service)
+
+	err := services.InjectServiceWith(
+		svc, state,
+		storageSchema.StorageInject,
+		logSchema.LoggerInject,
+		configSchema.ConfigInject,
+		jobsSchema.JobQueueInject,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing currency service: %w", err)
+	}
+
+	if err := schema.ValidateCurrency(svc.DefaultCurrency()); err != nil {
+		return nil, fmt.Errorf("error initializing currency service: default currency: %w", err)
+	}
+
+	svc.provider, err = rates.New(svc.cfg.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing currency service: %w", err)
+	}
+
+	// rates are refreshed on each start, so changes of the provider are applied without waiting for schedule
+	if err := svc.queue.Enqueue(ctx, refreshJobKind, nil); err != nil {
+		return nil, fmt.Errorf("error initializing currency service: %w", err)
+	}
+
+	return svc, nil
+}
+
+// NewCurrencyService returns new currency service definition.
+func NewCurrencyService() svcSchema.ServiceDefinition {
+	return svcSchema.ServiceDefinition{
+		ID:               schema.ServiceID,
+		Init:             currencyServiceInit,
+		DependsOn:        nil,
+		InitHandlersFunc: handlers.AddCurrencyHandlers,
+		InitJobsFunc:     addCurrencyJobs,
+	}
+}
@@ -0,0 +1,11 @@
+package storage
+
+import "time"
+
+// ExchangeRate - entity of `exchange_rates` table.
+type ExchangeRate struct {
+	Currency string `db:"currency"`
+	// Rate is a decimal number of currency units per unit of base currency
+	Rate      string    `db:"rate"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
@@ -0,0 +1,63 @@
+/*
+Package storage contains db-related operations with exchange rates.
+*/
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	storageSchema "github.com/outcatcher/anwil/domains/storage/schema"
+)
+
+// ratesStorage - storage of exchange rates.
+type ratesStorage struct {
+	db storageSchema.QueryExecutor
+}
+
+// New creates a new RatesStorage instance.
+func New(db storageSchema.QueryExecutor) RatesStorage {
+	return &ratesStorage{db: db}
+}
+
+// ListRates returns all stored rates ordered by currency.
+func (r *ratesStorage) ListRates(ctx context.Context) ([]ExchangeRate, error) {
+	var rates []ExchangeRate
+
+	err := sqlx.SelectContext(ctx, r.db, &rates, `SELECT * FROM exchange_rates ORDER BY currency;`)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting exchange rates: %w", err)
+	}
+
+	return rates, nil
+}
+
+// ReplaceRates replaces all stored rates with the given ones in a single statement.
+func (r *ratesStorage) ReplaceRates(ctx context.Context, rates []ExchangeRate) error {
+	currencies := make([]string, len(rates))
+	values := make([]string, len(rates))
+
+	for i, rate := range rates {
+		currencies[i] = rate.Currency
+		values[i] = rate.Rate
+	}
+
+	_, err := r.db.ExecContext(
+		ctx,
+		`WITH new_rates AS (SELECT * FROM unnest($1::VARCHAR[], $2::NUMERIC[]) AS t (currency, rate)),
+		      upserted AS (
+		          INSERT INTO exchange_rates (currency, rate, updated_at)
+		              SELECT currency, rate, now() FROM new_rates
+		          ON CONFLICT (currency) DO UPDATE SET rate = excluded.rate, updated_at = excluded.updated_at
+		      )
+		 DELETE FROM exchange_rates WHERE currency NOT IN (SELECT currency FROM new_rates);`,
+		pq.Array(currencies), pq.Array(values),
+	)
+	if err != nil {
+		return fmt.Errorf("error replacing exchange rates: %w", err)
+	}
+
+	return nil
+}
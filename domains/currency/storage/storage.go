@@ -0,0 +1,10 @@
+package storage
+
+import "context"
+
+// RatesStorage - storage of exchange rates.
+type RatesStorage interface {
+	ListRates(ctx context.Context) ([]ExchangeRate, error)
+	// ReplaceRates replaces all stored rates with the given ones.
+	ReplaceRates(ctx context.Context, rates []ExchangeRate) error
+}
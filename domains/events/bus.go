@@ -0,0 +1,260 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/outcatcher/anwil/domains/events/schema"
+	"github.com/outcatcher/anwil/domains/jobs"
+	jobsSchema "github.com/outcatcher/anwil/domains/jobs/schema"
+	storageSchema "github.com/outcatcher/anwil/domains/storage/schema"
+)
+
+const (
+	// handlerTimeout - max duration of handling event by async subscribers
+	handlerTimeout = time.Minute
+
+	// relayJobKind - kind of the job delivering event published in transaction
+	relayJobKind = "events.relay"
+)
+
+// subscriber - registered event handler.
+type subscriber struct {
+	mode    schema.Mode
+	handler schema.Handler
+}
+
+// relayPayload - payload of the job delivering event published in transaction.
+type relayPayload struct {
+	Type  string          `json:"type"`
+	Event json.RawMessage `json:"event"`
+}
+
+// Bus - in-process domain event bus with transactional outbox.
+//
+// Job queue serves as the outbox: events published in transactions are delivered by background jobs.
+type Bus struct {
+	queue jobsSchema.Queue
+	log   *log.Logger
+
+	maxAttempts int
+
+	mu          sync.RWMutex
+	subscribers map[string][]subscriber
+
+	// async tracks running async subscribers
+	async sync.WaitGroup
+}
+
+// New creates new event bus using given job queue as the outbox.
+func New(cfg configSchema.EventsConfiguration, queue jobsSchema.Queue, logger *log.Logger) *Bus {
+	return &Bus{
+		queue:       queue,
+		log:         logger,
+		maxAttempts: cfg.MaxAttempts,
+		subscribers: make(map[string][]subscriber),
+	}
+}
+
+// AddJobs registers job delivering events published in transactions.
+func (b *Bus) AddJobs(registry svcSchema.JobRegistry) error {
+	if err := registry.Handle(relayJobKind, jobs.Typed(b.relay)); err != nil {
+		return fmt.Errorf("error adding event bus jobs: %w", err)
+	}
+
+	return nil
+}
+
+// Subscribe registers handler for the events of given type.
+func (b *Bus) Subscribe(eventType string, mode schema.Mode, handler schema.Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscribers[eventType] = append(b.subscribers[eventType], subscriber{mode: mode, handler: handler})
+}
+
+// Publish delivers event to the subscribers immediately.
+//
+// Sync subscribers are called first, async subscribers are started only if all sync subscribers succeeded.
+func (b *Bus) Publish(ctx context.Context, event schema.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error encoding event %s: %w", event.EventType(), err)
+	}
+
+	if err := b.dispatch(ctx, event.EventType(), payload); err != nil {
+		return fmt.Errorf("error publishing event: %w", err)
+	}
+
+	return nil
+}
+
+// PublishTx enqueues delivery of the event using given transaction.
+//
+// Event is delivered by background job after the transaction is committed and is dropped on rollback.
+// Job waits for both sync and async subscribers and is retried if any of them fails,
+// so subscribers can get the same event more than once.
+func (b *Bus) PublishTx(ctx context.Context, tx storageSchema.QueryExecutor, event schema.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error encoding event %s: %w", event.EventType(), err)
+	}
+
+	opts := []jobsSchema.EnqueueOption{jobsSchema.InTx(tx)}
+
+	if b.maxAttempts > 0 {
+		opts = append(opts, jobsSchema.MaxAttempts(b.maxAttempts))
+	}
+
+	err = b.queue.Enqueue(ctx, relayJobKind, relayPayload{Type: event.EventType(), Event: payload}, opts...)
+	if err != nil {
+		return fmt.Errorf("error publishing event: %w", err)
+	}
+
+	return nil
+}
+
+// handlers returns handlers of the event type subscribed in given mode.
+func (b *Bus) handlers(eventType string, mode schema.Mode) []schema.Handler {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var handlers []schema.Handler
+
+	for _, sub := range b.subscribers[eventType] {
+		if sub.mode == mode {
+			handlers = append(handlers, sub.handler)
+		}
+	}
+
+	return handlers
+}
+
+// dispatchSync calls sync subscribers of the event type in order of subscription.
+func (b *Bus) dispatchSync(ctx context.Context, eventType string, payload []byte) error {
+	for _, handler := range b.handlers(eventType, schema.Sync) {
+		if err := call(ctx, handler, payload); err != nil {
+			return fmt.Errorf("error handling event %s: %w", eventType, err)
+		}
+	}
+
+	return nil
+}
+
+// dispatch calls subscribers of the event type with given payload.
+//
+// Async subscribers are started in background, their errors are logged only.
+func (b *Bus) dispatch(ctx context.Context, eventType string, payload []byte) error {
+	if err := b.dispatchSync(ctx, eventType, payload); err != nil {
+		return err
+	}
+
+	for _, handler := range b.handlers(eventType, schema.Async) {
+		b.async.Add(1)
+
+		go func(handler schema.Handler) {
+			defer b.async.Done()
+
+			// async subscribers outlive the publishing request
+			ctx, cancel := context.WithTimeout(context.Background(), handlerTimeout)
+			defer cancel()
+
+			if err := call(ctx, handler, payload); err != nil {
+				b.log.Printf("error handling event %s: %s", eventType, err)
+			}
+		}(handler)
+	}
+
+	return nil
+}
+
+// dispatchAndWait calls subscribers of the event type with given payload, waiting for async subscribers.
+//
+// Async subscribers are still called concurrently, but their failures are returned,
+// so relay job is retried until all subscribers succeed.
+func (b *Bus) dispatchAndWait(ctx context.Context, eventType string, payload []byte) error {
+	if err := b.dispatchSync(ctx, eventType, payload); err != nil {
+		return err
+	}
+
+	handlers := b.handlers(eventType, schema.Async)
+	errs := make([]error, len(handlers))
+
+	var wg sync.WaitGroup
+
+	for i, handler := range handlers {
+		wg.Add(1)
+
+		go func(i int, handler schema.Handler) {
+			defer wg.Done()
+
+			errs[i] = call(ctx, handler, payload)
+		}(i, handler)
+	}
+
+	wg.Wait()
+
+	var (
+		failed   int
+		firstErr error
+	)
+
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		if firstErr == nil {
+			firstErr = err
+		}
+
+		failed++
+	}
+
+	if failed > 0 {
+		return fmt.Errorf(
+			"error handling event %s: %d of %d async subscribers failed: %w", eventType, failed, len(handlers), firstErr,
+		)
+	}
+
+	return nil
+}
+
+// call calls the handler converting panic into error.
+func call(ctx context.Context, handler schema.Handler, payload []byte) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("event handler panicked: %v", rec) //nolint:goerr113
+		}
+	}()
+
+	return handler(ctx, payload)
+}
+
+// relay delivers event published in transaction to the subscribers.
+func (b *Bus) relay(ctx context.Context, payload relayPayload) error {
+	return b.dispatchAndWait(ctx, payload.Type, payload.Event)
+}
+
+// Stop waits for running async subscribers to finish.
+func (b *Bus) Stop(ctx context.Context) error {
+	finished := make(chan struct{})
+
+	go func() {
+		b.async.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("error waiting for async event subscribers: %w", ctx.Err())
+	}
+}
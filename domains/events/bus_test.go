@@ -0,0 +1,204 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
+	th "github.com/outcatcher/anwil/domains/core/testhelpers"
+	"github.com/outcatcher/anwil/domains/events/schema"
+	jobsSchema "github.com/outcatcher/anwil/domains/jobs/schema"
+	"github.com/stretchr/testify/require"
+)
+
+type testEvent struct {
+	Value string `json:"value"`
+}
+
+func (testEvent) EventType() string {
+	return "test.happened"
+}
+
+func newTestBus(queue jobsSchema.Queue) *Bus {
+	return New(configSchema.EventsConfiguration{}, queue, log.Default())
+}
+
+func TestPublish(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		bus := newTestBus(new(th.MockQueue))
+
+		var (
+			mu    sync.Mutex
+			calls []string
+		)
+
+		record := func(name string) func(context.Context, testEvent) error {
+			return func(_ context.Context, event testEvent) error {
+				mu.Lock()
+				defer mu.Unlock()
+
+				calls = append(calls, name+":"+event.Value)
+
+				return nil
+			}
+		}
+
+		asyncDone := make(chan struct{})
+
+		Subscribe(bus, schema.Async, func(ctx context.Context, event testEvent) error {
+			defer close(asyncDone)
+
+			return record("async")(ctx, event)
+		})
+		Subscribe(bus, schema.Sync, record("first"))
+		Subscribe(bus, schema.Sync, record("second"))
+
+		require.NoError(t, bus.Publish(ctx, testEvent{Value: "value"}))
+
+		select {
+		case <-asyncDone:
+		case <-time.After(time.Second):
+			require.FailNow(t, "async subscriber is not called")
+		}
+
+		require.Equal(t, []string{"first:value", "second:value", "async:value"}, calls)
+	})
+
+	t.Run("sync error", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected handler error") //nolint:goerr113
+
+		bus := newTestBus(new(th.MockQueue))
+
+		asyncCalled := false
+
+		Subscribe(bus, schema.Sync, func(context.Context, testEvent) error { return expectedErr })
+		Subscribe(bus, schema.Async, func(context.Context, testEvent) error {
+			asyncCalled = true
+
+			return nil
+		})
+
+		err := bus.Publish(ctx, testEvent{})
+		require.ErrorIs(t, err, expectedErr)
+
+		require.NoError(t, bus.Stop(ctx))
+		require.False(t, asyncCalled)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		t.Parallel()
+
+		bus := newTestBus(new(th.MockQueue))
+
+		Subscribe(bus, schema.Sync, func(context.Context, testEvent) error { panic("handler panic") })
+
+		require.Error(t, bus.Publish(ctx, testEvent{}))
+	})
+
+	t.Run("no subscribers", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, newTestBus(new(th.MockQueue)).Publish(ctx, testEvent{}))
+	})
+}
+
+func TestPublishTx(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	queue := new(th.MockQueue)
+	queue.
+		On("Enqueue", ctx, relayJobKind, relayPayload{
+			Type:  "test.happened",
+			Event: json.RawMessage(`{"value":"value"}`),
+		}).
+		Return(nil)
+
+	bus := newTestBus(queue)
+
+	called := false
+
+	Subscribe(bus, schema.Sync, func(context.Context, testEvent) error {
+		called = true
+
+		return nil
+	})
+
+	require.NoError(t, bus.PublishTx(ctx, nil, testEvent{Value: "value"}))
+
+	queue.AssertNumberOfCalls(t, "Enqueue", 1)
+	require.False(t, called, "event is delivered before commit")
+}
+
+func TestRelay(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	payload := relayPayload{Type: "test.happened", Event: json.RawMessage(`{"value":"value"}`)}
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		bus := newTestBus(new(th.MockQueue))
+
+		var received testEvent
+
+		Subscribe(bus, schema.Sync, func(_ context.Context, event testEvent) error {
+			received = event
+
+			return nil
+		})
+
+		require.NoError(t, bus.relay(ctx, payload))
+		require.Equal(t, "value", received.Value)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected handler error") //nolint:goerr113
+
+		bus := newTestBus(new(th.MockQueue))
+
+		Subscribe(bus, schema.Sync, func(context.Context, testEvent) error { return expectedErr })
+
+		require.ErrorIs(t, bus.relay(ctx, payload), expectedErr)
+	})
+
+	t.Run("failed async", func(t *testing.T) {
+		t.Parallel()
+
+		bus := newTestBus(new(th.MockQueue))
+
+		var succeeded bool
+
+		Subscribe(bus, schema.Async, func(context.Context, testEvent) error {
+			succeeded = true
+
+			return nil
+		})
+		Subscribe(bus, schema.Async, func(context.Context, testEvent) error {
+			return errors.New("handler error") //nolint:goerr113
+		})
+
+		err := bus.relay(ctx, payload)
+		require.ErrorContains(t, err, "1 of 2 async subscribers failed")
+
+		// async subscribers are finished before the job state is stored
+		require.True(t, succeeded)
+	})
+}
@@ -0,0 +1,12 @@
+/*
+Package events contains in-process domain event bus.
+
+Services publish typed events and subscribe to events of other services instead of calling them directly.
+Sync subscribers are called by the publisher, async subscribers are called in background.
+
+Events published within a DB transaction are enqueued as background jobs in the same transaction, so
+they are relayed to the subscribers only after the transaction is committed and subscribers never see events
+of rolled back changes. Such events are delivered at least once to both sync and async subscribers:
+relay job waits for all subscribers and is retried by the job runner if any of them fails.
+*/
+package events
@@ -0,0 +1,31 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/outcatcher/anwil/domains/events/schema"
+)
+
+// Typed wraps handler of JSON-encoded event of type T.
+func Typed[T any](handler func(ctx context.Context, event T) error) schema.Handler {
+	return func(ctx context.Context, payload []byte) error {
+		var decoded T
+
+		if err := json.Unmarshal(payload, &decoded); err != nil {
+			return fmt.Errorf("error decoding event payload: %w", err)
+		}
+
+		return handler(ctx, decoded)
+	}
+}
+
+// Subscribe registers handler of the events of type T.
+//
+// Event type is taken from T zero value, so T should not be a pointer.
+func Subscribe[T schema.Event](bus schema.Bus, mode schema.Mode, handler func(ctx context.Context, event T) error) {
+	var zero T
+
+	bus.Subscribe(zero.EventType(), mode, Typed(handler))
+}
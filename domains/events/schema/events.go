@@ -0,0 +1,74 @@
+/*
+Package schema contains domain event bus DTOs and interfaces
+*/
+package schema
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/outcatcher/anwil/domains/core/services"
+	storageSchema "github.com/outcatcher/anwil/domains/storage/schema"
+)
+
+// Event - domain event published by a service. Events are encoded as JSON.
+type Event interface {
+	// EventType returns unique type of the event, e.g. "users.deleted".
+	// Type should not depend on event values, as it's called on zero value when subscribing.
+	EventType() string
+}
+
+// Handler - function processing single event with raw JSON payload.
+type Handler func(ctx context.Context, payload []byte) error
+
+// Mode - way subscriber is called.
+type Mode int
+
+const (
+	// Sync subscribers are called by the publisher in order of subscription.
+	// Publishing fails if any of them fails.
+	Sync Mode = iota
+	// Async subscribers are called in background after all sync subscribers succeeded.
+	// Errors of events published directly are logged only, while events published in transactions are retried
+	// until all subscribers succeed.
+	Async
+)
+
+// Publisher publishes domain events.
+type Publisher interface {
+	// Publish delivers event to the subscribers immediately.
+	Publish(ctx context.Context, event Event) error
+	// PublishTx enqueues delivery of the event using given transaction.
+	// Event is delivered to the subscribers only after the transaction is committed.
+	PublishTx(ctx context.Context, tx storageSchema.QueryExecutor, event Event) error
+}
+
+// Bus - in-process domain event bus.
+type Bus interface {
+	Publisher
+
+	// Subscribe registers handler for the events of given type.
+	Subscribe(eventType string, mode Mode, handler Handler)
+}
+
+// WithEventBus defines service or state having event bus attached.
+type WithEventBus interface {
+	EventBus() Bus
+}
+
+// RequiresEventBus defines service which can use event bus attached.
+type RequiresEventBus interface {
+	UseEventBus(bus Bus)
+}
+
+// EventBusInject adds event bus to the service.
+func EventBusInject(consumer, provider any) error {
+	reqBus, provBus, err := services.ValidateArgInterfaces[RequiresEventBus, WithEventBus](consumer, provider)
+	if err != nil {
+		return fmt.Errorf("error injecting event bus: %w", err)
+	}
+
+	reqBus.UseEventBus(provBus.EventBus())
+
+	return nil
+}
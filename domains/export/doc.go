@@ -0,0 +1,4 @@
+/*
+Package export contains functions and entities of personal data export domain.
+*/
+package export
@@ -0,0 +1,108 @@
+/*
+Package handlers contains API handlers for personal data export endpoints.
+*/
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/outcatcher/anwil/domains/core/negotiation"
+	"github.com/outcatcher/anwil/domains/core/services"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/outcatcher/anwil/domains/core/validation"
+	"github.com/outcatcher/anwil/domains/export/service/schema"
+	"github.com/outcatcher/anwil/domains/users/auth"
+)
+
+// AddExportHandlers - adds export-related endpoints.
+func AddExportHandlers(state svcSchema.ProvidingServices) svcSchema.AddHandlersFunc {
+	return func(baseGroup, secGroup *echo.Group) error {
+		exportService, err := services.GetServiceFromProvider[schema.ExportService](state, schema.ServiceID)
+		if err != nil {
+			return fmt.Errorf("error adding export handlers: %w", err)
+		}
+
+		// export is requested without body
+		secGroup.POST("/me/exports", handleRequestExport(exportService), negotiation.Consumes())
+		secGroup.GET("/me/exports/:uuid", handleGetExport(exportService))
+
+		// download is authorized by link signature, so links can be used directly in browser
+		baseGroup.GET("/exports/:uuid/download", handleDownloadExport(exportService))
+
+		return nil
+	}
+}
+
+// exportUUIDParam returns validated export UUID path parameter.
+func exportUUIDParam(c echo.Context) (string, error) {
+	value := c.Param("uuid")
+
+	if _, err := uuid.Parse(value); err != nil {
+		return "", fmt.Errorf("%w: invalid export UUID %q", validation.ErrValidationFailed, value)
+	}
+
+	return value, nil
+}
+
+func handleRequestExport(exp schema.ExportService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error requesting export: %w", err)
+		}
+
+		export, err := exp.RequestExport(c.Request().Context(), claims.UserUUID)
+		if err != nil {
+			return fmt.Errorf("error requesting export: %w", err)
+		}
+
+		return negotiation.Respond(c, http.StatusAccepted, export)
+	}
+}
+
+func handleGetExport(exp schema.ExportService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error getting export: %w", err)
+		}
+
+		exportUUID, err := exportUUIDParam(c)
+		if err != nil {
+			return fmt.Errorf("error getting export: %w", err)
+		}
+
+		export, err := exp.GetExport(c.Request().Context(), claims.UserUUID, exportUUID)
+		if err != nil {
+			return fmt.Errorf("error getting export: %w", err)
+		}
+
+		return negotiation.Respond(c, http.StatusOK, export)
+	}
+}
+
+func handleDownloadExport(exp schema.ExportService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		exportUUID, err := exportUUIDParam(c)
+		if err != nil {
+			return fmt.Errorf("error downloading export: %w", err)
+		}
+
+		expires, err := strconv.ParseInt(c.QueryParam("expires"), 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: invalid query parameter 'expires'", validation.ErrValidationFailed)
+		}
+
+		archivePath, err := exp.ArchivePath(c.Request().Context(), exportUUID, expires, c.QueryParam("signature"))
+		if err != nil {
+			return fmt.Errorf("error downloading export: %w", err)
+		}
+
+		return c.Attachment(filepath.Clean(archivePath), "anwil-export-"+exportUUID+".zip") //nolint:wrapcheck
+	}
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+)
+
+// writeArchive writes zip archive with user data to w.
+//
+// Data of each service is written to `<service ID>.json`, files are written to `<service ID>/` directory.
+func writeArchive(ctx context.Context, w io.Writer, data map[svcSchema.ServiceID]*svcSchema.UserDataExport) error {
+	ids := make([]string, 0, len(data))
+
+	for id := range data {
+		ids = append(ids, string(id))
+	}
+
+	sort.Strings(ids)
+
+	archive := zip.NewWriter(w)
+
+	for _, id := range ids {
+		export := data[svcSchema.ServiceID(id)]
+
+		if err := writeJSON(archive, id+".json", export.Data); err != nil {
+			return err
+		}
+
+		for _, file := range export.Files {
+			if err := writeFile(ctx, archive, id, file); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("error finishing archive: %w", err)
+	}
+
+	return nil
+}
+
+func writeJSON(archive *zip.Writer, name string, data any) error {
+	entry, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("error creating archive entry %s: %w", name, err)
+	}
+
+	encoder := json.NewEncoder(entry)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(data); err != nil {
+		return fmt.Errorf("error encoding %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func writeFile(ctx context.Context, archive *zip.Writer, dir string, file svcSchema.ExportedFile) error {
+	// cleaning rooted path removes any `..` elements, so files stay in service directory
+	name := path.Join(dir, strings.TrimPrefix(path.Clean("/"+file.Name), "/"))
+
+	entry, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("error creating archive entry %s: %w", name, err)
+	}
+
+	content, err := file.Open(ctx)
+	if err != nil {
+		return fmt.Errorf("error opening exported file %s: %w", name, err)
+	}
+
+	_, err = io.Copy(entry, content)
+
+	closeErr := content.Close()
+
+	if err != nil {
+		return fmt.Errorf("error writing exported file %s: %w", name, err)
+	}
+
+	if closeErr != nil {
+		return fmt.Errorf("error closing exported file %s: %w", name, closeErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func stringFile(name, content string) svcSchema.ExportedFile {
+	return svcSchema.ExportedFile{
+		Name: name,
+		Open: func(context.Context) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader(content)), nil
+		},
+	}
+}
+
+func readEntry(t *testing.T, file *zip.File) string {
+	t.Helper()
+
+	reader, err := file.Open()
+	require.NoError(t, err)
+
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	return string(content)
+}
+
+func TestWriteArchive(t *testing.T) {
+	t.Parallel()
+
+	data := map[svcSchema.ServiceID]*svcSchema.UserDataExport{
+		"users": {
+			Data: map[string]string{"username": "john"},
+			Files: []svcSchema.ExportedFile{
+				stringFile("avatar.png", "avatar"),
+				stringFile("../../escape.txt", "escape"),
+			},
+		},
+		"empty": {Data: []string{}},
+	}
+
+	buf := new(bytes.Buffer)
+
+	require.NoError(t, writeArchive(context.Background(), buf, data))
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	entries := make(map[string]string, len(archive.File))
+
+	for _, file := range archive.File {
+		entries[file.Name] = readEntry(t, file)
+	}
+
+	require.Len(t, entries, 4)
+	require.Equal(t, "avatar", entries["users/avatar.png"])
+	require.Equal(t, "escape", entries["users/escape.txt"])
+	require.JSONEq(t, "[]", entries["empty.json"])
+
+	user := make(map[string]string)
+
+	require.NoError(t, json.Unmarshal([]byte(entries["users.json"]), &user))
+	require.Equal(t, "john", user["username"])
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/outcatcher/anwil/domains/export/service/schema"
+	"github.com/outcatcher/anwil/domains/export/storage"
+)
+
+// archivePath returns path of the archive for given export.
+func (s *service) archivePath(exportUUID string) string {
+	return filepath.Join(s.dir, exportUUID+".zip")
+}
+
+// removeArchiveFiles removes export archive together with temporary files left by interrupted builds.
+func (s *service) removeArchiveFiles(exportUUID string) error {
+	partial, err := filepath.Glob(filepath.Join(s.dir, exportUUID+"-*.tmp"))
+	if err != nil {
+		return fmt.Errorf("error listing temporary archives: %w", err)
+	}
+
+	for _, path := range append(partial, s.archivePath(exportUUID)) {
+		err := os.Remove(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("error removing export archive: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// toExport converts stored export to DTO, adding download link to ready exports.
+func (s *service) toExport(export *storage.UserExport, now time.Time) *schema.Export {
+	result := &schema.Export{
+		UUID:      export.UUID,
+		Status:    export.Status,
+		CreatedAt: export.CreatedAt,
+	}
+
+	if export.FinishedAt.Valid {
+		result.FinishedAt = &export.FinishedAt.Time
+	}
+
+	if export.Status != storage.StatusReady || !export.ExpiresAt.Valid {
+		return result
+	}
+
+	result.ExpiresAt = &export.ExpiresAt.Time
+
+	if !now.Before(export.ExpiresAt.Time) {
+		result.Status = schema.StatusExpired
+
+		return result
+	}
+
+	result.DownloadURL = downloadURL(s.cfg.API.BaseURL(), export.UUID, export.ExpiresAt.Time, s.linkKey)
+
+	return result
+}
+
+// RequestExport starts building new export of the user data.
+//
+// Archive is built by background job. Existing pending export is returned instead of starting a new one.
+func (s *service) RequestExport(ctx context.Context, userUUID string) (*schema.Export, error) {
+	pending, err := s.storage.GetPendingExport(ctx, userUUID)
+
+	switch {
+	case err == nil:
+		return s.toExport(pending, time.Now()), nil
+	case !errors.Is(err, errbase.ErrNotFound):
+		return nil, fmt.Errorf("error requesting export: %w", err)
+	}
+
+	export, err := s.storage.InsertExport(ctx, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting export: %w", err)
+	}
+
+	err = s.queue.Enqueue(ctx, buildJobKind, buildPayload{ExportUUID: export.UUID, UserUUID: export.WisherUUID})
+	if err != nil {
+		if failErr := s.storage.FailExport(ctx, export.UUID, "failed to start building archive"); failErr != nil {
+			s.log.Printf("error marking export %s failed: %s", export.UUID, failErr)
+		}
+
+		return nil, fmt.Errorf("error requesting export: %w", err)
+	}
+
+	return s.toExport(export, time.Now()), nil
+}
+
+// GetExport returns export of the user. Exports of other users are reported as not found.
+func (s *service) GetExport(ctx context.Context, userUUID, exportUUID string) (*schema.Export, error) {
+	export, err := s.storage.GetExport(ctx, exportUUID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting export: %w", err)
+	}
+
+	if export.WisherUUID != userUUID {
+		return nil, fmt.Errorf("export %s: %w", exportUUID, errbase.ErrNotFound)
+	}
+
+	return s.toExport(export, time.Now()), nil
+}
+
+// ArchivePath validates download link parameters and returns path of the export archive.
+func (s *service) ArchivePath(ctx context.Context, exportUUID string, expires int64, signature string) (string, error) {
+	if err := verifyDownload(exportUUID, expires, signature, s.linkKey, time.Now()); err != nil {
+		return "", fmt.Errorf("error downloading export: %w", err)
+	}
+
+	export, err := s.storage.GetExport(ctx, exportUUID)
+	if err != nil {
+		return "", fmt.Errorf("error downloading export: %w", err)
+	}
+
+	if export.Status != storage.StatusReady {
+		return "", fmt.Errorf("export %s is %s: %w", exportUUID, export.Status, errbase.ErrNotFound)
+	}
+
+	return s.archivePath(exportUUID), nil
+}
+
+// writeArchiveFile collects user data and writes it to the archive file.
+//
+// Archive is written to temporary file first, so partially written archives are never served.
+func (s *service) writeArchiveFile(ctx context.Context, exportUUID, userUUID string) error {
+	data, err := s.collector.CollectUserData(ctx, userUUID)
+	if err != nil {
+		return fmt.Errorf("error collecting user data: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp(s.dir, exportUUID+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("error creating archive file: %w", err)
+	}
+
+	defer func() {
+		// file is already renamed on success
+		if err := os.Remove(tmpFile.Name()); err != nil && !errors.Is(err, os.ErrNotExist) {
+			s.log.Printf("error removing temporary archive: %s", err)
+		}
+	}()
+
+	err = writeArchive(ctx, tmpFile, data)
+
+	closeErr := tmpFile.Close()
+
+	if err != nil {
+		return err
+	}
+
+	if closeErr != nil {
+		return fmt.Errorf("error closing archive file: %w", closeErr)
+	}
+
+	if err := os.Rename(tmpFile.Name(), s.archivePath(exportUUID)); err != nil {
+		return fmt.Errorf("error saving archive file: %w", err)
+	}
+
+	return nil
+}
+
+// PrepareErasure returns function removing export archives of the user. Export records are deleted with the user.
+func (s *service) PrepareErasure(ctx context.Context, userUUID string) (svcSchema.EraseFunc, error) {
+	exports, err := s.storage.ListExports(ctx, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("error preparing exports erasure: %w", err)
+	}
+
+	return func(context.Context) error {
+		for _, export := range exports {
+			if err := s.removeArchiveFiles(export.UUID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, nil
+}
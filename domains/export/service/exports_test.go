@@ -0,0 +1,366 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	th "github.com/outcatcher/anwil/domains/core/testhelpers"
+	"github.com/outcatcher/anwil/domains/export/service/schema"
+	"github.com/outcatcher/anwil/domains/export/storage"
+	jobsSchema "github.com/outcatcher/anwil/domains/jobs/schema"
+	notificationsSchema "github.com/outcatcher/anwil/domains/notifications/schema"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockStorage struct {
+	mock.Mock
+}
+
+func (m *mockStorage) InsertExport(ctx context.Context, wisherUUID string) (*storage.UserExport, error) {
+	args := m.Called(ctx, wisherUUID)
+
+	return args.Get(0).(*storage.UserExport), args.Error(1) //nolint:forcetypeassert
+}
+
+func (m *mockStorage) GetExport(ctx context.Context, uuid string) (*storage.UserExport, error) {
+	args := m.Called(ctx, uuid)
+
+	return args.Get(0).(*storage.UserExport), args.Error(1) //nolint:forcetypeassert
+}
+
+func (m *mockStorage) GetPendingExport(ctx context.Context, wisherUUID string) (*storage.UserExport, error) {
+	args := m.Called(ctx, wisherUUID)
+
+	return args.Get(0).(*storage.UserExport), args.Error(1) //nolint:forcetypeassert
+}
+
+func (m *mockStorage) ListExports(ctx context.Context, wisherUUID string) ([]storage.UserExport, error) {
+	args := m.Called(ctx, wisherUUID)
+
+	return args.Get(0).([]storage.UserExport), args.Error(1) //nolint:forcetypeassert
+}
+
+func (m *mockStorage) FinishExport(ctx context.Context, uuid string, expiresAt time.Time) error {
+	return m.Called(ctx, uuid, expiresAt).Error(0)
+}
+
+func (m *mockStorage) FailExport(ctx context.Context, uuid, reason string) error {
+	return m.Called(ctx, uuid, reason).Error(0)
+}
+
+func (m *mockStorage) ListExpiredExports(
+	ctx context.Context, before, failedBefore time.Time,
+) ([]storage.UserExport, error) {
+	args := m.Called(ctx, before, failedBefore)
+
+	return args.Get(0).([]storage.UserExport), args.Error(1) //nolint:forcetypeassert
+}
+
+func (m *mockStorage) DeleteExport(ctx context.Context, uuid string) error {
+	return m.Called(ctx, uuid).Error(0)
+}
+
+type mockCollector struct {
+	mock.Mock
+}
+
+func (m *mockCollector) CollectUserData(
+	ctx context.Context, userUUID string,
+) (map[svcSchema.ServiceID]*svcSchema.UserDataExport, error) {
+	args := m.Called(ctx, userUUID)
+
+	return args.Get(0).(map[svcSchema.ServiceID]*svcSchema.UserDataExport), args.Error(1) //nolint:forcetypeassert
+}
+
+func newTestService(t *testing.T, exportStorage storage.ExportStorage) *service {
+	t.Helper()
+
+	return &service{
+		cfg: &configSchema.Configuration{
+			API: configSchema.APIConfiguration{PublicURL: "https://anwil.example.com"},
+		},
+		storage: exportStorage,
+		log:     log.Default(),
+		dir:     t.TempDir(),
+		ttl:     time.Hour,
+		linkKey: []byte(th.RandomString("key-", 20)),
+	}
+}
+
+func readyExport(userUUID string, expiresAt time.Time) *storage.UserExport {
+	return &storage.UserExport{
+		UUID:       th.RandomString("uuid-", 10),
+		WisherUUID: userUUID,
+		Status:     storage.StatusReady,
+		CreatedAt:  expiresAt.Add(-2 * time.Hour),
+		FinishedAt: sql.NullTime{Time: expiresAt.Add(-time.Hour), Valid: true},
+		ExpiresAt:  sql.NullTime{Time: expiresAt, Valid: true},
+	}
+}
+
+func TestGetExport(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	userUUID := th.RandomString("user-", 10)
+
+	t.Run("ready", func(t *testing.T) {
+		t.Parallel()
+
+		export := readyExport(userUUID, time.Now().Add(time.Hour))
+
+		store := new(mockStorage)
+		store.On("GetExport", ctx, export.UUID).Return(export, nil)
+
+		svc := newTestService(t, store)
+
+		result, err := svc.GetExport(ctx, userUUID, export.UUID)
+		require.NoError(t, err)
+		require.Equal(t, storage.StatusReady, result.Status)
+		require.Contains(t, result.DownloadURL, "https://anwil.example.com/api/v1/exports/"+export.UUID)
+
+		archivePath, err := svc.ArchivePath(ctx, export.UUID, export.ExpiresAt.Time.Unix(), signDownload(
+			export.UUID, export.ExpiresAt.Time.Unix(), svc.linkKey,
+		))
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(svc.dir, export.UUID+".zip"), archivePath)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		t.Parallel()
+
+		export := readyExport(userUUID, time.Now().Add(-time.Minute))
+
+		store := new(mockStorage)
+		store.On("GetExport", ctx, export.UUID).Return(export, nil)
+
+		result, err := newTestService(t, store).GetExport(ctx, userUUID, export.UUID)
+		require.NoError(t, err)
+		require.Equal(t, schema.StatusExpired, result.Status)
+		require.Empty(t, result.DownloadURL)
+	})
+
+	t.Run("other user", func(t *testing.T) {
+		t.Parallel()
+
+		export := readyExport(th.RandomString("user-", 10), time.Now().Add(time.Hour))
+
+		store := new(mockStorage)
+		store.On("GetExport", ctx, export.UUID).Return(export, nil)
+
+		_, err := newTestService(t, store).GetExport(ctx, userUUID, export.UUID)
+		require.ErrorIs(t, err, errbase.ErrNotFound)
+	})
+}
+
+func TestRequestExport(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	userUUID := th.RandomString("user-", 10)
+
+	t.Run("new", func(t *testing.T) {
+		t.Parallel()
+
+		export := &storage.UserExport{
+			UUID:       th.RandomString("uuid-", 10),
+			WisherUUID: userUUID,
+			Status:     storage.StatusPending,
+			CreatedAt:  time.Now(),
+		}
+
+		store := new(mockStorage)
+		store.On("GetPendingExport", ctx, userUUID).Return((*storage.UserExport)(nil), errbase.ErrNotFound)
+		store.On("InsertExport", ctx, userUUID).Return(export, nil)
+
+		queue := new(th.MockQueue)
+		queue.
+			On("Enqueue", ctx, buildJobKind, buildPayload{ExportUUID: export.UUID, UserUUID: userUUID}).
+			Return(nil)
+
+		svc := newTestService(t, store)
+		svc.queue = queue
+
+		result, err := svc.RequestExport(ctx, userUUID)
+		require.NoError(t, err)
+		require.Equal(t, export.UUID, result.UUID)
+		require.Equal(t, storage.StatusPending, result.Status)
+
+		queue.AssertNumberOfCalls(t, "Enqueue", 1)
+	})
+
+	t.Run("pending exists", func(t *testing.T) {
+		t.Parallel()
+
+		export := &storage.UserExport{
+			UUID:       th.RandomString("uuid-", 10),
+			WisherUUID: userUUID,
+			Status:     storage.StatusPending,
+		}
+
+		store := new(mockStorage)
+		store.On("GetPendingExport", ctx, userUUID).Return(export, nil)
+
+		queue := new(th.MockQueue)
+
+		svc := newTestService(t, store)
+		svc.queue = queue
+
+		result, err := svc.RequestExport(ctx, userUUID)
+		require.NoError(t, err)
+		require.Equal(t, export.UUID, result.UUID)
+
+		store.AssertNotCalled(t, "InsertExport")
+		queue.AssertNotCalled(t, "Enqueue")
+	})
+}
+
+func TestBuildExport(t *testing.T) {
+	t.Parallel()
+
+	payload := buildPayload{
+		ExportUUID: th.RandomString("uuid-", 10),
+		UserUUID:   th.RandomString("user-", 10),
+	}
+	expectedErr := errors.New("expected") //nolint:goerr113
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := jobsSchema.WithAttempt(context.Background(), 1, 3)
+
+		store := new(mockStorage)
+		store.On("FinishExport", ctx, payload.ExportUUID, mock.AnythingOfType("time.Time")).Return(nil)
+
+		collector := new(mockCollector)
+		collector.
+			On("CollectUserData", ctx, payload.UserUUID).
+			Return(map[svcSchema.ServiceID]*svcSchema.UserDataExport{"users": {Data: "data"}}, nil)
+
+		notifier := new(th.MockNotifier)
+		notifier.
+			On("Notify", ctx, mock.MatchedBy(func(n notificationsSchema.Notification) bool {
+				return n.Type == notificationsSchema.TypeExportReady && n.UserUUID == payload.UserUUID
+			})).
+			Return(nil)
+
+		svc := newTestService(t, store)
+		svc.collector = collector
+		svc.notifier = notifier
+
+		require.NoError(t, svc.buildExport(ctx, payload))
+
+		store.AssertNumberOfCalls(t, "FinishExport", 1)
+		notifier.AssertNumberOfCalls(t, "Notify", 1)
+
+		archive, err := zip.OpenReader(svc.archivePath(payload.ExportUUID))
+		require.NoError(t, err)
+		require.NoError(t, archive.Close())
+
+		// temporary files are removed
+		entries, err := os.ReadDir(svc.dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+	})
+
+	t.Run("failed attempt", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := jobsSchema.WithAttempt(context.Background(), 1, 3)
+
+		store := new(mockStorage)
+
+		collector := new(mockCollector)
+		collector.
+			On("CollectUserData", ctx, payload.UserUUID).
+			Return(map[svcSchema.ServiceID]*svcSchema.UserDataExport(nil), expectedErr)
+
+		svc := newTestService(t, store)
+		svc.collector = collector
+
+		err := svc.buildExport(ctx, payload)
+		require.ErrorIs(t, err, expectedErr)
+		require.NotErrorIs(t, err, jobsSchema.ErrPermanent)
+
+		store.AssertNotCalled(t, "FailExport")
+	})
+
+	t.Run("failed last attempt", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := jobsSchema.WithAttempt(context.Background(), 3, 3)
+
+		store := new(mockStorage)
+		store.On("FailExport", ctx, payload.ExportUUID, mock.AnythingOfType("string")).Return(nil)
+
+		collector := new(mockCollector)
+		collector.
+			On("CollectUserData", ctx, payload.UserUUID).
+			Return(map[svcSchema.ServiceID]*svcSchema.UserDataExport(nil), expectedErr)
+
+		svc := newTestService(t, store)
+		svc.collector = collector
+
+		err := svc.buildExport(ctx, payload)
+		require.ErrorIs(t, err, jobsSchema.ErrPermanent)
+
+		store.AssertNumberOfCalls(t, "FailExport", 1)
+		store.AssertNotCalled(t, "FinishExport")
+
+		_, err = os.Stat(svc.archivePath(payload.ExportUUID))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestCleanupExports(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	expired := readyExport(th.RandomString("user-", 10), time.Now().Add(-time.Minute))
+	failed := &storage.UserExport{
+		UUID:       th.RandomString("export-", 10),
+		WisherUUID: expired.WisherUUID,
+		Status:     storage.StatusFailed,
+	}
+
+	store := new(mockStorage)
+	store.
+		On("ListExpiredExports", ctx, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) {
+			before := args.Get(1).(time.Time)       //nolint:forcetypeassert
+			failedBefore := args.Get(2).(time.Time) //nolint:forcetypeassert
+
+			require.WithinDuration(t, before.Add(-time.Hour), failedBefore, time.Second)
+		}).
+		Return([]storage.UserExport{*expired, *failed}, nil)
+	store.On("DeleteExport", ctx, mock.AnythingOfType("string")).Return(nil)
+
+	svc := newTestService(t, store)
+
+	partial := filepath.Join(svc.dir, failed.UUID+"-123.tmp")
+
+	require.NoError(t, os.WriteFile(svc.archivePath(expired.UUID), []byte("archive"), 0o600))
+	require.NoError(t, os.WriteFile(partial, []byte("arch"), 0o600))
+
+	require.NoError(t, svc.cleanupExports(ctx, nil))
+
+	for _, path := range []string{svc.archivePath(expired.UUID), partial} {
+		_, err := os.Stat(path)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	}
+
+	store.AssertCalled(t, "DeleteExport", ctx, expired.UUID)
+	store.AssertCalled(t, "DeleteExport", ctx, failed.UUID)
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/outcatcher/anwil/domains/core/services"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/outcatcher/anwil/domains/export/service/schema"
+	"github.com/outcatcher/anwil/domains/jobs"
+	jobsSchema "github.com/outcatcher/anwil/domains/jobs/schema"
+	notificationsSchema "github.com/outcatcher/anwil/domains/notifications/schema"
+)
+
+const (
+	buildJobKind    = "export.build"
+	cleanupJobKind  = "export.cleanup"
+	cleanupSchedule = "@hourly"
+)
+
+// buildPayload - payload of the job building export archive.
+type buildPayload struct {
+	ExportUUID string `json:"export_uuid"`
+	UserUUID   string `json:"user_uuid"`
+}
+
+// addExportJobs registers export service jobs.
+func addExportJobs(state svcSchema.ProvidingServices) svcSchema.AddJobsFunc {
+	return func(registry svcSchema.JobRegistry) error {
+		svc, err := services.GetServiceFromProvider[*service](state, schema.ServiceID)
+		if err != nil {
+			return fmt.Errorf("error adding export jobs: %w", err)
+		}
+
+		if err := registry.Handle(buildJobKind, jobs.Typed(svc.buildExport)); err != nil {
+			return fmt.Errorf("error adding export jobs: %w", err)
+		}
+
+		if err := registry.Handle(cleanupJobKind, svc.cleanupExports); err != nil {
+			return fmt.Errorf("error adding export jobs: %w", err)
+		}
+
+		if err := registry.Schedule(cleanupJobKind, cleanupSchedule); err != nil {
+			return fmt.Errorf("error adding export jobs: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// buildExport builds export archive, updating export status.
+//
+// Export is marked as failed only when the last attempt fails.
+func (s *service) buildExport(ctx context.Context, payload buildPayload) error {
+	if err := s.writeArchiveFile(ctx, payload.ExportUUID, payload.UserUUID); err != nil {
+		if !jobsSchema.IsLastAttempt(ctx) {
+			return fmt.Errorf("error building export %s: %w", payload.ExportUUID, err)
+		}
+
+		// details are only logged, as they can contain internal information
+		if failErr := s.storage.FailExport(ctx, payload.ExportUUID, "failed to build archive"); failErr != nil {
+			s.log.Printf("error marking export %s failed: %s", payload.ExportUUID, failErr)
+		}
+
+		return fmt.Errorf("%w: error building export %s: %w", jobsSchema.ErrPermanent, payload.ExportUUID, err)
+	}
+
+	if err := s.storage.FinishExport(ctx, payload.ExportUUID, time.Now().Add(s.ttl)); err != nil {
+		return fmt.Errorf("error marking export %s ready: %w", payload.ExportUUID, err)
+	}
+
+	err := s.notifier.Notify(ctx, notificationsSchema.Notification{
+		UserUUID: payload.UserUUID,
+		Type:     notificationsSchema.TypeExportReady,
+		Title:    "Your data export is ready",
+		Body:     "Archive with all your Anwil data is ready for download.",
+		Link:     s.cfg.API.BaseURL() + "/api/v1/me/exports/" + payload.ExportUUID,
+		Data:     map[string]any{"export_uuid": payload.ExportUUID},
+	})
+	if err != nil {
+		// export is ready anyway, the status can be checked by the user
+		s.log.Printf("error notifying user about export %s: %s", payload.ExportUUID, err)
+	}
+
+	return nil
+}
+
+// cleanupExports removes expired and failed exports with their archives.
+//
+// Failed exports are kept for the same TTL as ready ones, so the user can see the failure.
+func (s *service) cleanupExports(ctx context.Context, _ []byte) error {
+	now := time.Now()
+
+	expired, err := s.storage.ListExpiredExports(ctx, now, now.Add(-s.ttl))
+	if err != nil {
+		return fmt.Errorf("error cleaning up exports: %w", err)
+	}
+
+	for _, export := range expired {
+		if err := s.removeArchiveFiles(export.UUID); err != nil {
+			return fmt.Errorf("error cleaning up exports: %w", err)
+		}
+
+		if err := s.storage.DeleteExport(ctx, export.UUID); err != nil {
+			return fmt.Errorf("error cleaning up exports: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/outcatcher/anwil/domains/core/errbase"
+)
+
+// linkKeyContext separates download link key from other keys derived from the same secret.
+const linkKeyContext = "anwil export download link"
+
+// deriveLinkKey returns key for signing download links derived from given secret.
+func deriveLinkKey(secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+
+	_, _ = mac.Write([]byte(linkKeyContext)) // hash writes never fail
+
+	return mac.Sum(nil)
+}
+
+// signDownload returns signature of the download link for given export and expiration time.
+func signDownload(exportUUID string, expires int64, key []byte) string {
+	mac := hmac.New(sha256.New, key)
+
+	_, _ = fmt.Fprintf(mac, "%s:%d", exportUUID, expires) // hash writes never fail
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyDownload checks download link signature and expiration.
+func verifyDownload(exportUUID string, expires int64, signature string, key []byte, now time.Time) error {
+	decoded, err := hex.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("%w: invalid signature", errbase.ErrForbidden)
+	}
+
+	expected, _ := hex.DecodeString(signDownload(exportUUID, expires, key))
+
+	if !hmac.Equal(decoded, expected) {
+		return fmt.Errorf("%w: invalid signature", errbase.ErrForbidden)
+	}
+
+	if now.Unix() >= expires {
+		return fmt.Errorf("%w: link expired", errbase.ErrForbidden)
+	}
+
+	return nil
+}
+
+// downloadURL returns signed download link to the export archive.
+func downloadURL(baseURL, exportUUID string, expiresAt time.Time, key []byte) string {
+	expires := expiresAt.Unix()
+
+	query := url.Values{
+		"expires":   []string{strconv.FormatInt(expires, 10)},
+		"signature": []string{signDownload(exportUUID, expires, key)},
+	}
+
+	return fmt.Sprintf("%s/api/v1/exports/%s/download?%s", baseURL, exportUUID, query.Encode())
+}
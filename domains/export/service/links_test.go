@@ -0,0 +1,89 @@
+package service
+
+import (
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	th "github.com/outcatcher/anwil/domains/core/testhelpers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyDownload(t *testing.T) {
+	t.Parallel()
+
+	key := []byte(th.RandomString("key-", 20))
+	exportUUID := th.RandomString("uuid-", 10)
+	now := time.Now()
+	expires := now.Add(time.Hour).Unix()
+	signature := signDownload(exportUUID, expires, key)
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, verifyDownload(exportUUID, expires, signature, key, now))
+	})
+
+	cases := []struct {
+		name       string
+		exportUUID string
+		expires    int64
+		signature  string
+		now        time.Time
+	}{
+		{"other export", th.RandomString("uuid-", 10), expires, signature, now},
+		{"changed expiration", exportUUID, expires + 1, signature, now},
+		{"not hex signature", exportUUID, expires, "not-a-signature", now},
+		{"expired", exportUUID, expires, signature, now.Add(2 * time.Hour)},
+	}
+
+	for _, data := range cases {
+		data := data
+
+		t.Run(data.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := verifyDownload(data.exportUUID, data.expires, data.signature, key, data.now)
+			require.ErrorIs(t, err, errbase.ErrForbidden)
+		})
+	}
+}
+
+func TestDownloadURL(t *testing.T) {
+	t.Parallel()
+
+	key := []byte(th.RandomString("key-", 20))
+	exportUUID := th.RandomString("uuid-", 10)
+	expiresAt := time.Now().Add(time.Hour)
+
+	link, err := url.Parse(downloadURL("https://anwil.example.com", exportUUID, expiresAt, key))
+	require.NoError(t, err)
+
+	require.Equal(t, "anwil.example.com", link.Host)
+	require.Equal(t, "/api/v1/exports/"+exportUUID+"/download", link.Path)
+
+	expires, err := strconv.ParseInt(link.Query().Get("expires"), 10, 64)
+	require.NoError(t, err)
+	require.Equal(t, expiresAt.Unix(), expires)
+
+	require.NoError(t, verifyDownload(exportUUID, expires, link.Query().Get("signature"), key, time.Now()))
+}
+
+func TestDeriveLinkKey(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte(th.RandomString("secret-", 20))
+	key := deriveLinkKey(secret)
+
+	require.Equal(t, key, deriveLinkKey(secret))
+	require.NotEqual(t, secret, key)
+
+	exportUUID := th.RandomString("uuid-", 10)
+	expires := time.Now().Add(time.Hour).Unix()
+
+	// links signed with the secret itself are not accepted
+	err := verifyDownload(exportUUID, expires, signDownload(exportUUID, expires, secret), key, time.Now())
+	require.ErrorIs(t, err, errbase.ErrForbidden)
+}
@@ -0,0 +1,39 @@
+/*
+Package schema contains service definition for Export service
+*/
+package schema
+
+import (
+	"context"
+	"time"
+
+	"github.com/outcatcher/anwil/domains/core/services/schema"
+)
+
+// ServiceID - ID for export service.
+const ServiceID schema.ServiceID = "export"
+
+// StatusExpired - status of ready export which archive is not available anymore.
+const StatusExpired = "expired"
+
+// ExportService - service handling personal data export.
+type ExportService interface {
+	// RequestExport starts building new export of the user data. Pending export is returned if exists.
+	RequestExport(ctx context.Context, userUUID string) (*Export, error)
+	// GetExport returns export of the user.
+	GetExport(ctx context.Context, userUUID, exportUUID string) (*Export, error)
+	// ArchivePath validates download link parameters and returns path of export archive.
+	ArchivePath(ctx context.Context, exportUUID string, expires int64, signature string) (string, error)
+}
+
+// Export holds user data export state.
+type Export struct {
+	UUID       string     `json:"uuid"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	// ExpiresAt is a time archive is available for download till
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// DownloadURL is a signed link to the archive, available till ExpiresAt
+	DownloadURL string `json:"download_url,omitempty"`
+}
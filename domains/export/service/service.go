@@ -0,0 +1,130 @@
+/*
+Package service contains export service methods
+*/
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
+	logSchema "github.com/outcatcher/anwil/domains/core/logging/schema"
+	"github.com/outcatcher/anwil/domains/core/services"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/outcatcher/anwil/domains/export/handlers"
+	"github.com/outcatcher/anwil/domains/export/service/schema"
+	exportStorage "github.com/outcatcher/anwil/domains/export/storage"
+	jobsSchema "github.com/outcatcher/anwil/domains/jobs/schema"
+	notificationsSchema "github.com/outcatcher/anwil/domains/notifications/schema"
+	storageSchema "github.com/outcatcher/anwil/domains/storage/schema"
+)
+
+const (
+	defaultTTL = 24 * time.Hour
+
+	dirPermissions = 0o750
+)
+
+// service - personal data export service.
+type service struct {
+	cfg     *configSchema.Configuration
+	storage exportStorage.ExportStorage
+
+	log       *log.Logger
+	collector svcSchema.UserDataCollector
+	queue     jobsSchema.Queue
+	notifier  notificationsSchema.Notifier
+
+	// directory to store archives in
+	dir string
+	// duration archives are available for download
+	ttl time.Duration
+
+	// key for signing download links
+	linkKey []byte
+}
+
+// UseConfig attaches configuration to the service.
+func (s *service) UseConfig(configuration *configSchema.Configuration) {
+	s.cfg = configuration
+}
+
+// UseStorage attaches given DB storage to the service.
+func (s *service) UseStorage(db storageSchema.QueryExecutor) {
+	s.storage = exportStorage.New(db)
+}
+
+// UseLogger attaches logger to the service.
+func (s *service) UseLogger(logger *log.Logger) {
+	s.log = logger
+}
+
+// UseUserDataCollector attaches collector of user data from all the services.
+func (s *service) UseUserDataCollector(collector svcSchema.UserDataCollector) {
+	s.collector = collector
+}
+
+// UseJobQueue attaches background job queue to the service.
+func (s *service) UseJobQueue(queue jobsSchema.Queue) {
+	s.queue = queue
+}
+
+// UseNotifier attaches notification publisher.
+func (s *service) UseNotifier(notifier notificationsSchema.Notifier) {
+	s.notifier = notifier
+}
+
+func exportServiceInit(_ context.Context, state any) (any, error) {
+	svc := new(service)
+
+	err := services.InjectServiceWith(
+		svc, state,
+		storageSchema.StorageInject,
+		logSchema.LoggerInject,
+		configSchema.ConfigInject,
+		services.UserDataCollectorInject,
+		jobsSchema.JobQueueInject,
+		notificationsSchema.NotifierInject,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing export service: %w", err)
+	}
+
+	key, err := svc.cfg.GetPrivateKey()
+	if err != nil {
+		return nil, fmt.Errorf("error initializing export service: %w", err)
+	}
+
+	svc.linkKey = deriveLinkKey(key)
+
+	svc.dir = svc.cfg.Export.Directory
+	if svc.dir == "" {
+		svc.dir = filepath.Join(os.TempDir(), "anwil-exports")
+	}
+
+	if err := os.MkdirAll(filepath.Clean(svc.dir), dirPermissions); err != nil {
+		return nil, fmt.Errorf("error creating export directory: %w", err)
+	}
+
+	svc.ttl = svc.cfg.Export.TTL
+	if svc.ttl == 0 {
+		svc.ttl = defaultTTL
+	}
+
+	return svc, nil
+}
+
+// NewExportService returns new export service definition.
+func NewExportService() svcSchema.ServiceDefinition {
+	return svcSchema.ServiceDefinition{
+		ID:               schema.ServiceID,
+		Init:             exportServiceInit,
+		DependsOn:        nil,
+		InitHandlersFunc: handlers.AddExportHandlers,
+		InitJobsFunc:     addExportJobs,
+	}
+}
@@ -0,0 +1,24 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Statuses of `user_exports`, matching `export_status` DB type.
+const (
+	StatusPending = "pending"
+	StatusReady   = "ready"
+	StatusFailed  = "failed"
+)
+
+// UserExport - entity of `user_exports` table.
+type UserExport struct {
+	UUID       string       `db:"uuid"`
+	WisherUUID string       `db:"wisher_uuid"`
+	Status     string       `db:"status"`
+	CreatedAt  time.Time    `db:"created_at"`
+	FinishedAt sql.NullTime `db:"finished_at"`
+	ExpiresAt  sql.NullTime `db:"expires_at"`
+	Error      string       `db:"error"`
+}
@@ -0,0 +1,148 @@
+/*
+Package storage contains db-related operations with user data exports.
+*/
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	storageSchema "github.com/outcatcher/anwil/domains/storage/schema"
+)
+
+// exportStorage - storage of user data exports.
+type exportStorage struct {
+	db storageSchema.QueryExecutor
+}
+
+// New creates a new ExportStorage instance.
+func New(db storageSchema.QueryExecutor) ExportStorage {
+	return &exportStorage{db: db}
+}
+
+// InsertExport creates new pending export.
+func (e *exportStorage) InsertExport(ctx context.Context, wisherUUID string) (*UserExport, error) {
+	export := new(UserExport)
+
+	err := e.db.GetContext(ctx, export, `INSERT INTO user_exports (wisher_uuid) VALUES ($1) RETURNING *;`, wisherUUID)
+	if err != nil {
+		return nil, fmt.Errorf("inserting export failed: %w", err)
+	}
+
+	return export, nil
+}
+
+// GetExport returns single export by UUID.
+func (e *exportStorage) GetExport(ctx context.Context, uuid string) (*UserExport, error) {
+	export := new(UserExport)
+
+	err := e.db.GetContext(ctx, export, `SELECT * FROM user_exports WHERE uuid = $1;`, uuid)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("no export found: %w", errbase.ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error selecting export: %w", err)
+	}
+
+	return export, nil
+}
+
+// GetPendingExport returns latest pending export of the user.
+func (e *exportStorage) GetPendingExport(ctx context.Context, wisherUUID string) (*UserExport, error) {
+	export := new(UserExport)
+
+	err := e.db.GetContext(
+		ctx,
+		export,
+		`SELECT * FROM user_exports WHERE wisher_uuid = $1 AND status = $2 ORDER BY created_at DESC LIMIT 1;`,
+		wisherUUID, StatusPending,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("no pending export found: %w", errbase.ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error selecting pending export: %w", err)
+	}
+
+	return export, nil
+}
+
+// ListExports returns all exports of the user.
+func (e *exportStorage) ListExports(ctx context.Context, wisherUUID string) ([]UserExport, error) {
+	var exports []UserExport
+
+	err := sqlx.SelectContext(
+		ctx, e.db, &exports, `SELECT * FROM user_exports WHERE wisher_uuid = $1 ORDER BY created_at;`, wisherUUID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting exports: %w", err)
+	}
+
+	return exports, nil
+}
+
+// FinishExport marks export as ready.
+func (e *exportStorage) FinishExport(ctx context.Context, uuid string, expiresAt time.Time) error {
+	_, err := e.db.ExecContext(
+		ctx,
+		`UPDATE user_exports SET status = $2, finished_at = now(), expires_at = $3 WHERE uuid = $1;`,
+		uuid, StatusReady, expiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("error finishing export: %w", err)
+	}
+
+	return nil
+}
+
+// FailExport marks export as failed with the given reason.
+func (e *exportStorage) FailExport(ctx context.Context, uuid, reason string) error {
+	_, err := e.db.ExecContext(
+		ctx,
+		`UPDATE user_exports SET status = $2, finished_at = now(), error = $3 WHERE uuid = $1;`,
+		uuid, StatusFailed, reason,
+	)
+	if err != nil {
+		return fmt.Errorf("error failing export: %w", err)
+	}
+
+	return nil
+}
+
+// ListExpiredExports returns ready exports expired before given time
+// and failed exports finished before failedBefore.
+func (e *exportStorage) ListExpiredExports(
+	ctx context.Context, before, failedBefore time.Time,
+) ([]UserExport, error) {
+	var exports []UserExport
+
+	err := sqlx.SelectContext(
+		ctx, e.db, &exports,
+		`SELECT * FROM user_exports
+		 WHERE (status = $1 AND expires_at < $2)
+		    OR (status = $3 AND finished_at < $4);`,
+		StatusReady, before, StatusFailed, failedBefore,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting expired exports: %w", err)
+	}
+
+	return exports, nil
+}
+
+// DeleteExport removes export.
+func (e *exportStorage) DeleteExport(ctx context.Context, uuid string) error {
+	_, err := e.db.ExecContext(ctx, `DELETE FROM user_exports WHERE uuid = $1;`, uuid)
+	if err != nil {
+		return fmt.Errorf("error deleting export: %w", err)
+	}
+
+	return nil
+}
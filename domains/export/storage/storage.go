@@ -0,0 +1,18 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// ExportStorage - storage of user data exports.
+type ExportStorage interface {
+	InsertExport(ctx context.Context, wisherUUID string) (*UserExport, error)
+	GetExport(ctx context.Context, uuid string) (*UserExport, error)
+	GetPendingExport(ctx context.Context, wisherUUID string) (*UserExport, error)
+	ListExports(ctx context.Context, wisherUUID string) ([]UserExport, error)
+	FinishExport(ctx context.Context, uuid string, expiresAt time.Time) error
+	FailExport(ctx context.Context, uuid, reason string) error
+	ListExpiredExports(ctx context.Context, before, failedBefore time.Time) ([]UserExport, error)
+	DeleteExport(ctx context.Context, uuid string) error
+}
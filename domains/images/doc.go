@@ -0,0 +1,7 @@
+/*
+Package images contains processing of uploaded images.
+
+Uploaded images are decoded and encoded again, so all the metadata (i.e. EXIF with location)
+is removed. EXIF orientation of JPEG images is applied to the pixels before.
+*/
+package images
@@ -0,0 +1,177 @@
+package images
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	"github.com/outcatcher/anwil/domains/core/validation"
+)
+
+const (
+	// maxPixels limits decoded image size, protecting from decompression bombs
+	maxPixels = 40_000_000
+
+	jpegQuality = 85
+
+	// DefaultThumbnailSize - thumbnail size used if none is configured.
+	DefaultThumbnailSize = 256
+	// DefaultMaxUploadSize - max upload size used if none is configured.
+	DefaultMaxUploadSize = 10 << 20
+)
+
+// Image - encoded image.
+type Image struct {
+	Data        []byte
+	ContentType string
+	// Extension - file extension matching content type, including leading dot
+	Extension string
+	Width     int
+	Height    int
+}
+
+// Processed - processed uploaded image.
+type Processed struct {
+	Image     Image
+	Thumbnail Image
+}
+
+// ReadLimited reads up to limit bytes, failing with errbase.ErrTooLarge if there is more.
+func ReadLimited(r io.Reader, limit int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading image: %w", err)
+	}
+
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%w: image exceeds %d bytes", errbase.ErrTooLarge, limit)
+	}
+
+	return data, nil
+}
+
+// Process validates uploaded image, removes its metadata and generates thumbnail
+// fitting into thumbnailSize x thumbnailSize square.
+//
+// Image type is detected by content, supported are JPEG, PNG and GIF. JPEG images are encoded as JPEG,
+// others are encoded as PNG. Only the first frame of animated GIF is used.
+func Process(data []byte, thumbnailSize int) (*Processed, error) {
+	contentType := http.DetectContentType(data)
+
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+	default:
+		return nil, fmt.Errorf("%w: %s, JPEG, PNG or GIF expected", errbase.ErrUnsupportedMediaType, contentType)
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid image: %w", validation.ErrValidationFailed, err)
+	}
+
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, fmt.Errorf("%w: image is %dx%d pixels", errbase.ErrTooLarge, cfg.Width, cfg.Height)
+	}
+
+	decoded, err := decode(contentType, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid image: %w", validation.ErrValidationFailed, err)
+	}
+
+	pixels := toRGBA(decoded)
+
+	if contentType == "image/jpeg" {
+		pixels = orient(pixels, jpegOrientation(data))
+	}
+
+	if thumbnailSize <= 0 {
+		thumbnailSize = DefaultThumbnailSize
+	}
+
+	encode := encodePNG
+	if contentType == "image/jpeg" {
+		encode = encodeJPEG
+	}
+
+	original, err := encode(pixels)
+	if err != nil {
+		return nil, err
+	}
+
+	thumbnail, err := encode(fit(pixels, thumbnailSize))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Processed{Image: *original, Thumbnail: *thumbnail}, nil
+}
+
+func decode(contentType string, data []byte) (image.Image, error) {
+	var (
+		decoded image.Image
+		err     error
+	)
+
+	switch contentType {
+	case "image/jpeg":
+		decoded, err = jpeg.Decode(bytes.NewReader(data))
+	case "image/png":
+		decoded, err = png.Decode(bytes.NewReader(data))
+	default:
+		decoded, err = gif.Decode(bytes.NewReader(data))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error decoding %s: %w", contentType, err)
+	}
+
+	return decoded, nil
+}
+
+func toRGBA(src image.Image) *image.RGBA {
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+
+	return dst
+}
+
+func encodeJPEG(img *image.RGBA) (*Image, error) {
+	buf := new(bytes.Buffer)
+
+	if err := jpeg.Encode(buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, fmt.Errorf("error encoding JPEG: %w", err)
+	}
+
+	return &Image{
+		Data:        buf.Bytes(),
+		ContentType: "image/jpeg",
+		Extension:   ".jpg",
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+	}, nil
+}
+
+func encodePNG(img *image.RGBA) (*Image, error) {
+	buf := new(bytes.Buffer)
+
+	if err := png.Encode(buf, img); err != nil {
+		return nil, fmt.Errorf("error encoding PNG: %w", err)
+	}
+
+	return &Image{
+		Data:        buf.Bytes(),
+		ContentType: "image/png",
+		Extension:   ".png",
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+	}, nil
+}
@@ -0,0 +1,185 @@
+package images
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	"github.com/outcatcher/anwil/domains/core/validation"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	red  = color.RGBA{R: 255, A: 255}
+	blue = color.RGBA{B: 255, A: 255}
+)
+
+// halves returns image with left half red and right half blue.
+func halves(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if x < width/2 {
+				img.Set(x, y, red)
+			} else {
+				img.Set(x, y, blue)
+			}
+		}
+	}
+
+	return img
+}
+
+// exifSegment returns APP1 segment with big-endian EXIF containing orientation only.
+func exifSegment(orientation uint16) []byte {
+	tiff := new(bytes.Buffer)
+	tiff.WriteString("MM")
+	_ = binary.Write(tiff, binary.BigEndian, uint16(42))
+	_ = binary.Write(tiff, binary.BigEndian, uint32(8))
+	_ = binary.Write(tiff, binary.BigEndian, uint16(1))
+	_ = binary.Write(tiff, binary.BigEndian, []uint16{orientationTag, typeShort})
+	_ = binary.Write(tiff, binary.BigEndian, uint32(1))
+	_ = binary.Write(tiff, binary.BigEndian, []uint16{orientation, 0})
+	_ = binary.Write(tiff, binary.BigEndian, uint32(0))
+
+	payload := append(append([]byte(nil), exifHeader...), tiff.Bytes()...)
+
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+
+	return append(segment, payload...)
+}
+
+func encodeTestJPEG(t *testing.T, img image.Image, orientation uint16) []byte {
+	t.Helper()
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, jpeg.Encode(buf, img, &jpeg.Options{Quality: 100}))
+
+	data := buf.Bytes()
+
+	// EXIF goes right after start of image marker
+	return append(append(append([]byte(nil), data[:2]...), exifSegment(orientation)...), data[2:]...)
+}
+
+func requireColor(t *testing.T, expected color.RGBA, actual color.Color) {
+	t.Helper()
+
+	r, g, b, _ := actual.RGBA()
+
+	// JPEG is lossy
+	const tolerance = 0x2000
+
+	require.InDelta(t, uint32(expected.R)*0x101, r, tolerance)
+	require.InDelta(t, uint32(expected.G)*0x101, g, tolerance)
+	require.InDelta(t, uint32(expected.B)*0x101, b, tolerance)
+}
+
+func TestProcess_JPEGOrientation(t *testing.T) {
+	t.Parallel()
+
+	data := encodeTestJPEG(t, halves(64, 32), 6)
+	require.Equal(t, 6, jpegOrientation(data))
+
+	processed, err := Process(data, 16)
+	require.NoError(t, err)
+
+	require.Equal(t, "image/jpeg", processed.Image.ContentType)
+	require.Equal(t, ".jpg", processed.Image.Extension)
+	require.NotContains(t, string(processed.Image.Data), "Exif")
+	require.Equal(t, 1, jpegOrientation(processed.Image.Data))
+
+	// rotated clockwise: left half is on top now
+	require.Equal(t, 32, processed.Image.Width)
+	require.Equal(t, 64, processed.Image.Height)
+
+	decoded, err := jpeg.Decode(bytes.NewReader(processed.Image.Data))
+	require.NoError(t, err)
+
+	requireColor(t, red, decoded.At(16, 8))
+	requireColor(t, blue, decoded.At(16, 56))
+
+	require.Equal(t, 8, processed.Thumbnail.Width)
+	require.Equal(t, 16, processed.Thumbnail.Height)
+}
+
+func TestProcess_PNG(t *testing.T) {
+	t.Parallel()
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, png.Encode(buf, halves(400, 200)))
+
+	processed, err := Process(buf.Bytes(), 0)
+	require.NoError(t, err)
+
+	require.Equal(t, "image/png", processed.Thumbnail.ContentType)
+	require.Equal(t, DefaultThumbnailSize, processed.Thumbnail.Width)
+	require.Equal(t, DefaultThumbnailSize/2, processed.Thumbnail.Height)
+
+	thumbnail, err := png.Decode(bytes.NewReader(processed.Thumbnail.Data))
+	require.NoError(t, err)
+
+	requireColor(t, red, thumbnail.At(10, 10))
+	requireColor(t, blue, thumbnail.At(DefaultThumbnailSize-10, 10))
+}
+
+// pngHeader returns PNG signature and header chunk of the image with given dimensions.
+func pngHeader(width, height uint32) []byte {
+	chunk := new(bytes.Buffer)
+	chunk.WriteString("IHDR")
+	_ = binary.Write(chunk, binary.BigEndian, []uint32{width, height})
+	chunk.Write([]byte{8, 6, 0, 0, 0}) // 8-bit RGBA
+
+	result := new(bytes.Buffer)
+	result.WriteString("\x89PNG\r\n\x1a\n")
+	_ = binary.Write(result, binary.BigEndian, uint32(chunk.Len()-4))
+	result.Write(chunk.Bytes())
+	_ = binary.Write(result, binary.BigEndian, crc32.ChecksumIEEE(chunk.Bytes()))
+
+	return result.Bytes()
+}
+
+func TestProcess_invalid(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		data     []byte
+		expected error
+	}{
+		{"text", []byte("definitely not an image"), errbase.ErrUnsupportedMediaType},
+		{"svg", []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`), errbase.ErrUnsupportedMediaType},
+		{"decompression bomb", pngHeader(100_000, 100_000), errbase.ErrTooLarge},
+		{"truncated", pngHeader(10, 10), validation.ErrValidationFailed},
+	}
+
+	for _, data := range cases {
+		data := data
+
+		t.Run(data.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Process(data.data, 0)
+			require.ErrorIs(t, err, data.expected)
+		})
+	}
+}
+
+func TestReadLimited(t *testing.T) {
+	t.Parallel()
+
+	data, err := ReadLimited(strings.NewReader("1234"), 4)
+	require.NoError(t, err)
+	require.Equal(t, "1234", string(data))
+
+	_, err = ReadLimited(strings.NewReader("12345"), 4)
+	require.ErrorIs(t, err, errbase.ErrTooLarge)
+}
@@ -0,0 +1,63 @@
+package images
+
+import (
+	"image"
+)
+
+// fit downscales image to fit into size x size square keeping aspect ratio.
+//
+// Smaller images are returned as is. Each target pixel is an average of the covered source pixels.
+func fit(src *image.RGBA, size int) *image.RGBA {
+	srcWidth, srcHeight := src.Bounds().Dx(), src.Bounds().Dy()
+
+	if srcWidth <= size && srcHeight <= size {
+		return src
+	}
+
+	dstWidth, dstHeight := size, size
+
+	if srcWidth > srcHeight {
+		dstHeight = maxInt(1, srcHeight*size/srcWidth)
+	} else {
+		dstWidth = maxInt(1, srcWidth*size/srcHeight)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+
+	for y := 0; y < dstHeight; y++ {
+		y0, y1 := y*srcHeight/dstHeight, maxInt((y+1)*srcHeight/dstHeight, y*srcHeight/dstHeight+1)
+
+		for x := 0; x < dstWidth; x++ {
+			x0, x1 := x*srcWidth/dstWidth, maxInt((x+1)*srcWidth/dstWidth, x*srcWidth/dstWidth+1)
+
+			var sum [4]int
+
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+
+				for sx := x0; sx < x1; sx++ {
+					for c := 0; c < 4; c++ {
+						sum[c] += int(row[sx*4+c])
+					}
+				}
+			}
+
+			count := (x1 - x0) * (y1 - y0)
+			offset := y*dst.Stride + x*4
+
+			for c := 0; c < 4; c++ {
+				dst.Pix[offset+c] = uint8(sum[c] / count)
+			}
+		}
+	}
+
+	return dst
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,211 @@
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errInvalidSpec = errors.New("invalid cron expression")
+
+// cronDescriptors - supported shortcuts of standard expressions.
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+const everyPrefix = "@every "
+
+// maxSearchYears limits search of the next matching time, i.e. for `0 0 31 2 *`.
+const maxSearchYears = 5
+
+// schedule returns next activation time after given time.
+type schedule interface {
+	Next(after time.Time) time.Time
+}
+
+// intervalSchedule - schedule running every fixed interval, aligned to Unix epoch.
+//
+// Alignment makes all the instances share the same activation times.
+type intervalSchedule struct {
+	interval time.Duration
+}
+
+func (s intervalSchedule) Next(after time.Time) time.Time {
+	return after.Truncate(s.interval).Add(s.interval)
+}
+
+// cronSchedule - schedule defined by standard 5-field cron expression, evaluated in UTC.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+
+	// day of month and week restrictions are combined with OR if both are restricted
+	domAny, dowAny bool
+}
+
+// Next returns next matching minute after given time. Zero time is returned if there is none.
+func (s cronSchedule) Next(after time.Time) time.Time {
+	next := after.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := next.AddDate(maxSearchYears, 0, 0)
+
+	for next.Before(limit) {
+		switch {
+		case !has(s.month, int(next.Month())):
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.dayMatches(next):
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, time.UTC)
+		case !has(s.hour, next.Hour()):
+			next = next.Truncate(time.Hour).Add(time.Hour)
+		case !has(s.minute, next.Minute()):
+			next = next.Add(time.Minute)
+		default:
+			return next
+		}
+	}
+
+	return time.Time{}
+}
+
+func (s cronSchedule) dayMatches(t time.Time) bool {
+	domMatches := has(s.dom, t.Day())
+	dowMatches := has(s.dow, int(t.Weekday()))
+
+	if s.domAny || s.dowAny {
+		return domMatches && dowMatches
+	}
+
+	return domMatches || dowMatches
+}
+
+func has(set uint64, value int) bool {
+	return set&(1<<uint(value)) != 0
+}
+
+// parseSchedule parses cron expression.
+//
+// Supported are standard 5-field expressions (`*`, `*/n`, `a-b`, `a-b/n` and lists),
+// descriptors like `@daily` and fixed intervals like `@every 10m`.
+func parseSchedule(spec string) (schedule, error) {
+	spec = strings.TrimSpace(spec)
+
+	if strings.HasPrefix(spec, everyPrefix) {
+		interval, err := time.ParseDuration(strings.TrimPrefix(spec, everyPrefix))
+		if err != nil || interval < time.Minute {
+			return nil, fmt.Errorf("%w %q: interval must be at least 1m", errInvalidSpec, spec)
+		}
+
+		return intervalSchedule{interval: interval}, nil
+	}
+
+	if expanded, ok := cronDescriptors[spec]; ok {
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 { //nolint:gomnd
+		return nil, fmt.Errorf("%w %q: 5 fields expected", errInvalidSpec, spec)
+	}
+
+	result := cronSchedule{
+		domAny: fields[2] == "*",
+		dowAny: fields[4] == "*",
+	}
+
+	bounds := []struct {
+		target       *uint64
+		lower, upper int
+	}{
+		{&result.minute, 0, 59},
+		{&result.hour, 0, 23},
+		{&result.dom, 1, 31},
+		{&result.month, 1, 12},
+		{&result.dow, 0, 7},
+	}
+
+	for i, bound := range bounds {
+		set, err := parseField(fields[i], bound.lower, bound.upper)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %w", errInvalidSpec, spec, err)
+		}
+
+		*bound.target = set
+	}
+
+	// both 0 and 7 are Sunday
+	if has(result.dow, 7) { //nolint:gomnd
+		result.dow |= 1
+	}
+
+	return result, nil
+}
+
+// parseField parses single comma-separated field into a bit set.
+func parseField(field string, lower, upper int) (uint64, error) {
+	var set uint64
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+
+		if hasStep {
+			parsed, err := strconv.Atoi(stepPart)
+			if err != nil || parsed < 1 {
+				return 0, fmt.Errorf("invalid step %q", stepPart) //nolint:goerr113
+			}
+
+			step = parsed
+		}
+
+		start, end, err := parseRange(rangePart, lower, upper)
+		if err != nil {
+			return 0, err
+		}
+
+		// `5/15` means `5-max/15`
+		if hasStep && !strings.Contains(rangePart, "-") {
+			end = upper
+		}
+
+		for value := start; value <= end; value += step {
+			set |= 1 << uint(value)
+		}
+	}
+
+	return set, nil
+}
+
+func parseRange(part string, lower, upper int) (int, int, error) {
+	if part == "*" {
+		return lower, upper, nil
+	}
+
+	startPart, endPart, isRange := strings.Cut(part, "-")
+
+	start, err := strconv.Atoi(startPart)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid value %q", startPart) //nolint:goerr113
+	}
+
+	end := start
+
+	if isRange {
+		end, err = strconv.Atoi(endPart)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid value %q", endPart) //nolint:goerr113
+		}
+	}
+
+	if start < lower || end > upper || start > end {
+		return 0, 0, fmt.Errorf("range %q is out of bounds [%d, %d]", part, lower, upper) //nolint:goerr113
+	}
+
+	return start, end, nil
+}
@@ -0,0 +1,76 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule(t *testing.T) {
+	t.Parallel()
+
+	// Friday
+	after := time.Date(2023, time.March, 31, 10, 17, 30, 0, time.UTC)
+
+	cases := []struct {
+		spec     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2023, time.March, 31, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2023, time.March, 31, 10, 30, 0, 0, time.UTC)},
+		{"5,10 * * * *", time.Date(2023, time.March, 31, 11, 5, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2023, time.March, 31, 13, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2023, time.March, 31, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2023, time.April, 2, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2023, time.April, 2, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		// either day of month or day of week
+		{"0 0 15 * 1", time.Date(2023, time.April, 3, 0, 0, 0, 0, time.UTC)},
+		{"@every 10m", time.Date(2023, time.March, 31, 10, 20, 0, 0, time.UTC)},
+		{"0 0 31 2 *", time.Time{}},
+	}
+
+	for _, data := range cases {
+		data := data
+
+		t.Run(data.spec, func(t *testing.T) {
+			t.Parallel()
+
+			parsed, err := parseSchedule(data.spec)
+			require.NoError(t, err)
+			require.Equal(t, data.expected, parsed.Next(after))
+		})
+	}
+}
+
+func TestParseSchedule_invalid(t *testing.T) {
+	t.Parallel()
+
+	cases := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@every 10s",
+		"@every forever",
+	}
+
+	for _, spec := range cases {
+		spec := spec
+
+		t.Run(spec, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := parseSchedule(spec)
+			require.ErrorIs(t, err, errInvalidSpec)
+		})
+	}
+}
@@ -0,0 +1,8 @@
+/*
+Package jobs contains background job runner.
+
+Jobs are stored in Postgres queue and are claimed by workers using `FOR UPDATE SKIP LOCKED`,
+so multiple API instances can process the same queue. Failed jobs are retried with exponential backoff.
+Periodic jobs are enqueued by the scheduler using cron expressions.
+*/
+package jobs
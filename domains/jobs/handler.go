@@ -0,0 +1,25 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/outcatcher/anwil/domains/jobs/schema"
+)
+
+// Typed wraps handler of JSON-encoded payload of type T.
+//
+// Payload which can't be decoded fails the job without retries.
+func Typed[T any](handler func(ctx context.Context, payload T) error) svcSchema.JobHandler {
+	return func(ctx context.Context, payload []byte) error {
+		var decoded T
+
+		if err := json.Unmarshal(payload, &decoded); err != nil {
+			return fmt.Errorf("%w: error decoding job payload: %w", schema.ErrPermanent, err)
+		}
+
+		return handler(ctx, decoded)
+	}
+}
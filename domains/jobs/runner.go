@@ -0,0 +1,448 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/outcatcher/anwil/domains/jobs/schema"
+	"github.com/outcatcher/anwil/domains/jobs/storage"
+	storageSchema "github.com/outcatcher/anwil/domains/storage/schema"
+)
+
+const (
+	defaultWorkers      = 4
+	defaultPollInterval = time.Second
+	defaultMaxAttempts  = 5
+	defaultTimeout      = 10 * time.Minute
+
+	// retries are delayed by baseRetryDelay * 2^(attempt-1), but not more than maxRetryDelay
+	baseRetryDelay = 10 * time.Second
+	maxRetryDelay  = time.Hour
+
+	// storageTimeout - timeout of job state updates
+	storageTimeout = 10 * time.Second
+
+	// finished jobs are kept for debugging purposes
+	finishedJobsTTL     = 7 * 24 * time.Hour
+	cleanupJobKind      = "jobs.cleanup"
+	cleanupJobsSchedule = "@daily"
+)
+
+var (
+	errDuplicateHandler = errors.New("job handler already registered")
+	errMissingHandler   = errors.New("no handler registered for job kind")
+	errAlreadyStarted   = errors.New("job runner is already started")
+)
+
+// scheduledJob - job periodically enqueued by the scheduler.
+type scheduledJob struct {
+	kind     string
+	schedule schedule
+	next     time.Time
+}
+
+// Runner - background job queue and worker pool.
+type Runner struct {
+	storage storage.JobStorage
+	log     *log.Logger
+
+	workers      int
+	pollInterval time.Duration
+	maxAttempts  int
+	timeout      time.Duration
+
+	mu        sync.Mutex
+	handlers  map[string]svcSchema.JobHandler
+	scheduled []*scheduledJob
+
+	// wake triggers polling without waiting for the next tick
+	wake chan struct{}
+	// slots limits number of concurrently running jobs
+	slots chan struct{}
+
+	stopPolling context.CancelFunc
+	stopJobs    context.CancelFunc
+	pollDone    chan struct{}
+	running     sync.WaitGroup
+}
+
+// New creates new job runner using given DB.
+func New(cfg configSchema.JobsConfiguration, db storageSchema.QueryExecutor, logger *log.Logger) *Runner {
+	runner := &Runner{
+		storage:      storage.New(db),
+		log:          logger,
+		workers:      cfg.Workers,
+		pollInterval: cfg.PollInterval,
+		maxAttempts:  cfg.MaxAttempts,
+		timeout:      cfg.Timeout,
+		handlers:     make(map[string]svcSchema.JobHandler),
+		wake:         make(chan struct{}, 1),
+	}
+
+	if runner.workers <= 0 {
+		runner.workers = defaultWorkers
+	}
+
+	if runner.pollInterval <= 0 {
+		runner.pollInterval = defaultPollInterval
+	}
+
+	if runner.maxAttempts <= 0 {
+		runner.maxAttempts = defaultMaxAttempts
+	}
+
+	if runner.timeout <= 0 {
+		runner.timeout = defaultTimeout
+	}
+
+	runner.slots = make(chan struct{}, runner.workers)
+
+	// errors are impossible for the fresh runner and valid spec
+	_ = runner.Handle(cleanupJobKind, runner.cleanup)
+	_ = runner.Schedule(cleanupJobKind, cleanupJobsSchedule)
+
+	return runner
+}
+
+// Handle registers handler for the jobs of given kind.
+func (r *Runner) Handle(kind string, handler svcSchema.JobHandler) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.handlers[kind]; ok {
+		return fmt.Errorf("%w: %s", errDuplicateHandler, kind)
+	}
+
+	r.handlers[kind] = handler
+
+	return nil
+}
+
+// Schedule registers periodic enqueuing of the job of given kind using cron expression.
+//
+// Job payload is `null`. Jobs are scheduled in UTC.
+func (r *Runner) Schedule(kind, spec string) error {
+	parsed, err := parseSchedule(spec)
+	if err != nil {
+		return fmt.Errorf("error scheduling job %s: %w", kind, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.scheduled = append(r.scheduled, &scheduledJob{
+		kind:     kind,
+		schedule: parsed,
+		next:     parsed.Next(time.Now()),
+	})
+
+	return nil
+}
+
+// Enqueue adds new job of given kind with JSON-encoded payload to the queue.
+func (r *Runner) Enqueue(ctx context.Context, kind string, payload any, opts ...schema.EnqueueOption) error {
+	options := new(schema.EnqueueOptions)
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error encoding job payload: %w", err)
+	}
+
+	job := storage.Job{ //nolint:exhaustruct
+		Kind:        kind,
+		Payload:     encoded,
+		MaxAttempts: r.maxAttempts,
+		RunAt:       options.RunAt,
+	}
+
+	if options.MaxAttempts > 0 {
+		job.MaxAttempts = options.MaxAttempts
+	}
+
+	if job.RunAt.IsZero() {
+		job.RunAt = time.Now()
+	}
+
+	if options.UniqueKey != "" {
+		job.UniqueKey.String = options.UniqueKey
+		job.UniqueKey.Valid = true
+	}
+
+	jobStorage := r.storage
+	if options.Tx != nil {
+		jobStorage = storage.New(options.Tx)
+	}
+
+	if _, err := jobStorage.InsertJob(ctx, job); err != nil {
+		return fmt.Errorf("error enqueuing job %s: %w", kind, err)
+	}
+
+	// job inserted in transaction is not visible to workers until commit
+	if options.Tx == nil && !job.RunAt.After(time.Now()) {
+		select {
+		case r.wake <- struct{}{}:
+		default: // polling is already triggered
+		}
+	}
+
+	return nil
+}
+
+// DeleteJobs removes jobs of given kind with payload containing given JSON-encoded value using given transaction.
+//
+// Finished jobs are removed as well, while jobs being run are not stopped.
+func (r *Runner) DeleteJobs(ctx context.Context, tx storageSchema.QueryExecutor, kind string, payload any) error {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error encoding job payload: %w", err)
+	}
+
+	if _, err := storage.New(tx).DeleteJobs(ctx, kind, encoded); err != nil {
+		return fmt.Errorf("error deleting jobs %s: %w", kind, err)
+	}
+
+	return nil
+}
+
+// Start starts scheduler and workers. Runner works until Stop is called.
+func (r *Runner) Start() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.pollDone != nil {
+		return errAlreadyStarted
+	}
+
+	pollCtx, stopPolling := context.WithCancel(context.Background())
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+
+	r.stopPolling = stopPolling
+	r.stopJobs = stopJobs
+	r.pollDone = make(chan struct{})
+
+	go r.poll(pollCtx, jobsCtx)
+
+	r.log.Printf("job runner started with %d workers", r.workers)
+
+	return nil
+}
+
+// Stop stops polling for new jobs and waits for running jobs to finish.
+//
+// If ctx is done before jobs are finished, jobs are cancelled and returned to the queue.
+func (r *Runner) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	pollDone := r.pollDone
+	r.mu.Unlock()
+
+	if pollDone == nil {
+		return nil
+	}
+
+	r.stopPolling()
+	<-pollDone
+
+	finished := make(chan struct{})
+
+	go func() {
+		r.running.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		r.stopJobs()
+
+		return nil
+	case <-ctx.Done():
+		r.stopJobs()
+		<-finished
+
+		return fmt.Errorf("error waiting for running jobs: %w", ctx.Err())
+	}
+}
+
+func (r *Runner) poll(pollCtx, jobsCtx context.Context) {
+	defer close(r.pollDone)
+
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		r.enqueueScheduled(pollCtx, time.Now())
+		r.releaseStale(pollCtx)
+
+		// queue can contain more due jobs than there were free workers
+		for claimedAll := true; claimedAll; {
+			claimedAll = r.dispatch(pollCtx, jobsCtx)
+		}
+
+		select {
+		case <-pollCtx.Done():
+			return
+		case <-ticker.C:
+		case <-r.wake:
+		}
+	}
+}
+
+// dispatch claims jobs for all free workers. Returns true if all free workers got a job.
+func (r *Runner) dispatch(pollCtx, jobsCtx context.Context) bool {
+	free := cap(r.slots) - len(r.slots)
+	if free == 0 {
+		return false
+	}
+
+	jobs, err := r.storage.ClaimJobs(pollCtx, free)
+	if err != nil {
+		if pollCtx.Err() == nil {
+			r.log.Printf("error polling jobs: %s", err)
+		}
+
+		return false
+	}
+
+	for _, job := range jobs {
+		r.slots <- struct{}{}
+
+		r.running.Add(1)
+
+		go func(job storage.Job) {
+			defer func() {
+				<-r.slots
+				r.running.Done()
+			}()
+
+			r.run(jobsCtx, job)
+		}(job)
+	}
+
+	return len(jobs) == free
+}
+
+// run executes single job attempt and stores its result.
+func (r *Runner) run(ctx context.Context, job storage.Job) {
+	err := r.execute(ctx, job)
+
+	// job state is stored even if runner is stopped
+	storeCtx, cancel := context.WithTimeout(context.Background(), storageTimeout)
+	defer cancel()
+
+	switch {
+	case err == nil:
+		err = r.storage.CompleteJob(storeCtx, job.UUID)
+	case errors.Is(err, schema.ErrPermanent) || job.Attempts >= job.MaxAttempts:
+		r.log.Printf("job %s (%s) failed after %d attempts: %s", job.UUID, job.Kind, job.Attempts, err)
+
+		err = r.storage.FailJob(storeCtx, job.UUID, err.Error())
+	default:
+		r.log.Printf("job %s (%s) attempt %d failed: %s", job.UUID, job.Kind, job.Attempts, err)
+
+		err = r.storage.RetryJob(storeCtx, job.UUID, time.Now().Add(retryDelay(job.Attempts)), err.Error())
+	}
+
+	if err != nil {
+		r.log.Printf("error storing job %s state: %s", job.UUID, err)
+	}
+}
+
+func (r *Runner) execute(ctx context.Context, job storage.Job) (err error) {
+	r.mu.Lock()
+	handler, ok := r.handlers[job.Kind]
+	r.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%w: %w %s", schema.ErrPermanent, errMissingHandler, job.Kind)
+	}
+
+	ctx, cancel := context.WithTimeout(schema.WithAttempt(ctx, job.Attempts, job.MaxAttempts), r.timeout)
+	defer cancel()
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("job handler panicked: %v", rec) //nolint:goerr113
+		}
+	}()
+
+	return handler(ctx, job.Payload)
+}
+
+// retryDelay returns delay before next attempt of the job failed given number of times.
+func retryDelay(attempts int) time.Duration {
+	delay := baseRetryDelay
+
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+
+	return delay
+}
+
+// enqueueScheduled enqueues scheduled jobs due at given time.
+//
+// Unique keys make sure the job is enqueued once even if multiple runners share the queue.
+func (r *Runner) enqueueScheduled(ctx context.Context, now time.Time) {
+	r.mu.Lock()
+	scheduled := append([]*scheduledJob(nil), r.scheduled...)
+	r.mu.Unlock()
+
+	for _, job := range scheduled {
+		if job.next.IsZero() || now.Before(job.next) {
+			continue
+		}
+
+		key := fmt.Sprintf("schedule:%s:%d", job.kind, job.next.Unix())
+
+		if err := r.Enqueue(ctx, job.kind, nil, schema.UniqueKey(key)); err != nil {
+			r.log.Printf("error enqueuing scheduled job %s: %s", job.kind, err)
+
+			continue // retried on the next poll
+		}
+
+		job.next = job.schedule.Next(now)
+	}
+}
+
+// releaseStale returns jobs left running by crashed runners to the queue.
+func (r *Runner) releaseStale(ctx context.Context) {
+	// lock is considered stale only after job timeout, so running jobs are not affected
+	released, err := r.storage.ReleaseStaleJobs(ctx, time.Now().Add(-r.timeout-storageTimeout))
+	if err != nil {
+		if ctx.Err() == nil {
+			r.log.Printf("error releasing stale jobs: %s", err)
+		}
+
+		return
+	}
+
+	if released > 0 {
+		r.log.Printf("%d stale jobs returned to the queue", released)
+	}
+}
+
+// cleanup removes old finished jobs.
+func (r *Runner) cleanup(ctx context.Context, _ []byte) error {
+	deleted, err := r.storage.DeleteFinishedJobs(ctx, time.Now().Add(-finishedJobsTTL))
+	if err != nil {
+		return fmt.Errorf("error cleaning up jobs: %w", err)
+	}
+
+	r.log.Printf("%d finished jobs removed", deleted)
+
+	return nil
+}
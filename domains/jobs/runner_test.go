@@ -0,0 +1,292 @@
+package jobs
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
+	th "github.com/outcatcher/anwil/domains/core/testhelpers"
+	"github.com/outcatcher/anwil/domains/jobs/schema"
+	"github.com/outcatcher/anwil/domains/jobs/storage"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockStorage struct {
+	mock.Mock
+}
+
+func (m *mockStorage) InsertJob(ctx context.Context, job storage.Job) (bool, error) {
+	args := m.Called(ctx, job)
+
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockStorage) ClaimJobs(ctx context.Context, limit int) ([]storage.Job, error) {
+	args := m.Called(ctx, limit)
+
+	return args.Get(0).([]storage.Job), args.Error(1) //nolint:forcetypeassert
+}
+
+func (m *mockStorage) CompleteJob(ctx context.Context, uuid string) error {
+	return m.Called(ctx, uuid).Error(0)
+}
+
+func (m *mockStorage) RetryJob(ctx context.Context, uuid string, runAt time.Time, reason string) error {
+	return m.Called(ctx, uuid, runAt, reason).Error(0)
+}
+
+func (m *mockStorage) FailJob(ctx context.Context, uuid, reason string) error {
+	return m.Called(ctx, uuid, reason).Error(0)
+}
+
+func (m *mockStorage) ReleaseStaleJobs(ctx context.Context, lockedBefore time.Time) (int64, error) {
+	args := m.Called(ctx, lockedBefore)
+
+	return args.Get(0).(int64), args.Error(1) //nolint:forcetypeassert
+}
+
+func (m *mockStorage) DeleteFinishedJobs(ctx context.Context, finishedBefore time.Time) (int64, error) {
+	args := m.Called(ctx, finishedBefore)
+
+	return args.Get(0).(int64), args.Error(1) //nolint:forcetypeassert
+}
+
+func (m *mockStorage) DeleteJobs(ctx context.Context, kind string, payload []byte) (int64, error) {
+	args := m.Called(ctx, kind, payload)
+
+	return args.Get(0).(int64), args.Error(1) //nolint:forcetypeassert
+}
+
+func newTestRunner(store storage.JobStorage) *Runner {
+	runner := New(configSchema.JobsConfiguration{PollInterval: 10 * time.Millisecond}, nil, log.Default())
+	runner.storage = store
+
+	return runner
+}
+
+func newTestJob(kind string) storage.Job {
+	return storage.Job{
+		UUID:        th.RandomString("uuid-", 10),
+		Kind:        kind,
+		Payload:     []byte(`{"value":"payload"}`),
+		Status:      storage.StatusRunning,
+		Attempts:    1,
+		MaxAttempts: 3,
+	}
+}
+
+func TestEnqueue(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	runAt := time.Now().Add(time.Hour)
+
+	store := new(mockStorage)
+	store.
+		On("InsertJob", ctx, mock.AnythingOfType("storage.Job")).
+		Return(true, nil)
+
+	err := newTestRunner(store).Enqueue(
+		ctx, "kind", map[string]int{"value": 1},
+		schema.RunAt(runAt), schema.MaxAttempts(1), schema.UniqueKey("key"),
+	)
+	require.NoError(t, err)
+
+	job := store.Calls[0].Arguments.Get(1).(storage.Job) //nolint:forcetypeassert
+	require.Equal(t, "kind", job.Kind)
+	require.JSONEq(t, `{"value":1}`, string(job.Payload))
+	require.Equal(t, runAt, job.RunAt)
+	require.Equal(t, 1, job.MaxAttempts)
+	require.Equal(t, "key", job.UniqueKey.String)
+}
+
+func TestEnqueueInTx(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	tx := new(th.MockDBExecutor)
+	tx.
+		On("GetContext", ctx, mock.Anything, mock.AnythingOfType("string"), mock.Anything).
+		Return(nil)
+
+	store := new(mockStorage)
+	runner := newTestRunner(store)
+
+	require.NoError(t, runner.Enqueue(ctx, "kind", nil, schema.InTx(tx)))
+
+	tx.AssertNumberOfCalls(t, "GetContext", 1)
+	store.AssertNotCalled(t, "InsertJob")
+	require.Empty(t, runner.wake, "polling is triggered before commit")
+}
+
+func TestDeleteJobs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	tx := new(th.MockDBExecutor)
+	tx.
+		On("ExecContext", ctx, mock.AnythingOfType("string"), []any{"kind", `{"email":"user@example.com"}`}).
+		Return(driver.RowsAffected(1), nil)
+
+	err := newTestRunner(new(mockStorage)).DeleteJobs(ctx, tx, "kind", map[string]string{"email": "user@example.com"})
+	require.NoError(t, err)
+
+	tx.AssertNumberOfCalls(t, "ExecContext", 1)
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	expectedErr := errors.New("expected") //nolint:goerr113
+
+	cases := []struct {
+		name     string
+		handler  func(context.Context, []byte) error
+		attempts int
+		expected string
+	}{
+		{"ok", func(context.Context, []byte) error { return nil }, 1, "CompleteJob"},
+		{"error", func(context.Context, []byte) error { return expectedErr }, 1, "RetryJob"},
+		{"panic", func(context.Context, []byte) error { panic("expected") }, 1, "RetryJob"},
+		{"last attempt", func(context.Context, []byte) error { return expectedErr }, 3, "FailJob"},
+		{"permanent", func(context.Context, []byte) error { return schema.ErrPermanent }, 1, "FailJob"},
+	}
+
+	for _, data := range cases {
+		data := data
+
+		t.Run(data.name, func(t *testing.T) {
+			t.Parallel()
+
+			job := newTestJob("kind")
+			job.Attempts = data.attempts
+
+			store := new(mockStorage)
+			store.On(data.expected, mock.Anything, job.UUID).Return(nil)
+			store.On(data.expected, mock.Anything, job.UUID, mock.Anything).Return(nil)
+			store.On(data.expected, mock.Anything, job.UUID, mock.Anything, mock.Anything).Return(nil)
+
+			runner := newTestRunner(store)
+			require.NoError(t, runner.Handle("kind", data.handler))
+
+			runner.run(context.Background(), job)
+
+			store.AssertNumberOfCalls(t, data.expected, 1)
+		})
+	}
+
+	t.Run("missing handler", func(t *testing.T) {
+		t.Parallel()
+
+		job := newTestJob(th.RandomString("kind-", 5))
+
+		store := new(mockStorage)
+		store.On("FailJob", mock.Anything, job.UUID, mock.AnythingOfType("string")).Return(nil)
+
+		newTestRunner(store).run(context.Background(), job)
+
+		store.AssertNumberOfCalls(t, "FailJob", 1)
+	})
+}
+
+func TestHandle_duplicate(t *testing.T) {
+	t.Parallel()
+
+	runner := newTestRunner(new(mockStorage))
+
+	handler := func(context.Context, []byte) error { return nil }
+
+	require.NoError(t, runner.Handle("kind", handler))
+	require.ErrorIs(t, runner.Handle("kind", handler), errDuplicateHandler)
+}
+
+func TestRetryDelay(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, baseRetryDelay, retryDelay(1))
+	require.Equal(t, 4*baseRetryDelay, retryDelay(3))
+	require.Equal(t, maxRetryDelay, retryDelay(100))
+}
+
+func TestEnqueueScheduled(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	store := new(mockStorage)
+	store.
+		On("InsertJob", ctx, mock.AnythingOfType("storage.Job")).
+		Return(true, nil)
+
+	runner := newTestRunner(store)
+	require.NoError(t, runner.Schedule("kind", "@hourly"))
+
+	scheduled := runner.scheduled[len(runner.scheduled)-1]
+	due := scheduled.next
+
+	// not due yet
+	runner.enqueueScheduled(ctx, due.Add(-time.Second))
+	store.AssertNotCalled(t, "InsertJob")
+
+	runner.enqueueScheduled(ctx, due)
+
+	// cleanup job is scheduled daily, so only one job is enqueued
+	store.AssertNumberOfCalls(t, "InsertJob", 1)
+
+	job := store.Calls[0].Arguments.Get(1).(storage.Job) //nolint:forcetypeassert
+	require.Equal(t, "kind", job.Kind)
+	require.Contains(t, job.UniqueKey.String, "kind")
+	require.Equal(t, due.Add(time.Hour), scheduled.next)
+}
+
+func TestStartStop(t *testing.T) {
+	t.Parallel()
+
+	job := newTestJob("kind")
+	processed := make(chan []byte, 1)
+
+	store := new(mockStorage)
+	store.On("ReleaseStaleJobs", mock.Anything, mock.Anything).Return(int64(0), nil)
+	store.On("ClaimJobs", mock.Anything, mock.Anything).Return([]storage.Job{job}, nil).Once()
+	store.On("ClaimJobs", mock.Anything, mock.Anything).Return([]storage.Job{}, nil)
+	store.On("CompleteJob", mock.Anything, job.UUID).Return(nil)
+
+	runner := newTestRunner(store)
+	require.NoError(t, runner.Handle("kind", Typed(func(_ context.Context, payload map[string]string) error {
+		processed <- []byte(payload["value"])
+
+		return nil
+	})))
+
+	require.NoError(t, runner.Start())
+	require.ErrorIs(t, runner.Start(), errAlreadyStarted)
+
+	select {
+	case value := <-processed:
+		require.Equal(t, "payload", string(value))
+	case <-time.After(time.Second):
+		require.FailNow(t, "job is not processed")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	require.NoError(t, runner.Stop(ctx))
+
+	store.AssertNumberOfCalls(t, "CompleteJob", 1)
+}
+
+func TestTyped_invalidPayload(t *testing.T) {
+	t.Parallel()
+
+	handler := Typed(func(context.Context, int) error { return nil })
+
+	require.ErrorIs(t, handler(context.Background(), []byte(`"not a number"`)), schema.ErrPermanent)
+}
@@ -0,0 +1,113 @@
+/*
+Package schema contains background job DTOs
+*/
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/outcatcher/anwil/domains/core/services"
+	storageSchema "github.com/outcatcher/anwil/domains/storage/schema"
+)
+
+// ErrPermanent - job failure which should not be retried.
+var ErrPermanent = errors.New("permanent job failure")
+
+// EnqueueOptions - parameters of the enqueued job.
+type EnqueueOptions struct {
+	// RunAt is the earliest time job is started at. Job is started as soon as possible if zero.
+	RunAt time.Time
+	// MaxAttempts overrides configured number of attempts if positive.
+	MaxAttempts int
+	// UniqueKey prevents enqueuing job with the same key twice if not empty.
+	UniqueKey string
+	// Tx is a transaction job is inserted with. Job is not run until the transaction is committed.
+	Tx storageSchema.QueryExecutor
+}
+
+// EnqueueOption - option of the enqueued job.
+type EnqueueOption func(opts *EnqueueOptions)
+
+// RunAt sets the earliest time job is started at.
+func RunAt(runAt time.Time) EnqueueOption {
+	return func(opts *EnqueueOptions) {
+		opts.RunAt = runAt
+	}
+}
+
+// MaxAttempts sets number of attempts before job is marked as failed.
+func MaxAttempts(attempts int) EnqueueOption {
+	return func(opts *EnqueueOptions) {
+		opts.MaxAttempts = attempts
+	}
+}
+
+// UniqueKey makes job unique: job with the same key is never enqueued twice.
+func UniqueKey(key string) EnqueueOption {
+	return func(opts *EnqueueOptions) {
+		opts.UniqueKey = key
+	}
+}
+
+// InTx enqueues job using given transaction, so the job is dropped on rollback.
+func InTx(tx storageSchema.QueryExecutor) EnqueueOption {
+	return func(opts *EnqueueOptions) {
+		opts.Tx = tx
+	}
+}
+
+// Queue enqueues background jobs.
+type Queue interface {
+	// Enqueue adds new job of given kind with JSON-encoded payload to the queue.
+	Enqueue(ctx context.Context, kind string, payload any, opts ...EnqueueOption) error
+	// DeleteJobs removes jobs of given kind with payload containing given JSON-encoded value
+	// using given transaction, e.g. to erase personal data. Finished jobs are removed as well,
+	// while jobs being run are not stopped.
+	DeleteJobs(ctx context.Context, tx storageSchema.QueryExecutor, kind string, payload any) error
+}
+
+// WithJobQueue defines service or state having job queue attached.
+type WithJobQueue interface {
+	JobQueue() Queue
+}
+
+// RequiresJobQueue defines service which can use job queue attached.
+type RequiresJobQueue interface {
+	UseJobQueue(queue Queue)
+}
+
+// JobQueueInject adds job queue to the service.
+func JobQueueInject(consumer, provider any) error {
+	reqQueue, provQueue, err := services.ValidateArgInterfaces[RequiresJobQueue, WithJobQueue](consumer, provider)
+	if err != nil {
+		return fmt.Errorf("error injecting job queue: %w", err)
+	}
+
+	reqQueue.UseJobQueue(provQueue.JobQueue())
+
+	return nil
+}
+
+type attemptKey struct{}
+
+// attempt - attempt of the job being processed.
+type attempt struct {
+	number, max int
+}
+
+// WithAttempt returns context of the job attempt with given number.
+func WithAttempt(ctx context.Context, number, maxAttempts int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt{number: number, max: maxAttempts})
+}
+
+// IsLastAttempt returns true if job won't be retried if the current attempt fails.
+//
+// Returns true if context is not a job context.
+func IsLastAttempt(ctx context.Context) bool {
+	current, ok := ctx.Value(attemptKey{}).(attempt)
+
+	return !ok || current.number >= current.max
+}
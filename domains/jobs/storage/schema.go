@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Statuses of `jobs`, matching `job_status` DB type.
+const (
+	StatusPending = "pending"
+	StatusRunning = "running"
+	StatusDone    = "done"
+	StatusFailed  = "failed"
+)
+
+// Job - entity of `jobs` table.
+type Job struct {
+	UUID        string         `db:"uuid"`
+	Kind        string         `db:"kind"`
+	Payload     []byte         `db:"payload"`
+	Status      string         `db:"status"`
+	Attempts    int            `db:"attempts"`
+	MaxAttempts int            `db:"max_attempts"`
+	RunAt       time.Time      `db:"run_at"`
+	LockedAt    sql.NullTime   `db:"locked_at"`
+	LastError   string         `db:"last_error"`
+	UniqueKey   sql.NullString `db:"unique_key"`
+	CreatedAt   time.Time      `db:"created_at"`
+	FinishedAt  sql.NullTime   `db:"finished_at"`
+}
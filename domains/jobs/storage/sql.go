@@ -0,0 +1,180 @@
+/*
+Package storage contains db-related operations with background jobs.
+*/
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	storageSchema "github.com/outcatcher/anwil/domains/storage/schema"
+)
+
+// jobStorage - storage of background jobs.
+type jobStorage struct {
+	db storageSchema.QueryExecutor
+}
+
+// New creates a new JobStorage instance.
+func New(db storageSchema.QueryExecutor) JobStorage {
+	return &jobStorage{db: db}
+}
+
+// InsertJob adds new pending job. Returns false if job with the same unique key already exists.
+func (j *jobStorage) InsertJob(ctx context.Context, job Job) (bool, error) {
+	var jobUUID string
+
+	// payload is passed as a string, as []byte is sent as bytea by the driver
+	err := j.db.GetContext(
+		ctx,
+		&jobUUID,
+		`INSERT INTO jobs (kind, payload, max_attempts, run_at, unique_key)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (unique_key) DO NOTHING
+		 RETURNING uuid;`,
+		job.Kind, string(job.Payload), job.MaxAttempts, job.RunAt, job.UniqueKey,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("error inserting job: %w", err)
+	}
+
+	return true, nil
+}
+
+// ClaimJobs marks up to limit due jobs as running and returns them.
+//
+// Jobs locked by other transactions are skipped, so the same job is never claimed twice.
+func (j *jobStorage) ClaimJobs(ctx context.Context, limit int) ([]Job, error) {
+	var jobs []Job
+
+	err := sqlx.SelectContext(
+		ctx,
+		j.db,
+		&jobs,
+		`UPDATE jobs
+		 SET status = $1, attempts = attempts + 1, locked_at = now()
+		 WHERE uuid IN (
+		     SELECT uuid FROM jobs
+		     WHERE status = $2 AND run_at <= now()
+		     ORDER BY run_at
+		     LIMIT $3
+		     FOR UPDATE SKIP LOCKED
+		 )
+		 RETURNING *;`,
+		StatusRunning, StatusPending, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error claiming jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// CompleteJob marks job as successfully done.
+func (j *jobStorage) CompleteJob(ctx context.Context, uuid string) error {
+	_, err := j.db.ExecContext(
+		ctx,
+		`UPDATE jobs SET status = $2, finished_at = now(), locked_at = NULL WHERE uuid = $1;`,
+		uuid, StatusDone,
+	)
+	if err != nil {
+		return fmt.Errorf("error completing job: %w", err)
+	}
+
+	return nil
+}
+
+// RetryJob returns job to the queue to be started not earlier than runAt.
+func (j *jobStorage) RetryJob(ctx context.Context, uuid string, runAt time.Time, reason string) error {
+	_, err := j.db.ExecContext(
+		ctx,
+		`UPDATE jobs SET status = $2, run_at = $3, last_error = $4, locked_at = NULL WHERE uuid = $1;`,
+		uuid, StatusPending, runAt, reason,
+	)
+	if err != nil {
+		return fmt.Errorf("error retrying job: %w", err)
+	}
+
+	return nil
+}
+
+// FailJob marks job as failed with the given reason.
+func (j *jobStorage) FailJob(ctx context.Context, uuid, reason string) error {
+	_, err := j.db.ExecContext(
+		ctx,
+		`UPDATE jobs SET status = $2, last_error = $3, finished_at = now(), locked_at = NULL WHERE uuid = $1;`,
+		uuid, StatusFailed, reason,
+	)
+	if err != nil {
+		return fmt.Errorf("error failing job: %w", err)
+	}
+
+	return nil
+}
+
+// ReleaseStaleJobs returns jobs locked before given time to the queue.
+//
+// Such jobs are left running by crashed or killed workers.
+func (j *jobStorage) ReleaseStaleJobs(ctx context.Context, lockedBefore time.Time) (int64, error) {
+	result, err := j.db.ExecContext(
+		ctx,
+		`UPDATE jobs SET status = $1, locked_at = NULL WHERE status = $2 AND locked_at < $3;`,
+		StatusPending, StatusRunning, lockedBefore,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("error releasing stale jobs: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error releasing stale jobs: %w", err)
+	}
+
+	return affected, nil
+}
+
+// DeleteFinishedJobs removes done and failed jobs finished before given time.
+func (j *jobStorage) DeleteFinishedJobs(ctx context.Context, finishedBefore time.Time) (int64, error) {
+	result, err := j.db.ExecContext(
+		ctx,
+		`DELETE FROM jobs WHERE status IN ($1, $2) AND finished_at < $3;`,
+		StatusDone, StatusFailed, finishedBefore,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("error deleting finished jobs: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error deleting finished jobs: %w", err)
+	}
+
+	return affected, nil
+}
+
+// DeleteJobs removes jobs of given kind with payload containing given JSON value in any status.
+func (j *jobStorage) DeleteJobs(ctx context.Context, kind string, payload []byte) (int64, error) {
+	result, err := j.db.ExecContext(
+		ctx,
+		`DELETE FROM jobs WHERE kind = $1 AND payload @> $2::jsonb;`,
+		kind, string(payload),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("error deleting jobs: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error deleting jobs: %w", err)
+	}
+
+	return affected, nil
+}
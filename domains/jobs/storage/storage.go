@@ -0,0 +1,18 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// JobStorage - storage of background jobs.
+type JobStorage interface {
+	InsertJob(ctx context.Context, job Job) (bool, error)
+	ClaimJobs(ctx context.Context, limit int) ([]Job, error)
+	CompleteJob(ctx context.Context, uuid string) error
+	RetryJob(ctx context.Context, uuid string, runAt time.Time, reason string) error
+	FailJob(ctx context.Context, uuid, reason string) error
+	ReleaseStaleJobs(ctx context.Context, lockedBefore time.Time) (int64, error)
+	DeleteFinishedJobs(ctx context.Context, finishedBefore time.Time) (int64, error)
+	DeleteJobs(ctx context.Context, kind string, payload []byte) (int64, error)
+}
@@ -0,0 +1,4 @@
+/*
+Package mail contains functions and entities of outgoing mail domain.
+*/
+package mail
@@ -0,0 +1,116 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/outcatcher/anwil/domains/mail/schema"
+)
+
+const (
+	messageExt = ".eml"
+
+	dirPermissions  = 0o750
+	filePermissions = 0o600
+)
+
+// fileMailer writes messages into the directory, one file per message.
+type fileMailer struct {
+	from string
+	dir  string
+}
+
+func newFileMailer(from, dir string) (*fileMailer, error) {
+	dir = filepath.Clean(dir)
+
+	if err := os.MkdirAll(dir, dirPermissions); err != nil {
+		return nil, fmt.Errorf("error creating mail directory: %w", err)
+	}
+
+	return &fileMailer{from: from, dir: dir}, nil
+}
+
+// Send writes message to a new file.
+//
+// File names are ordered by message creation time.
+func (m *fileMailer) Send(_ context.Context, msg schema.Message) error {
+	if err := validateMessage(msg); err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	data, err := formatMessage(m.from, msg, now)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d-%s%s", now.UnixNano(), uuid.NewString(), messageExt)
+
+	if err := os.WriteFile(filepath.Join(m.dir, name), data, filePermissions); err != nil {
+		return fmt.Errorf("error writing message file: %w", err)
+	}
+
+	return nil
+}
+
+// ReadMessages reads messages sent to the recipient written by `file` mailer to the given directory.
+//
+// Messages are returned in the order they were sent. All messages are returned if recipient is empty.
+func ReadMessages(dir, recipient string) ([]schema.Message, error) {
+	entries, err := os.ReadDir(filepath.Clean(dir))
+	if err != nil {
+		return nil, fmt.Errorf("error reading mail directory: %w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), messageExt) {
+			continue
+		}
+
+		names = append(names, entry.Name())
+	}
+
+	sort.Strings(names)
+
+	result := make([]schema.Message, 0, len(names))
+
+	for _, name := range names {
+		msg, err := readMessageFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+
+		if recipient != "" && !strings.EqualFold(msg.To, recipient) {
+			continue
+		}
+
+		result = append(result, *msg)
+	}
+
+	return result, nil
+}
+
+func readMessageFile(path string) (*schema.Message, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("error opening message file: %w", err)
+	}
+
+	defer func() {
+		if err := file.Close(); err != nil {
+			log.Printf("error closing message file: %s", err)
+		}
+	}()
+
+	return parseMessage(file)
+}
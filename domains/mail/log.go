@@ -0,0 +1,24 @@
+package mail
+
+import (
+	"context"
+	"log"
+
+	"github.com/outcatcher/anwil/domains/mail/schema"
+)
+
+// logMailer writes messages to the log instead of sending them.
+type logMailer struct {
+	log *log.Logger
+}
+
+// Send writes message to the log.
+func (m *logMailer) Send(_ context.Context, msg schema.Message) error {
+	if err := validateMessage(msg); err != nil {
+		return err
+	}
+
+	m.log.Printf("mail to %s\nSubject: %s\n\n%s", msg.To, msg.Subject, msg.Body)
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
+	"github.com/outcatcher/anwil/domains/mail/schema"
+)
+
+// Supported mail drivers.
+const (
+	DriverLog  = "log"
+	DriverFile = "file"
+	DriverSMTP = "smtp"
+)
+
+var errUnknownDriver = errors.New("unknown mail driver")
+
+// New creates mailer using configured driver.
+func New(cfg configSchema.MailConfiguration, logger *log.Logger) (schema.Mailer, error) {
+	switch cfg.Driver {
+	case "", DriverLog:
+		return &logMailer{log: logger}, nil
+	case DriverFile:
+		mailer, err := newFileMailer(cfg.From, cfg.Directory)
+		if err != nil {
+			return nil, fmt.Errorf("error creating file mailer: %w", err)
+		}
+
+		return mailer, nil
+	case DriverSMTP:
+		return newSMTPMailer(cfg.From, cfg.SMTP), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownDriver, cfg.Driver)
+	}
+}
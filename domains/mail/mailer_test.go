@@ -0,0 +1,103 @@
+package mail
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
+	th "github.com/outcatcher/anwil/domains/core/testhelpers"
+	"github.com/outcatcher/anwil/domains/core/validation"
+	"github.com/outcatcher/anwil/domains/mail/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		driver   string
+		expected schema.Mailer
+	}{
+		{"", new(logMailer)},
+		{DriverLog, new(logMailer)},
+		{DriverFile, new(fileMailer)},
+		{DriverSMTP, new(smtpMailer)},
+	}
+
+	for _, data := range cases {
+		data := data
+
+		t.Run(data.driver, func(t *testing.T) {
+			t.Parallel()
+
+			mailer, err := New(configSchema.MailConfiguration{
+				Driver:    data.driver,
+				Directory: t.TempDir(),
+			}, log.Default())
+			require.NoError(t, err)
+			require.IsType(t, data.expected, mailer)
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := New(configSchema.MailConfiguration{Driver: "pigeon"}, log.Default())
+		require.ErrorIs(t, err, errUnknownDriver)
+	})
+}
+
+func TestFileMailer(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	mailer, err := newFileMailer("anwil@example.com", dir)
+	require.NoError(t, err)
+
+	recipient := th.RandomString("user-", 5) + "@example.com"
+
+	expected := []schema.Message{
+		{To: recipient, Subject: "Первое письмо", Body: "Hello,\nthis is a long line " + th.RandomString("", 100)},
+		{To: recipient, Subject: "Second", Body: "Привет!"},
+	}
+
+	for _, msg := range expected {
+		require.NoError(t, mailer.Send(ctx, msg))
+	}
+
+	require.NoError(t, mailer.Send(ctx, schema.Message{To: "other@example.com", Subject: "Other"}))
+
+	actual, err := ReadMessages(dir, recipient)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+
+	all, err := ReadMessages(dir, "")
+	require.NoError(t, err)
+	require.Len(t, all, len(expected)+1)
+}
+
+func TestSend_invalid(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]schema.Message{
+		"missing recipient": {Subject: "subject"},
+		"invalid recipient": {To: "not an address", Subject: "subject"},
+		"header injection":  {To: "user@example.com", Subject: "subject\r\nBcc: victim@example.com"},
+	}
+
+	for name, msg := range cases {
+		msg := msg
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			mailer := &logMailer{log: log.Default()}
+
+			err := mailer.Send(context.Background(), msg)
+			require.ErrorIs(t, err, validation.ErrValidationFailed)
+		})
+	}
+}
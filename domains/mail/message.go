@@ -0,0 +1,90 @@
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/outcatcher/anwil/domains/core/validation"
+	"github.com/outcatcher/anwil/domains/mail/schema"
+)
+
+const charset = "utf-8"
+
+var errInvalidHeader = errors.New("header value contains line breaks")
+
+// validateMessage checks message headers to be safe for sending.
+func validateMessage(msg schema.Message) error {
+	if _, err := mail.ParseAddress(msg.To); err != nil {
+		return fmt.Errorf("%w: invalid recipient address %q: %w", validation.ErrValidationFailed, msg.To, err)
+	}
+
+	if strings.ContainsAny(msg.Subject, "\r\n") {
+		return fmt.Errorf("%w: invalid subject: %w", validation.ErrValidationFailed, errInvalidHeader)
+	}
+
+	return nil
+}
+
+// formatMessage formats message as RFC 5322 message with quoted-printable encoded body.
+func formatMessage(from string, msg schema.Message, date time.Time) ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	headers := []struct{ key, value string }{
+		{"From", from},
+		{"To", msg.To},
+		{"Subject", mime.QEncoding.Encode(charset, msg.Subject)},
+		{"Date", date.Format(time.RFC1123Z)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", fmt.Sprintf("text/plain; charset=%s", charset)},
+		{"Content-Transfer-Encoding", "quoted-printable"},
+	}
+
+	for _, header := range headers {
+		_, _ = fmt.Fprintf(buf, "%s: %s\r\n", header.key, header.value)
+	}
+
+	buf.WriteString("\r\n")
+
+	bodyWriter := quotedprintable.NewWriter(buf)
+
+	if _, err := io.WriteString(bodyWriter, msg.Body); err != nil {
+		return nil, fmt.Errorf("error encoding message body: %w", err)
+	}
+
+	if err := bodyWriter.Close(); err != nil {
+		return nil, fmt.Errorf("error encoding message body: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// parseMessage parses message formatted with formatMessage.
+func parseMessage(src io.Reader) (*schema.Message, error) {
+	parsed, err := mail.ReadMessage(src)
+	if err != nil {
+		return nil, fmt.Errorf("error reading message: %w", err)
+	}
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding message subject: %w", err)
+	}
+
+	body, err := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding message body: %w", err)
+	}
+
+	return &schema.Message{
+		To:      parsed.Header.Get("To"),
+		Subject: subject,
+		Body:    strings.ReplaceAll(string(body), "\r\n", "\n"), // line breaks are CRLF-encoded in the message
+	}, nil
+}
@@ -0,0 +1,45 @@
+/*
+Package schema contains mail-related DTOs
+*/
+package schema
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/outcatcher/anwil/domains/core/services"
+)
+
+// Message - outgoing plain text mail message.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends mail messages.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// WithMailer defines service or state having mailer attached.
+type WithMailer interface {
+	Mailer() Mailer
+}
+
+// RequiresMailer defines service which can use mailer attached.
+type RequiresMailer interface {
+	UseMailer(mailer Mailer)
+}
+
+// MailerInject adds mailer to the service.
+func MailerInject(consumer, provider any) error {
+	reqMailer, provMailer, err := services.ValidateArgInterfaces[RequiresMailer, WithMailer](consumer, provider)
+	if err != nil {
+		return fmt.Errorf("error injecting mailer: %w", err)
+	}
+
+	reqMailer.UseMailer(provMailer.Mailer())
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
+	"github.com/outcatcher/anwil/domains/mail/schema"
+)
+
+// smtpMailer sends messages using SMTP server.
+type smtpMailer struct {
+	from string
+	addr string
+	auth smtp.Auth
+}
+
+func newSMTPMailer(from string, cfg configSchema.SMTPConfiguration) *smtpMailer {
+	mailer := &smtpMailer{
+		from: from,
+		addr: net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+	}
+
+	if cfg.Username != "" {
+		mailer.auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	return mailer
+}
+
+// Send sends message via SMTP.
+//
+// Note that context cancellation is not supported by net/smtp.
+func (m *smtpMailer) Send(_ context.Context, msg schema.Message) error {
+	if err := validateMessage(msg); err != nil {
+		return err
+	}
+
+	data, err := formatMessage(m.from, msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if err := smtp.SendMail(m.addr, m.auth, m.from, []string{msg.To}, data); err != nil {
+		return fmt.Errorf("error sending mail via SMTP: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,7 @@
+/*
+Package notifications contains functions and entities of user notifications domain.
+
+Other services publish notifications using schema.Notifier. Notifications are stored for in-app
+notification center and delivered to other channels according to user preferences.
+*/
+package notifications
@@ -0,0 +1,175 @@
+/*
+Package handlers contains API handlers for notification center endpoints.
+*/
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/outcatcher/anwil/domains/core/negotiation"
+	"github.com/outcatcher/anwil/domains/core/services"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/outcatcher/anwil/domains/core/validation"
+	"github.com/outcatcher/anwil/domains/notifications/service/schema"
+	"github.com/outcatcher/anwil/domains/users/auth"
+)
+
+type preferenceRequest struct {
+	Channels []string `json:"channels" form:"channels" validate:"dive,required"`
+}
+
+// AddNotificationHandlers - adds notification-related endpoints.
+func AddNotificationHandlers(state svcSchema.ProvidingServices) svcSchema.AddHandlersFunc {
+	return func(_, secGroup *echo.Group) error {
+		notificationService, err := services.GetServiceFromProvider[schema.NotificationService](
+			state, schema.ServiceID,
+		)
+		if err != nil {
+			return fmt.Errorf("error adding notification handlers: %w", err)
+		}
+
+		secGroup.GET("/me/notifications", handleListNotifications(notificationService))
+		// marking read is requested without body
+		secGroup.POST("/me/notifications/read", handleMarkAllRead(notificationService), negotiation.Consumes())
+		secGroup.POST("/me/notifications/:uuid/read", handleMarkRead(notificationService), negotiation.Consumes())
+
+		secGroup.GET("/me/notification-preferences", handleGetPreferences(notificationService))
+		secGroup.PUT(
+			"/me/notification-preferences/:type",
+			handleSetPreference(notificationService),
+			negotiation.Consumes(negotiation.DataTypes...),
+		)
+
+		return nil
+	}
+}
+
+// listFilter reads notification list parameters from query.
+func listFilter(c echo.Context) (schema.ListFilter, error) {
+	filter := schema.ListFilter{UnreadOnly: false, Limit: 0}
+
+	if value := c.QueryParam("unread"); value != "" {
+		unread, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, fmt.Errorf("%w: query parameter 'unread' should be boolean", validation.ErrValidationFailed)
+		}
+
+		filter.UnreadOnly = unread
+	}
+
+	if value := c.QueryParam("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			return filter, fmt.Errorf("%w: query parameter 'limit' should be positive integer",
+				validation.ErrValidationFailed)
+		}
+
+		filter.Limit = limit
+	}
+
+	return filter, nil
+}
+
+func handleListNotifications(ntf schema.NotificationService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error listing notifications: %w", err)
+		}
+
+		filter, err := listFilter(c)
+		if err != nil {
+			return fmt.Errorf("error listing notifications: %w", err)
+		}
+
+		notifications, err := ntf.ListNotifications(c.Request().Context(), claims.UserUUID, filter)
+		if err != nil {
+			return fmt.Errorf("error listing notifications: %w", err)
+		}
+
+		return negotiation.Respond(c, http.StatusOK, notifications)
+	}
+}
+
+func handleMarkRead(ntf schema.NotificationService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error marking notification read: %w", err)
+		}
+
+		notificationUUID := c.Param("uuid")
+
+		if _, err := uuid.Parse(notificationUUID); err != nil {
+			return fmt.Errorf("%w: invalid notification UUID %q", validation.ErrValidationFailed, notificationUUID)
+		}
+
+		if err := ntf.MarkRead(c.Request().Context(), claims.UserUUID, notificationUUID); err != nil {
+			return fmt.Errorf("error marking notification read: %w", err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func handleMarkAllRead(ntf schema.NotificationService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error marking notifications read: %w", err)
+		}
+
+		if err := ntf.MarkAllRead(c.Request().Context(), claims.UserUUID); err != nil {
+			return fmt.Errorf("error marking notifications read: %w", err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func handleGetPreferences(ntf schema.NotificationService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error getting notification preferences: %w", err)
+		}
+
+		preferences, err := ntf.Preferences(c.Request().Context(), claims.UserUUID)
+		if err != nil {
+			return fmt.Errorf("error getting notification preferences: %w", err)
+		}
+
+		return negotiation.Respond(c, http.StatusOK, preferences)
+	}
+}
+
+func handleSetPreference(ntf schema.NotificationService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error setting notification preference: %w", err)
+		}
+
+		req := new(preferenceRequest)
+
+		if err := c.Bind(req); err != nil {
+			return fmt.Errorf("error binding request: %w", err)
+		}
+
+		if err := validation.ValidateJSONCtx(c.Request().Context(), req); err != nil {
+			return fmt.Errorf("error validating request: %w", err)
+		}
+
+		preference := schema.Preference{Type: c.Param("type"), Channels: req.Channels}
+
+		if err := ntf.SetPreference(c.Request().Context(), claims.UserUUID, preference); err != nil {
+			return fmt.Errorf("error setting notification preference: %w", err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Package schema contains notification DTOs shared with publishing services
+*/
+package schema
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/outcatcher/anwil/domains/core/services"
+	eventsSchema "github.com/outcatcher/anwil/domains/events/schema"
+)
+
+// Notification types. Each publishing service adds its own types here.
+const (
+	TypePasswordChanged = "account.password_changed"
+	TypeExportReady     = "export.ready"
+	TypeSantaInvite     = "santa.invite"
+	TypeSantaDrawn      = "santa.drawn"
+)
+
+// Delivery channels.
+const (
+	ChannelInApp   = "in_app"
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+)
+
+// Types - all known notification types with channels enabled by default.
+var Types = map[string][]string{ //nolint:gochecknoglobals
+	TypePasswordChanged: {ChannelInApp, ChannelEmail},
+	TypeExportReady:     {ChannelInApp, ChannelEmail},
+	TypeSantaInvite:     {ChannelInApp, ChannelEmail},
+	TypeSantaDrawn:      {ChannelInApp, ChannelEmail},
+}
+
+// EventType returns type of the event publishing notification.
+func (Notification) EventType() string {
+	return "notifications.notification"
+}
+
+// GlobalTypes - notification types sent to global webhooks of admins.
+//
+// Only types not exposing personal data are allowed, and only redacted GlobalEvent is sent.
+var GlobalTypes = map[string]bool{ //nolint:gochecknoglobals
+	TypePasswordChanged: true,
+	TypeExportReady:     true,
+}
+
+// Channels - all known delivery channels.
+var Channels = []string{ChannelInApp, ChannelEmail, ChannelWebhook} //nolint:gochecknoglobals
+
+// Notification - notification published for a single user. Notifications are published to the event bus.
+type Notification struct {
+	// UserUUID is UUID of notified user
+	UserUUID string `json:"user_uuid"`
+	// Type is one of known notification types, e.g. TypeExportReady
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	// Link is an optional URL of related page or resource
+	Link string `json:"link,omitempty"`
+	// Data holds optional type-specific JSON-serializable attributes
+	Data map[string]any `json:"data,omitempty"`
+}
+
+// GlobalEvent - redacted notification sent to global webhooks of admins.
+type GlobalEvent struct {
+	// UserUUID is UUID of notified user
+	UserUUID string `json:"user_uuid"`
+	// Type is one of GlobalTypes
+	Type string `json:"type"`
+}
+
+// Notifier publishes notifications.
+type Notifier interface {
+	// Notify publishes notification. Delivery to external channels is done in background.
+	Notify(ctx context.Context, notification Notification) error
+}
+
+// RequiresNotifier defines service which can use notifier attached.
+type RequiresNotifier interface {
+	UseNotifier(notifier Notifier)
+}
+
+// NotifierInject adds notifier publishing notifications to the event bus to the service.
+//
+// Notifications are sent by notifications service subscribed to them.
+func NotifierInject(consumer, provider any) error {
+	reqNotifier, provBus, err := services.ValidateArgInterfaces[
+		RequiresNotifier, eventsSchema.WithEventBus,
+	](consumer, provider)
+	if err != nil {
+		return fmt.Errorf("error injecting notifier: %w", err)
+	}
+
+	reqNotifier.UseNotifier(busNotifier{bus: provBus.EventBus()})
+
+	return nil
+}
+
+// busNotifier publishes notifications to the event bus.
+type busNotifier struct {
+	bus eventsSchema.Publisher
+}
+
+// Notify publishes notification to be sent by notifications service.
+func (n busNotifier) Notify(ctx context.Context, notification Notification) error {
+	if err := n.bus.Publish(ctx, notification); err != nil {
+		return fmt.Errorf("error publishing notification: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	"github.com/outcatcher/anwil/domains/core/validation"
+	notificationsSchema "github.com/outcatcher/anwil/domains/notifications/schema"
+	"github.com/outcatcher/anwil/domains/notifications/service/schema"
+	notificationStorage "github.com/outcatcher/anwil/domains/notifications/storage"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// ListNotifications returns latest notifications of the user, newest first.
+func (s *service) ListNotifications(
+	ctx context.Context, userUUID string, filter schema.ListFilter,
+) (*schema.NotificationList, error) {
+	limit := filter.Limit
+
+	switch {
+	case limit <= 0:
+		limit = defaultLimit
+	case limit > maxLimit:
+		limit = maxLimit
+	}
+
+	stored, err := s.storage.ListNotifications(ctx, userUUID, filter.UnreadOnly, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error listing notifications: %w", err)
+	}
+
+	unread, err := s.storage.CountUnread(ctx, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing notifications: %w", err)
+	}
+
+	result := &schema.NotificationList{
+		Notifications: make([]schema.Notification, 0, len(stored)),
+		Unread:        unread,
+	}
+
+	for _, notification := range stored {
+		converted, err := toNotification(notification)
+		if err != nil {
+			return nil, fmt.Errorf("error listing notifications: %w", err)
+		}
+
+		result.Notifications = append(result.Notifications, *converted)
+	}
+
+	return result, nil
+}
+
+func toNotification(stored notificationStorage.Notification) (*schema.Notification, error) {
+	notification := &schema.Notification{
+		UUID:      stored.UUID,
+		Type:      stored.Type,
+		Title:     stored.Title,
+		Body:      stored.Body,
+		Link:      stored.Link,
+		Data:      nil,
+		CreatedAt: stored.CreatedAt,
+		ReadAt:    nil,
+	}
+
+	if stored.ReadAt.Valid {
+		readAt := stored.ReadAt.Time
+		notification.ReadAt = &readAt
+	}
+
+	if stored.Data != "" {
+		if err := json.Unmarshal([]byte(stored.Data), &notification.Data); err != nil {
+			return nil, fmt.Errorf("error decoding notification data: %w", err)
+		}
+	}
+
+	return notification, nil
+}
+
+// MarkRead marks single notification of the user as read.
+func (s *service) MarkRead(ctx context.Context, userUUID, notificationUUID string) error {
+	if err := s.storage.MarkRead(ctx, userUUID, notificationUUID); err != nil {
+		return fmt.Errorf("error marking notification read: %w", err)
+	}
+
+	return nil
+}
+
+// MarkAllRead marks all notifications of the user as read.
+func (s *service) MarkAllRead(ctx context.Context, userUUID string) error {
+	if err := s.storage.MarkAllRead(ctx, userUUID); err != nil {
+		return fmt.Errorf("error marking notifications read: %w", err)
+	}
+
+	return nil
+}
+
+// Preferences returns delivery channels of all notification types for the user,
+// using default channels for types without stored preference.
+func (s *service) Preferences(ctx context.Context, userUUID string) ([]schema.Preference, error) {
+	stored, err := s.storage.ListPreferences(ctx, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting notification preferences: %w", err)
+	}
+
+	channels := make(map[string][]string, len(notificationsSchema.Types))
+
+	for notificationType, defaults := range notificationsSchema.Types {
+		channels[notificationType] = defaults
+	}
+
+	for _, preference := range stored {
+		if _, ok := channels[preference.Type]; !ok {
+			continue // type is not published anymore
+		}
+
+		channels[preference.Type] = preference.Channels
+	}
+
+	preferences := make([]schema.Preference, 0, len(channels))
+
+	for notificationType, typeChannels := range channels {
+		preferences = append(preferences, schema.Preference{Type: notificationType, Channels: typeChannels})
+	}
+
+	sort.Slice(preferences, func(i, j int) bool {
+		return preferences[i].Type < preferences[j].Type
+	})
+
+	return preferences, nil
+}
+
+// channels returns delivery channels of the notification type for the user.
+func (s *service) channels(ctx context.Context, userUUID, notificationType string) ([]string, error) {
+	preferences, err := s.Preferences(ctx, userUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, preference := range preferences {
+		if preference.Type == notificationType {
+			return preference.Channels, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// SetPreference sets delivery channels of the notification type for the user.
+func (s *service) SetPreference(ctx context.Context, userUUID string, preference schema.Preference) error {
+	if _, ok := notificationsSchema.Types[preference.Type]; !ok {
+		return fmt.Errorf("%w: notification type %s", errbase.ErrNotFound, preference.Type)
+	}
+
+	channels := make([]string, 0, len(preference.Channels))
+	seen := make(map[string]bool, len(preference.Channels))
+
+	for _, channel := range preference.Channels {
+		if !isKnownChannel(channel) {
+			return fmt.Errorf("%w: unknown channel %s", validation.ErrValidationFailed, channel)
+		}
+
+		if seen[channel] {
+			continue
+		}
+
+		seen[channel] = true
+
+		channels = append(channels, channel)
+	}
+
+	err := s.storage.SetPreference(ctx, notificationStorage.Preference{
+		WisherUUID: userUUID,
+		Type:       preference.Type,
+		Channels:   channels,
+	})
+	if err != nil {
+		return fmt.Errorf("error setting notification preference: %w", err)
+	}
+
+	return nil
+}
+
+func isKnownChannel(channel string) bool {
+	for _, known := range notificationsSchema.Channels {
+		if channel == known {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/outcatcher/anwil/domains/notifications/service/schema"
+)
+
+// notificationsExport - exported notification data of the user.
+type notificationsExport struct {
+	Notifications []schema.Notification `json:"notifications"`
+	Preferences   []schema.Preference   `json:"preferences"`
+}
+
+// ExportUserData returns all notifications and notification preferences of the user for personal data export.
+//
+// Notifications are removed with the user, so there is no PrepareErasure.
+func (s *service) ExportUserData(ctx context.Context, userUUID string) (*svcSchema.UserDataExport, error) {
+	stored, err := s.storage.ListNotifications(ctx, userUUID, false, 0)
+	if err != nil {
+		return nil, fmt.Errorf("error exporting notifications: %w", err)
+	}
+
+	export := notificationsExport{Notifications: make([]schema.Notification, 0, len(stored)), Preferences: nil}
+
+	for _, notification := range stored {
+		converted, err := toNotification(notification)
+		if err != nil {
+			return nil, fmt.Errorf("error exporting notifications: %w", err)
+		}
+
+		export.Notifications = append(export.Notifications, *converted)
+	}
+
+	export.Preferences, err = s.Preferences(ctx, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("error exporting notifications: %w", err)
+	}
+
+	return &svcSchema.UserDataExport{Data: export, Files: nil}, nil
+}
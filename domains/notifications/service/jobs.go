@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/outcatcher/anwil/domains/core/services"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/outcatcher/anwil/domains/jobs"
+	jobsSchema "github.com/outcatcher/anwil/domains/jobs/schema"
+	mailSchema "github.com/outcatcher/anwil/domains/mail/schema"
+	notificationsSchema "github.com/outcatcher/anwil/domains/notifications/schema"
+	"github.com/outcatcher/anwil/domains/notifications/service/schema"
+)
+
+const deliverJobKind = "notifications.deliver"
+
+const emailBody = `Hello, %s!
+
+%s
+`
+
+// deliverPayload - payload of the job delivering notification to external channel.
+type deliverPayload struct {
+	Channel      string                           `json:"channel"`
+	Notification notificationsSchema.Notification `json:"notification"`
+}
+
+// addNotificationJobs registers notifications service jobs.
+func addNotificationJobs(state svcSchema.ProvidingServices) svcSchema.AddJobsFunc {
+	return func(registry svcSchema.JobRegistry) error {
+		svc, err := services.GetServiceFromProvider[*service](state, schema.ServiceID)
+		if err != nil {
+			return fmt.Errorf("error adding notification jobs: %w", err)
+		}
+
+		if err := registry.Handle(deliverJobKind, jobs.Typed(svc.deliver)); err != nil {
+			return fmt.Errorf("error adding notification jobs: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// deliver sends notification to the external channel.
+func (s *service) deliver(ctx context.Context, payload deliverPayload) error {
+	switch payload.Channel {
+	case notificationsSchema.ChannelEmail:
+		return s.deliverEmail(ctx, payload.Notification)
+	default:
+		return fmt.Errorf("%w: unknown channel %s", jobsSchema.ErrPermanent, payload.Channel)
+	}
+}
+
+// deliverEmail sends notification to verified email of the user. Users without verified email are skipped.
+func (s *service) deliverEmail(ctx context.Context, notification notificationsSchema.Notification) error {
+	user, err := s.users.GetUserByUUID(ctx, notification.UserUUID)
+	if err != nil {
+		return fmt.Errorf("error delivering notification email: %w", err)
+	}
+
+	if user.Email == "" || !user.EmailVerified {
+		return nil
+	}
+
+	body := notification.Body
+	if notification.Link != "" {
+		body += "\n\n" + notification.Link
+	}
+
+	err = s.mailer.Send(ctx, mailSchema.Message{
+		To:      user.Email,
+		Subject: "Anwil: " + notification.Title,
+		Body:    fmt.Sprintf(emailBody, user.FullName, body),
+	})
+	if err != nil {
+		return fmt.Errorf("error delivering notification email: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	notificationsSchema "github.com/outcatcher/anwil/domains/notifications/schema"
+	notificationStorage "github.com/outcatcher/anwil/domains/notifications/storage"
+)
+
+var errUnknownType = errors.New("unknown notification type")
+
+// Notify stores in-app notification, dispatches it to webhooks and enqueues its delivery
+// to other channels enabled by the user.
+//
+// Notifications of GlobalTypes are also sent redacted to global webhooks of admins regardless of user preferences.
+func (s *service) Notify(ctx context.Context, notification notificationsSchema.Notification) error {
+	if _, ok := notificationsSchema.Types[notification.Type]; !ok {
+		return fmt.Errorf("%w: %s", errUnknownType, notification.Type)
+	}
+
+	channels, err := s.channels(ctx, notification.UserUUID, notification.Type)
+	if err != nil {
+		return fmt.Errorf("error sending notification: %w", err)
+	}
+
+	for _, channel := range channels {
+		switch channel {
+		case notificationsSchema.ChannelInApp:
+			err = s.store(ctx, notification)
+		case notificationsSchema.ChannelWebhook:
+			// webhooks service queues delivery to each webhook itself
+			err = s.webhooks.Dispatch(ctx, notification.UserUUID, notification.Type, notification)
+		default:
+			err = s.queue.Enqueue(ctx, deliverJobKind, deliverPayload{Channel: channel, Notification: notification})
+		}
+
+		if err != nil {
+			return fmt.Errorf("error sending notification: %w", err)
+		}
+	}
+
+	if !notificationsSchema.GlobalTypes[notification.Type] {
+		return nil
+	}
+
+	event := notificationsSchema.GlobalEvent{UserUUID: notification.UserUUID, Type: notification.Type}
+
+	// notification is already delivered to the user at this point
+	if err := s.webhooks.DispatchGlobal(ctx, notification.Type, event); err != nil {
+		s.log.Printf("error sending notification %s to global webhooks: %s", notification.Type, err)
+	}
+
+	return nil
+}
+
+// eventNotification - type of real-time event sent for new in-app notifications.
+const eventNotification = "notification"
+
+// store adds notification to the notification center and sends it to connected clients of the user.
+func (s *service) store(ctx context.Context, notification notificationsSchema.Notification) error {
+	data := []byte("{}")
+
+	if len(notification.Data) > 0 {
+		encoded, err := json.Marshal(notification.Data)
+		if err != nil {
+			return fmt.Errorf("error encoding notification data: %w", err)
+		}
+
+		data = encoded
+	}
+
+	stored, err := s.storage.InsertNotification(ctx, notificationStorage.Notification{ //nolint:exhaustruct
+		WisherUUID: notification.UserUUID,
+		Type:       notification.Type,
+		Title:      notification.Title,
+		Body:       notification.Body,
+		Link:       notification.Link,
+		Data:       string(data),
+	})
+	if err != nil {
+		return fmt.Errorf("error storing notification: %w", err)
+	}
+
+	converted, err := toNotification(*stored)
+	if err != nil {
+		return fmt.Errorf("error storing notification: %w", err)
+	}
+
+	// notification is available in the notification center anyway
+	err = s.publisher.Publish(ctx, []string{notification.UserUUID}, eventNotification, converted)
+	if err != nil {
+		s.log.Printf("error streaming notification %s: %s", stored.UUID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+/*
+Package schema contains service definition for Notifications service
+*/
+package schema
+
+import (
+	"context"
+	"time"
+
+	"github.com/outcatcher/anwil/domains/core/services/schema"
+)
+
+// ServiceID - ID for notifications service.
+const ServiceID schema.ServiceID = "notifications"
+
+// NotificationService - service handling notification center of the user.
+type NotificationService interface {
+	// ListNotifications returns latest notifications of the user, newest first.
+	ListNotifications(ctx context.Context, userUUID string, filter ListFilter) (*NotificationList, error)
+	// MarkRead marks single notification of the user as read.
+	MarkRead(ctx context.Context, userUUID, notificationUUID string) error
+	// MarkAllRead marks all notifications of the user as read.
+	MarkAllRead(ctx context.Context, userUUID string) error
+
+	// Preferences returns delivery channels of all notification types for the user.
+	Preferences(ctx context.Context, userUUID string) ([]Preference, error)
+	// SetPreference sets delivery channels of the notification type for the user.
+	SetPreference(ctx context.Context, userUUID string, preference Preference) error
+}
+
+// ListFilter - notification list parameters.
+type ListFilter struct {
+	// UnreadOnly excludes read notifications
+	UnreadOnly bool
+	// Limit is a max number of returned notifications
+	Limit int
+}
+
+// Notification - notification stored in notification center.
+type Notification struct {
+	UUID      string         `json:"uuid"`
+	Type      string         `json:"type"`
+	Title     string         `json:"title"`
+	Body      string         `json:"body"`
+	Link      string         `json:"link,omitempty"`
+	Data      map[string]any `json:"data,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	ReadAt    *time.Time     `json:"read_at,omitempty"`
+}
+
+// NotificationList - page of notifications with total number of unread ones.
+type NotificationList struct {
+	Notifications []Notification `json:"notifications"`
+	Unread        int            `json:"unread"`
+}
+
+// Preference - delivery channels of the notification type.
+type Preference struct {
+	Type string `json:"type"`
+	// Channels is a list of channels notification is delivered to, empty list disables notifications of the type
+	Channels []string `json:"channels"`
+}
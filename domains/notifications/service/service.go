@@ -0,0 +1,122 @@
+/*
+Package service contains notifications service methods
+*/
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
+	logSchema "github.com/outcatcher/anwil/domains/core/logging/schema"
+	"github.com/outcatcher/anwil/domains/core/services"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/outcatcher/anwil/domains/events"
+	eventsSchema "github.com/outcatcher/anwil/domains/events/schema"
+	jobsSchema "github.com/outcatcher/anwil/domains/jobs/schema"
+	mailSchema "github.com/outcatcher/anwil/domains/mail/schema"
+	"github.com/outcatcher/anwil/domains/notifications/handlers"
+	"github.com/outcatcher/anwil/domains/notifications/service/schema"
+	notificationStorage "github.com/outcatcher/anwil/domains/notifications/storage"
+	storageSchema "github.com/outcatcher/anwil/domains/storage/schema"
+	streamSchema "github.com/outcatcher/anwil/domains/stream/schema"
+	usersSchema "github.com/outcatcher/anwil/domains/users/service/schema"
+	webhooksSchema "github.com/outcatcher/anwil/domains/webhooks/service/schema"
+)
+
+// service - notifications service.
+type service struct {
+	cfg     *configSchema.Configuration
+	storage notificationStorage.NotificationStorage
+
+	log    *log.Logger
+	queue  jobsSchema.Queue
+	mailer mailSchema.Mailer
+	// publisher sends stored notifications to connected clients
+	publisher streamSchema.Publisher
+	users     usersSchema.UserFinder
+	webhooks  webhooksSchema.Dispatcher
+	bus       eventsSchema.Bus
+}
+
+// UseConfig attaches configuration to the service.
+func (s *service) UseConfig(configuration *configSchema.Configuration) {
+	s.cfg = configuration
+}
+
+// UseStorage attaches given DB storage to the service.
+func (s *service) UseStorage(db storageSchema.QueryExecutor) {
+	s.storage = notificationStorage.New(db)
+}
+
+// UseLogger attaches logger to the service.
+func (s *service) UseLogger(logger *log.Logger) {
+	s.log = logger
+}
+
+// UseJobQueue attaches background job queue to the service.
+func (s *service) UseJobQueue(queue jobsSchema.Queue) {
+	s.queue = queue
+}
+
+// UseMailer attaches mailer to the service.
+func (s *service) UseMailer(mailer mailSchema.Mailer) {
+	s.mailer = mailer
+}
+
+// UseUserFinder attaches users lookup.
+func (s *service) UseUserFinder(finder usersSchema.UserFinder) {
+	s.users = finder
+}
+
+// UseWebhookDispatcher attaches dispatcher of webhook events.
+func (s *service) UseWebhookDispatcher(dispatcher webhooksSchema.Dispatcher) {
+	s.webhooks = dispatcher
+}
+
+// UseEventBus attaches domain event bus to the service.
+func (s *service) UseEventBus(bus eventsSchema.Bus) {
+	s.bus = bus
+}
+
+// UsePublisher attaches publisher of real-time events.
+func (s *service) UsePublisher(publisher streamSchema.Publisher) {
+	s.publisher = publisher
+}
+
+func notificationServiceInit(_ context.Context, state any) (any, error) {
+	svc := new(service)
+
+	err := services.InjectServiceWith(
+		svc, state,
+		storageSchema.StorageInject,
+		logSchema.LoggerInject,
+		configSchema.ConfigInject,
+		jobsSchema.JobQueueInject,
+		mailSchema.MailerInject,
+		streamSchema.PublisherInject,
+		usersSchema.UserFinderInject,
+		webhooksSchema.WebhookDispatcherInject,
+		eventsSchema.EventBusInject,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing notifications service: %w", err)
+	}
+
+	// other services publish notifications instead of calling the service directly
+	events.Subscribe(svc.bus, eventsSchema.Sync, svc.Notify)
+
+	return svc, nil
+}
+
+// NewNotificationService returns new notifications service definition.
+func NewNotificationService() svcSchema.ServiceDefinition {
+	return svcSchema.ServiceDefinition{
+		ID:               schema.ServiceID,
+		Init:             notificationServiceInit,
+		DependsOn:        []svcSchema.ServiceID{usersSchema.ServiceID},
+		InitHandlersFunc: handlers.AddNotificationHandlers,
+		InitJobsFunc:     addNotificationJobs,
+	}
+}
@@ -0,0 +1,407 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	th "github.com/outcatcher/anwil/domains/core/testhelpers"
+	"github.com/outcatcher/anwil/domains/core/validation"
+	jobsSchema "github.com/outcatcher/anwil/domains/jobs/schema"
+	mailSchema "github.com/outcatcher/anwil/domains/mail/schema"
+	notificationsSchema "github.com/outcatcher/anwil/domains/notifications/schema"
+	"github.com/outcatcher/anwil/domains/notifications/service/schema"
+	"github.com/outcatcher/anwil/domains/notifications/storage"
+	usersSchema "github.com/outcatcher/anwil/domains/users/service/schema"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockStorage struct {
+	mock.Mock
+}
+
+func (m *mockStorage) InsertNotification(
+	ctx context.Context, notification storage.Notification,
+) (*storage.Notification, error) {
+	args := m.Called(ctx, notification)
+
+	return args.Get(0).(*storage.Notification), args.Error(1) //nolint:forcetypeassert
+}
+
+func (m *mockStorage) ListNotifications(
+	ctx context.Context, wisherUUID string, unreadOnly bool, limit int,
+) ([]storage.Notification, error) {
+	args := m.Called(ctx, wisherUUID, unreadOnly, limit)
+
+	return args.Get(0).([]storage.Notification), args.Error(1) //nolint:forcetypeassert
+}
+
+func (m *mockStorage) CountUnread(ctx context.Context, wisherUUID string) (int, error) {
+	args := m.Called(ctx, wisherUUID)
+
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockStorage) MarkRead(ctx context.Context, wisherUUID, uuid string) error {
+	return m.Called(ctx, wisherUUID, uuid).Error(0)
+}
+
+func (m *mockStorage) MarkAllRead(ctx context.Context, wisherUUID string) error {
+	return m.Called(ctx, wisherUUID).Error(0)
+}
+
+func (m *mockStorage) ListPreferences(ctx context.Context, wisherUUID string) ([]storage.Preference, error) {
+	args := m.Called(ctx, wisherUUID)
+
+	return args.Get(0).([]storage.Preference), args.Error(1) //nolint:forcetypeassert
+}
+
+func (m *mockStorage) SetPreference(ctx context.Context, preference storage.Preference) error {
+	return m.Called(ctx, preference).Error(0)
+}
+
+type mockMailer struct {
+	mock.Mock
+}
+
+func (m *mockMailer) Send(ctx context.Context, msg mailSchema.Message) error {
+	return m.Called(ctx, msg).Error(0)
+}
+
+type mockPublisher struct {
+	mock.Mock
+}
+
+func (m *mockPublisher) Publish(ctx context.Context, userUUIDs []string, eventType string, data any) error {
+	return m.Called(ctx, userUUIDs, eventType, data).Error(0)
+}
+
+type mockWebhooks struct {
+	mock.Mock
+}
+
+func (m *mockWebhooks) Dispatch(ctx context.Context, userUUID, eventType string, data any) error {
+	return m.Called(ctx, userUUID, eventType, data).Error(0)
+}
+
+func (m *mockWebhooks) DispatchGlobal(ctx context.Context, eventType string, data any) error {
+	return m.Called(ctx, eventType, data).Error(0)
+}
+
+func newTestService(store storage.NotificationStorage) *service {
+	webhooks := new(mockWebhooks)
+	webhooks.On("DispatchGlobal", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	return &service{
+		storage:  store,
+		log:      log.Default(),
+		webhooks: webhooks,
+	}
+}
+
+func testNotification() notificationsSchema.Notification {
+	return notificationsSchema.Notification{
+		UserUUID: th.RandomString("user-", 10),
+		Type:     notificationsSchema.TypeExportReady,
+		Title:    "Export ready",
+		Body:     th.RandomString("body ", 20),
+		Link:     "https://anwil.example.com/api/v1/me/exports/1",
+		Data:     map[string]any{"export_uuid": "1"},
+	}
+}
+
+func TestNotify(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("default channels", func(t *testing.T) {
+		t.Parallel()
+
+		notification := testNotification()
+
+		store := new(mockStorage)
+		store.On("ListPreferences", ctx, notification.UserUUID).Return([]storage.Preference{}, nil)
+		store.
+			On("InsertNotification", ctx, mock.MatchedBy(func(n storage.Notification) bool {
+				return n.WisherUUID == notification.UserUUID && n.Data == `{"export_uuid":"1"}`
+			})).
+			Return(&storage.Notification{UUID: "1", WisherUUID: notification.UserUUID, Data: `{}`}, nil)
+
+		publisher := new(mockPublisher)
+		publisher.
+			On("Publish", ctx, []string{notification.UserUUID}, eventNotification, mock.Anything).
+			Return(nil)
+
+		queue := new(th.MockQueue)
+		queue.
+			On("Enqueue", ctx, deliverJobKind, deliverPayload{
+				Channel:      notificationsSchema.ChannelEmail,
+				Notification: notification,
+			}).
+			Return(nil)
+
+		svc := newTestService(store)
+		svc.queue = queue
+		svc.publisher = publisher
+
+		require.NoError(t, svc.Notify(ctx, notification))
+
+		store.AssertNumberOfCalls(t, "InsertNotification", 1)
+		publisher.AssertNumberOfCalls(t, "Publish", 1)
+		queue.AssertNumberOfCalls(t, "Enqueue", 1)
+
+		webhooks := svc.webhooks.(*mockWebhooks) //nolint:forcetypeassert
+		webhooks.AssertCalled(t, "DispatchGlobal", ctx, notification.Type, notificationsSchema.GlobalEvent{
+			UserUUID: notification.UserUUID,
+			Type:     notification.Type,
+		})
+	})
+
+	t.Run("not global", func(t *testing.T) {
+		t.Parallel()
+
+		notification := testNotification()
+		notification.Type = notificationsSchema.TypeSantaInvite
+
+		store := new(mockStorage)
+		store.
+			On("ListPreferences", ctx, notification.UserUUID).
+			Return([]storage.Preference{{WisherUUID: notification.UserUUID, Type: notification.Type}}, nil)
+
+		svc := newTestService(store)
+
+		require.NoError(t, svc.Notify(ctx, notification))
+
+		webhooks := svc.webhooks.(*mockWebhooks) //nolint:forcetypeassert
+		webhooks.AssertNotCalled(t, "DispatchGlobal", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("webhook", func(t *testing.T) {
+		t.Parallel()
+
+		notification := testNotification()
+
+		store := new(mockStorage)
+		store.
+			On("ListPreferences", ctx, notification.UserUUID).
+			Return([]storage.Preference{{
+				WisherUUID: notification.UserUUID,
+				Type:       notification.Type,
+				Channels:   pq.StringArray{notificationsSchema.ChannelWebhook},
+			}}, nil)
+
+		queue := new(th.MockQueue)
+
+		svc := newTestService(store)
+		svc.queue = queue
+
+		webhooks := svc.webhooks.(*mockWebhooks) //nolint:forcetypeassert
+		webhooks.On("Dispatch", ctx, notification.UserUUID, notification.Type, notification).Return(nil)
+
+		require.NoError(t, svc.Notify(ctx, notification))
+
+		// webhook deliveries are queued by webhooks service only
+		webhooks.AssertNumberOfCalls(t, "Dispatch", 1)
+		queue.AssertNotCalled(t, "Enqueue")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		notification := testNotification()
+
+		store := new(mockStorage)
+		store.
+			On("ListPreferences", ctx, notification.UserUUID).
+			Return([]storage.Preference{{WisherUUID: notification.UserUUID, Type: notification.Type}}, nil)
+
+		queue := new(th.MockQueue)
+
+		svc := newTestService(store)
+		svc.queue = queue
+
+		require.NoError(t, svc.Notify(ctx, notification))
+
+		store.AssertNotCalled(t, "InsertNotification")
+		queue.AssertNotCalled(t, "Enqueue")
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		t.Parallel()
+
+		notification := testNotification()
+		notification.Type = "unknown"
+
+		store := new(mockStorage)
+
+		require.ErrorIs(t, newTestService(store).Notify(ctx, notification), errUnknownType)
+
+		store.AssertNotCalled(t, "ListPreferences")
+	})
+}
+
+func TestPreferences(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	userUUID := th.RandomString("user-", 10)
+
+	store := new(mockStorage)
+	store.
+		On("ListPreferences", ctx, userUUID).
+		Return([]storage.Preference{
+			{WisherUUID: userUUID, Type: notificationsSchema.TypeExportReady, Channels: pq.StringArray{"webhook"}},
+			{WisherUUID: userUUID, Type: "removed.type", Channels: pq.StringArray{"email"}},
+		}, nil)
+
+	preferences, err := newTestService(store).Preferences(ctx, userUUID)
+	require.NoError(t, err)
+
+	require.Len(t, preferences, len(notificationsSchema.Types))
+
+	for _, preference := range preferences {
+		expected := notificationsSchema.Types[preference.Type]
+		if preference.Type == notificationsSchema.TypeExportReady {
+			expected = []string{notificationsSchema.ChannelWebhook}
+		}
+
+		require.EqualValues(t, expected, preference.Channels, preference.Type)
+	}
+}
+
+func TestSetPreference(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	userUUID := th.RandomString("user-", 10)
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		store := new(mockStorage)
+		store.
+			On("SetPreference", ctx, storage.Preference{
+				WisherUUID: userUUID,
+				Type:       notificationsSchema.TypePasswordChanged,
+				Channels:   pq.StringArray{"email", "in_app"},
+			}).
+			Return(nil)
+
+		err := newTestService(store).SetPreference(ctx, userUUID, schema.Preference{
+			Type:     notificationsSchema.TypePasswordChanged,
+			Channels: []string{"email", "in_app", "email"},
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		t.Parallel()
+
+		err := newTestService(new(mockStorage)).SetPreference(ctx, userUUID, schema.Preference{
+			Type:     "unknown",
+			Channels: nil,
+		})
+		require.ErrorIs(t, err, errbase.ErrNotFound)
+	})
+
+	t.Run("unknown channel", func(t *testing.T) {
+		t.Parallel()
+
+		err := newTestService(new(mockStorage)).SetPreference(ctx, userUUID, schema.Preference{
+			Type:     notificationsSchema.TypePasswordChanged,
+			Channels: []string{"pigeon"},
+		})
+		require.ErrorIs(t, err, validation.ErrValidationFailed)
+	})
+}
+
+func TestListNotifications(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	userUUID := th.RandomString("user-", 10)
+	readAt := time.Now()
+
+	stored := []storage.Notification{
+		{UUID: "1", WisherUUID: userUUID, Type: notificationsSchema.TypeExportReady, Data: `{"export_uuid":"1"}`},
+		{UUID: "2", WisherUUID: userUUID, Type: notificationsSchema.TypePasswordChanged, Data: `{}`,
+			ReadAt: sql.NullTime{Time: readAt, Valid: true}},
+	}
+
+	store := new(mockStorage)
+	store.On("ListNotifications", ctx, userUUID, false, maxLimit).Return(stored, nil)
+	store.On("CountUnread", ctx, userUUID).Return(1, nil)
+
+	list, err := newTestService(store).ListNotifications(ctx, userUUID, schema.ListFilter{Limit: 1000})
+	require.NoError(t, err)
+
+	require.Equal(t, 1, list.Unread)
+	require.Len(t, list.Notifications, 2)
+	require.Equal(t, "1", list.Notifications[0].Data["export_uuid"])
+	require.Nil(t, list.Notifications[0].ReadAt)
+	require.Empty(t, list.Notifications[1].Data)
+	require.NotNil(t, list.Notifications[1].ReadAt)
+}
+
+func TestDeliverEmail(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("verified", func(t *testing.T) {
+		t.Parallel()
+
+		notification := testNotification()
+
+		mailer := new(mockMailer)
+		mailer.
+			On("Send", ctx, mock.MatchedBy(func(msg mailSchema.Message) bool {
+				return msg.To == "john@example.com" && msg.Subject == "Anwil: "+notification.Title
+			})).
+			Return(nil)
+
+		svc := newTestService(new(mockStorage))
+		svc.mailer = mailer
+		svc.users = &th.FakeUsers{User: usersSchema.User{Email: "john@example.com", EmailVerified: true}}
+
+		require.NoError(t, svc.deliver(ctx, deliverPayload{
+			Channel:      notificationsSchema.ChannelEmail,
+			Notification: notification,
+		}))
+
+		mailer.AssertNumberOfCalls(t, "Send", 1)
+	})
+
+	t.Run("not verified", func(t *testing.T) {
+		t.Parallel()
+
+		mailer := new(mockMailer)
+
+		svc := newTestService(new(mockStorage))
+		svc.mailer = mailer
+		svc.users = &th.FakeUsers{User: usersSchema.User{Email: "john@example.com", EmailVerified: false}}
+
+		require.NoError(t, svc.deliver(ctx, deliverPayload{
+			Channel:      notificationsSchema.ChannelEmail,
+			Notification: testNotification(),
+		}))
+
+		mailer.AssertNotCalled(t, "Send")
+	})
+
+	t.Run("unknown channel", func(t *testing.T) {
+		t.Parallel()
+
+		err := newTestService(new(mockStorage)).deliver(ctx, deliverPayload{
+			Channel:      "pigeon",
+			Notification: testNotification(),
+		})
+		require.ErrorIs(t, err, jobsSchema.ErrPermanent)
+	})
+}
@@ -0,0 +1,29 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Notification - entity of `notifications` table.
+type Notification struct {
+	UUID       string `db:"uuid"`
+	WisherUUID string `db:"wisher_uuid"`
+	Type       string `db:"type"`
+	Title      string `db:"title"`
+	Body       string `db:"body"`
+	Link       string `db:"link"`
+	// Data is JSON-encoded object
+	Data      string       `db:"data"`
+	CreatedAt time.Time    `db:"created_at"`
+	ReadAt    sql.NullTime `db:"read_at"`
+}
+
+// Preference - entity of `notification_preferences` table.
+type Preference struct {
+	WisherUUID string         `db:"wisher_uuid"`
+	Type       string         `db:"type"`
+	Channels   pq.StringArray `db:"channels"`
+}
@@ -0,0 +1,143 @@
+/*
+Package storage contains db-related operations with user notifications.
+*/
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	storageSchema "github.com/outcatcher/anwil/domains/storage/schema"
+)
+
+// notificationStorage - storage of user notifications.
+type notificationStorage struct {
+	db storageSchema.QueryExecutor
+}
+
+// New creates a new NotificationStorage instance.
+func New(db storageSchema.QueryExecutor) NotificationStorage {
+	return &notificationStorage{db: db}
+}
+
+// InsertNotification stores new notification.
+func (n *notificationStorage) InsertNotification(ctx context.Context, notification Notification) (*Notification, error) {
+	inserted := new(Notification)
+
+	err := n.db.GetContext(
+		ctx,
+		inserted,
+		`INSERT INTO notifications (wisher_uuid, type, title, body, link, data)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING *;`,
+		notification.WisherUUID, notification.Type, notification.Title, notification.Body, notification.Link,
+		notification.Data,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting notification: %w", err)
+	}
+
+	return inserted, nil
+}
+
+// ListNotifications returns latest notifications of the user, newest first. Zero limit returns all notifications.
+func (n *notificationStorage) ListNotifications(
+	ctx context.Context, wisherUUID string, unreadOnly bool, limit int,
+) ([]Notification, error) {
+	var notifications []Notification
+
+	err := sqlx.SelectContext(
+		ctx, n.db, &notifications,
+		`SELECT * FROM notifications
+		 WHERE wisher_uuid = $1 AND (NOT $2 OR read_at IS NULL)
+		 ORDER BY created_at DESC
+		 LIMIT NULLIF($3, 0);`,
+		wisherUUID, unreadOnly, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting notifications: %w", err)
+	}
+
+	return notifications, nil
+}
+
+// CountUnread returns number of unread notifications of the user.
+func (n *notificationStorage) CountUnread(ctx context.Context, wisherUUID string) (int, error) {
+	var count int
+
+	err := n.db.GetContext(
+		ctx, &count, `SELECT count(*) FROM notifications WHERE wisher_uuid = $1 AND read_at IS NULL;`, wisherUUID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("error counting unread notifications: %w", err)
+	}
+
+	return count, nil
+}
+
+// MarkRead marks notification of the user as read. Already read notification is not changed.
+func (n *notificationStorage) MarkRead(ctx context.Context, wisherUUID, uuid string) error {
+	result, err := n.db.ExecContext(
+		ctx,
+		`UPDATE notifications SET read_at = COALESCE(read_at, now()) WHERE uuid = $1 AND wisher_uuid = $2;`,
+		uuid, wisherUUID,
+	)
+	if err != nil {
+		return fmt.Errorf("error marking notification read: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error marking notification read: %w", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("notification %s: %w", uuid, errbase.ErrNotFound)
+	}
+
+	return nil
+}
+
+// MarkAllRead marks all notifications of the user as read.
+func (n *notificationStorage) MarkAllRead(ctx context.Context, wisherUUID string) error {
+	_, err := n.db.ExecContext(
+		ctx, `UPDATE notifications SET read_at = now() WHERE wisher_uuid = $1 AND read_at IS NULL;`, wisherUUID,
+	)
+	if err != nil {
+		return fmt.Errorf("error marking notifications read: %w", err)
+	}
+
+	return nil
+}
+
+// ListPreferences returns stored notification preferences of the user.
+func (n *notificationStorage) ListPreferences(ctx context.Context, wisherUUID string) ([]Preference, error) {
+	var preferences []Preference
+
+	err := sqlx.SelectContext(
+		ctx, n.db, &preferences, `SELECT * FROM notification_preferences WHERE wisher_uuid = $1;`, wisherUUID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting notification preferences: %w", err)
+	}
+
+	return preferences, nil
+}
+
+// SetPreference creates or replaces notification preference.
+func (n *notificationStorage) SetPreference(ctx context.Context, preference Preference) error {
+	_, err := n.db.ExecContext(
+		ctx,
+		`INSERT INTO notification_preferences (wisher_uuid, type, channels)
+		 VALUES ($1, $2, $3)
+		 ON CONFLICT (wisher_uuid, type) DO UPDATE SET channels = excluded.channels;`,
+		preference.WisherUUID, preference.Type, preference.Channels,
+	)
+	if err != nil {
+		return fmt.Errorf("error setting notification preference: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,16 @@
+package storage
+
+import "context"
+
+// NotificationStorage - storage of user notifications.
+type NotificationStorage interface {
+	InsertNotification(ctx context.Context, notification Notification) (*Notification, error)
+	// ListNotifications returns latest notifications of the user, newest first. Zero limit returns all notifications.
+	ListNotifications(ctx context.Context, wisherUUID string, unreadOnly bool, limit int) ([]Notification, error)
+	CountUnread(ctx context.Context, wisherUUID string) (int, error)
+	MarkRead(ctx context.Context, wisherUUID, uuid string) error
+	MarkAllRead(ctx context.Context, wisherUUID string) error
+
+	ListPreferences(ctx context.Context, wisherUUID string) ([]Preference, error)
+	SetPreference(ctx context.Context, preference Preference) error
+}
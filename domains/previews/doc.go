@@ -0,0 +1,6 @@
+/*
+Package previews contains functions and entities of link preview domain.
+
+Link previews are used to prefill wish data from shop product pages.
+*/
+package previews
@@ -0,0 +1,47 @@
+/*
+Package handlers contains API handlers for link preview endpoints.
+*/
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/outcatcher/anwil/domains/core/negotiation"
+	"github.com/outcatcher/anwil/domains/core/services"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/outcatcher/anwil/domains/core/validation"
+	"github.com/outcatcher/anwil/domains/previews/service/schema"
+)
+
+// AddPreviewHandlers - adds link preview endpoints.
+func AddPreviewHandlers(state svcSchema.ProvidingServices) svcSchema.AddHandlersFunc {
+	return func(_, secGroup *echo.Group) error {
+		previewService, err := services.GetServiceFromProvider[schema.PreviewService](state, schema.ServiceID)
+		if err != nil {
+			return fmt.Errorf("error adding preview handlers: %w", err)
+		}
+
+		// previews are available to authenticated users only, so the endpoint can't be used as open proxy
+		secGroup.GET("/previews", handleGetPreview(previewService))
+
+		return nil
+	}
+}
+
+func handleGetPreview(prv schema.PreviewService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		pageURL := c.QueryParam("url")
+		if pageURL == "" {
+			return fmt.Errorf("%w: query parameter 'url' is missing", validation.ErrValidationFailed)
+		}
+
+		preview, err := prv.Preview(c.Request().Context(), pageURL)
+		if err != nil {
+			return fmt.Errorf("error getting preview: %w", err)
+		}
+
+		return negotiation.Respond(c, http.StatusOK, preview)
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/outcatcher/anwil/domains/previews/service/schema"
+)
+
+// cacheEntry - single cached preview.
+type cacheEntry struct {
+	preview   schema.Preview
+	expiresAt time.Time
+}
+
+// previewCache - in-memory cache of fetched previews with limited size and entry lifetime.
+type previewCache struct {
+	lock    sync.Mutex
+	entries map[string]cacheEntry
+
+	ttl  time.Duration
+	size int
+
+	now func() time.Time
+}
+
+func newPreviewCache(ttl time.Duration, size int) *previewCache {
+	return &previewCache{
+		lock:    sync.Mutex{},
+		entries: make(map[string]cacheEntry),
+		ttl:     ttl,
+		size:    size,
+		now:     time.Now,
+	}
+}
+
+// get returns copy of cached preview if it's not expired.
+func (c *previewCache) get(key string) (*schema.Preview, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	if !c.now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+
+		return nil, false
+	}
+
+	preview := entry.preview
+
+	return &preview, true
+}
+
+// put adds preview to the cache, evicting expired entries or the oldest one if cache is full.
+func (c *previewCache) put(key string, preview *schema.Preview) {
+	if c.size <= 0 {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now()
+
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.size {
+		oldestKey, oldestExpiry := "", time.Time{}
+
+		for entryKey, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, entryKey)
+
+				continue
+			}
+
+			if oldestKey == "" || entry.expiresAt.Before(oldestExpiry) {
+				oldestKey, oldestExpiry = entryKey, entry.expiresAt
+			}
+		}
+
+		if len(c.entries) >= c.size {
+			delete(c.entries, oldestKey)
+		}
+	}
+
+	c.entries[key] = cacheEntry{preview: *preview, expiresAt: now.Add(c.ttl)}
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/outcatcher/anwil/domains/previews/service/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreviewCache(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	cache := newPreviewCache(time.Minute, 2)
+	cache.now = func() time.Time { return now }
+
+	cache.put("first", &schema.Preview{URL: "first"})
+
+	now = now.Add(time.Second)
+
+	cache.put("second", &schema.Preview{URL: "second"})
+	cache.put("third", &schema.Preview{URL: "third"})
+
+	_, ok := cache.get("first")
+	require.False(t, ok, "the oldest entry should be evicted")
+
+	preview, ok := cache.get("third")
+	require.True(t, ok)
+	require.Equal(t, "third", preview.URL)
+
+	now = now.Add(time.Minute)
+
+	_, ok = cache.get("third")
+	require.False(t, ok, "entry should expire")
+}
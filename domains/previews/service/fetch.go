@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	"github.com/outcatcher/anwil/domains/core/validation"
+	"github.com/outcatcher/anwil/domains/previews/service/schema"
+)
+
+// fetch downloads and parses page.
+func (s *service) fetch(ctx context.Context, pageURL *url.URL) (*schema.Preview, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL.String(), http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("error creating page request: %w", err)
+	}
+
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := s.client.Do(req)
+	if errors.Is(err, validation.ErrValidationFailed) {
+		return nil, fmt.Errorf("error fetching page: %w", err)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: error fetching page: %s", errbase.ErrUpstream, err.Error())
+	}
+
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			s.log.Println("error closing page body:", closeErr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: page responded with status %d", errbase.ErrUpstream, resp.StatusCode)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "" && mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, fmt.Errorf("%w: page content type %s is not HTML", errbase.ErrUnsupportedMediaType, mediaType)
+	}
+
+	// pages larger than limit are parsed partially, data we need is usually at the top
+	preview, err := parsePage(io.LimitReader(resp.Body, s.maxBodySize), resp.Request.URL)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing page: %w", err)
+	}
+
+	return preview, nil
+}
@@ -0,0 +1,307 @@
+package service
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/outcatcher/anwil/domains/previews/service/schema"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	priceRe    = regexp.MustCompile(`\d[\d\s\x{00A0}.,']*`)
+	currencyRe = regexp.MustCompile(`^[A-Z]{3}$`)
+)
+
+// pageData holds raw values found on the page.
+type pageData struct {
+	// meta holds values of <meta> tags by `property` or `name` attribute, first value wins
+	meta     map[string]string
+	title    string
+	products []map[string]any
+}
+
+// metaValue returns first non-empty meta value of the given keys.
+func (p *pageData) metaValue(keys ...string) string {
+	for _, key := range keys {
+		if value := p.meta[key]; value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// firstNonEmpty returns first non-empty value.
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// parsePage parses HTML page returning found preview data.
+//
+// pageURL is used to resolve relative image URLs.
+func parsePage(body io.Reader, pageURL *url.URL) (*schema.Preview, error) {
+	root, err := html.Parse(body)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	data := &pageData{meta: make(map[string]string)}
+
+	data.walk(root)
+
+	product := make(map[string]any)
+	if len(data.products) > 0 {
+		product = data.products[0]
+	}
+
+	preview := &schema.Preview{
+		URL: pageURL.String(),
+		Title: firstNonEmpty(
+			data.metaValue("og:title"), stringValue(product["name"]), data.metaValue("twitter:title"), data.title,
+		),
+		Description: firstNonEmpty(
+			data.metaValue("og:description"),
+			stringValue(product["description"]),
+			data.metaValue("twitter:description", "description"),
+		),
+		Image: resolveURL(pageURL, firstNonEmpty(
+			data.metaValue("og:image:secure_url", "og:image", "og:image:url"),
+			imageValue(product["image"]),
+			data.metaValue("twitter:image", "twitter:image:src"),
+		)),
+	}
+
+	price, currency := offerPrice(product["offers"])
+	if price == "" {
+		price = data.metaValue("product:price:amount", "og:price:amount")
+		currency = data.metaValue("product:price:currency", "og:price:currency")
+	}
+
+	preview.Price = normalizePrice(price)
+	if preview.Price != "" {
+		preview.Currency = normalizeCurrency(currency)
+	}
+
+	return preview, nil
+}
+
+// walk collects page data from the node and all its children.
+func (p *pageData) walk(node *html.Node) {
+	if node.Type == html.ElementNode {
+		switch node.DataAtom { //nolint:exhaustive
+		case atom.Meta:
+			p.addMeta(node)
+		case atom.Title:
+			if p.title == "" && node.FirstChild != nil {
+				p.title = node.FirstChild.Data
+			}
+		case atom.Script:
+			if strings.EqualFold(attr(node, "type"), "application/ld+json") && node.FirstChild != nil {
+				p.addLinkedData(node.FirstChild.Data)
+			}
+		}
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		p.walk(child)
+	}
+}
+
+// attr returns value of the node attribute.
+func attr(node *html.Node, name string) string {
+	for _, attribute := range node.Attr {
+		if strings.EqualFold(attribute.Key, name) {
+			return attribute.Val
+		}
+	}
+
+	return ""
+}
+
+func (p *pageData) addMeta(node *html.Node) {
+	key := strings.ToLower(firstNonEmpty(attr(node, "property"), attr(node, "name"), attr(node, "itemprop")))
+	if key == "" {
+		return
+	}
+
+	if _, ok := p.meta[key]; ok {
+		return
+	}
+
+	p.meta[key] = strings.TrimSpace(attr(node, "content"))
+}
+
+// addLinkedData collects Product entities from JSON-LD script. Invalid scripts are ignored.
+func (p *pageData) addLinkedData(script string) {
+	var data any
+
+	if err := json.Unmarshal([]byte(script), &data); err != nil {
+		return
+	}
+
+	p.collectProducts(data)
+}
+
+func (p *pageData) collectProducts(data any) {
+	switch value := data.(type) {
+	case []any:
+		for _, item := range value {
+			p.collectProducts(item)
+		}
+	case map[string]any:
+		if isProduct(value["@type"]) {
+			p.products = append(p.products, value)
+		}
+
+		if graph, ok := value["@graph"]; ok {
+			p.collectProducts(graph)
+		}
+	}
+}
+
+// isProduct checks if JSON-LD `@type` value is a Product.
+func isProduct(typeValue any) bool {
+	switch value := typeValue.(type) {
+	case string:
+		return value == "Product" || strings.HasSuffix(value, "/Product")
+	case []any:
+		for _, item := range value {
+			if isProduct(item) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// stringValue returns JSON-LD value as a string.
+func stringValue(value any) string {
+	switch typed := value.(type) {
+	case string:
+		return html.UnescapeString(typed)
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+// imageValue returns first image URL of JSON-LD `image` value, which can be a string, an ImageObject or an array.
+func imageValue(value any) string {
+	switch typed := value.(type) {
+	case string:
+		return typed
+	case map[string]any:
+		return firstNonEmpty(stringValue(typed["url"]), stringValue(typed["contentUrl"]))
+	case []any:
+		for _, item := range typed {
+			if image := imageValue(item); image != "" {
+				return image
+			}
+		}
+	}
+
+	return ""
+}
+
+// offerPrice returns price and currency of the first JSON-LD offer having the price.
+func offerPrice(value any) (string, string) {
+	switch typed := value.(type) {
+	case map[string]any:
+		currency := stringValue(typed["priceCurrency"])
+
+		if price := firstNonEmpty(stringValue(typed["price"]), stringValue(typed["lowPrice"])); price != "" {
+			return price, currency
+		}
+
+		if spec, ok := typed["priceSpecification"]; ok {
+			price, specCurrency := offerPrice(spec)
+
+			return price, firstNonEmpty(specCurrency, currency)
+		}
+	case []any:
+		for _, item := range typed {
+			if price, currency := offerPrice(item); price != "" {
+				return price, currency
+			}
+		}
+	}
+
+	return "", ""
+}
+
+// normalizePrice converts price text to decimal with dot separator, i.e. "1 299,90 EUR" to "1299.90".
+//
+// Empty string is returned if text contains no price.
+func normalizePrice(text string) string {
+	digits := strings.TrimRight(priceRe.FindString(text), " \u00a0\t\r\n.,'")
+	if digits == "" {
+		return ""
+	}
+
+	// the last separator followed by 1 or 2 digits is a decimal one, all the others are thousands separators
+	decimalAt := strings.LastIndexAny(digits, ".,")
+	if decimalAt >= 0 && len(digits)-decimalAt-1 > 2 {
+		decimalAt = -1
+	}
+
+	var result strings.Builder
+
+	for i, char := range digits {
+		switch {
+		case char >= '0' && char <= '9':
+			result.WriteRune(char)
+		case i == decimalAt:
+			result.WriteRune('.')
+		}
+	}
+
+	if _, err := strconv.ParseFloat(result.String(), 64); err != nil {
+		return ""
+	}
+
+	return result.String()
+}
+
+// normalizeCurrency returns ISO 4217 currency code or empty string for invalid value.
+func normalizeCurrency(value string) string {
+	value = strings.ToUpper(strings.TrimSpace(value))
+
+	if !currencyRe.MatchString(value) {
+		return ""
+	}
+
+	return value
+}
+
+// resolveURL returns absolute URL of the reference. Only http(s) URLs are returned.
+func resolveURL(base *url.URL, ref string) string {
+	if ref == "" {
+		return ""
+	}
+
+	parsed, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+
+	resolved := base.ResolveReference(parsed)
+	if resolved.Scheme != "http" && resolved.Scheme != "https" {
+		return ""
+	}
+
+	return resolved.String()
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/outcatcher/anwil/domains/previews/service/schema"
+	"github.com/stretchr/testify/require"
+)
+
+const openGraphPage = `<!DOCTYPE html>
+<html>
+<head>
+  <title>Shop - Teapot</title>
+  <meta property="og:title" content="Teapot &amp; cups">
+  <meta property="og:description" content="Porcelain teapot">
+  <meta property="og:image" content="/images/teapot.jpg">
+  <meta property="product:price:amount" content="1 299,90">
+  <meta property="product:price:currency" content="eur">
+  <meta name="description" content="Generic shop description">
+</head>
+<body></body>
+</html>`
+
+const linkedDataPage = `<html>
+<head>
+  <title>Headphones | Shop</title>
+  <script type="application/ld+json">{"@context": "https://schema.org", "@type": "Organization", "name": "Shop"}</script>
+  <script type="application/ld+json">
+  {
+    "@context": "https://schema.org",
+    "@graph": [
+      {"@type": "BreadcrumbList"},
+      {
+        "@type": "Product",
+        "name": "Wireless headphones",
+        "description": "Noise cancelling",
+        "image": [{"@type": "ImageObject", "url": "https://cdn.example.com/headphones.png"}],
+        "offers": [{"@type": "Offer", "price": 199.5, "priceCurrency": "USD"}]
+      }
+    ]
+  }
+  </script>
+  <script type="application/ld+json">{ invalid json</script>
+</head>
+</html>`
+
+const basicPage = `<html><head>
+  <title>  Plain page  </title>
+  <meta name="description" content="Just a page">
+  <meta name="twitter:image" content="https://example.com/card.png">
+</head></html>`
+
+func TestParsePage(t *testing.T) {
+	t.Parallel()
+
+	pageURL, err := url.Parse("https://shop.example.com/products/1")
+	require.NoError(t, err)
+
+	cases := []struct {
+		name     string
+		page     string
+		expected schema.Preview
+	}{
+		{
+			"open graph", openGraphPage,
+			schema.Preview{
+				URL:         pageURL.String(),
+				Title:       "Teapot & cups",
+				Description: "Porcelain teapot",
+				Image:       "https://shop.example.com/images/teapot.jpg",
+				Price:       "1299.90",
+				Currency:    "EUR",
+			},
+		},
+		{
+			"json-ld", linkedDataPage,
+			schema.Preview{
+				URL:         pageURL.String(),
+				Title:       "Wireless headphones",
+				Description: "Noise cancelling",
+				Image:       "https://cdn.example.com/headphones.png",
+				Price:       "199.5",
+				Currency:    "USD",
+			},
+		},
+		{
+			"basic meta", basicPage,
+			schema.Preview{
+				URL:         pageURL.String(),
+				Title:       "Plain page",
+				Description: "Just a page",
+				Image:       "https://example.com/card.png",
+			},
+		},
+	}
+
+	for _, data := range cases {
+		data := data
+
+		t.Run(data.name, func(t *testing.T) {
+			t.Parallel()
+
+			preview, err := parsePage(strings.NewReader(data.page), pageURL)
+			require.NoError(t, err)
+			require.Equal(t, data.expected, *preview)
+		})
+	}
+}
+
+func TestNormalizePrice(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"19.99":       "19.99",
+		"$ 1,299.00":  "1299.00",
+		"1.299,5 EUR": "1299.5",
+		"1 299 руб.":  "1299",
+		"1,299":       "1299",
+		"1'000.–":     "1000",
+		"12.":         "12",
+		"free":        "",
+		"":            "",
+	}
+
+	for input, expected := range cases {
+		input, expected := input, expected
+
+		t.Run(input, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, expected, normalizePrice(input))
+		})
+	}
+}
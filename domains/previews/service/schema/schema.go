@@ -0,0 +1,35 @@
+/*
+Package schema contains service definition for Previews service
+*/
+package schema
+
+import (
+	"context"
+
+	"github.com/outcatcher/anwil/domains/core/services/schema"
+)
+
+// ServiceID - ID for link previews service.
+const ServiceID schema.ServiceID = "previews"
+
+// PreviewService - service fetching link previews.
+type PreviewService interface {
+	// Preview returns preview of the page with given URL.
+	Preview(ctx context.Context, pageURL string) (*Preview, error)
+}
+
+// Preview holds data of the product page.
+//
+// Missing values are empty.
+type Preview struct {
+	// URL is a final URL of the page after all redirects
+	URL         string `json:"url"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Image is an absolute URL of the product image
+	Image string `json:"image,omitempty"`
+	// Price is a decimal price value, i.e. "19.99"
+	Price string `json:"price,omitempty"`
+	// Currency is an ISO 4217 currency code
+	Currency string `json:"currency,omitempty"`
+}
@@ -0,0 +1,132 @@
+/*
+Package service contains link previews service methods
+*/
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
+	logSchema "github.com/outcatcher/anwil/domains/core/logging/schema"
+	"github.com/outcatcher/anwil/domains/core/safehttp"
+	"github.com/outcatcher/anwil/domains/core/services"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/outcatcher/anwil/domains/previews/handlers"
+	"github.com/outcatcher/anwil/domains/previews/service/schema"
+)
+
+const (
+	defaultTimeout     = 5 * time.Second
+	defaultMaxBodySize = 2 << 20 // 2MB
+	defaultCacheTTL    = time.Hour
+	defaultCacheSize   = 1000
+
+	userAgent = "Mozilla/5.0 (compatible; AnwilPreview/1.0)"
+)
+
+// service - link previews service.
+type service struct {
+	cfg *configSchema.Configuration
+	log *log.Logger
+
+	client *http.Client
+	cache  *previewCache
+
+	// max number of page bytes read
+	maxBodySize int64
+}
+
+// UseConfig attaches configuration to the service.
+func (s *service) UseConfig(configuration *configSchema.Configuration) {
+	s.cfg = configuration
+}
+
+// UseLogger attaches logger to the service.
+func (s *service) UseLogger(logger *log.Logger) {
+	s.log = logger
+}
+
+// Preview returns preview of the page with given URL.
+//
+// Previews are cached by URL.
+func (s *service) Preview(ctx context.Context, pageURL string) (*schema.Preview, error) {
+	parsedURL, err := safehttp.ParseURL(pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("error getting preview: %w", err)
+	}
+
+	key := parsedURL.String()
+
+	if preview, ok := s.cache.get(key); ok {
+		return preview, nil
+	}
+
+	preview, err := s.fetch(ctx, parsedURL)
+	if err != nil {
+		return nil, fmt.Errorf("error getting preview: %w", err)
+	}
+
+	s.cache.put(key, preview)
+
+	return preview, nil
+}
+
+// setup prepares service using configuration values, falling back to defaults.
+//
+// Connections to private networks are allowed only if allowPrivate is set.
+func (s *service) setup(cfg configSchema.PreviewsConfiguration, allowPrivate bool) {
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	s.maxBodySize = cfg.MaxBodySize
+	if s.maxBodySize == 0 {
+		s.maxBodySize = defaultMaxBodySize
+	}
+
+	cacheTTL := cfg.CacheTTL
+	if cacheTTL == 0 {
+		cacheTTL = defaultCacheTTL
+	}
+
+	cacheSize := cfg.CacheSize
+	if cacheSize == 0 {
+		cacheSize = defaultCacheSize
+	}
+
+	s.client = safehttp.NewClient(timeout, allowPrivate)
+	s.cache = newPreviewCache(cacheTTL, cacheSize)
+}
+
+func previewServiceInit(_ context.Context, state any) (any, error) {
+	svc := new(service)
+
+	err := services.InjectServiceWith(
+		svc, state,
+		logSchema.LoggerInject,
+		configSchema.ConfigInject,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing previews service: %w", err)
+	}
+
+	svc.setup(svc.cfg.Previews, false)
+
+	return svc, nil
+}
+
+// NewPreviewService returns new link previews service definition.
+func NewPreviewService() svcSchema.ServiceDefinition {
+	return svcSchema.ServiceDefinition{
+		ID:               schema.ServiceID,
+		Init:             previewServiceInit,
+		DependsOn:        nil,
+		InitHandlersFunc: handlers.AddPreviewHandlers,
+		InitJobsFunc:     nil,
+	}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	"github.com/outcatcher/anwil/domains/core/validation"
+	"github.com/stretchr/testify/require"
+)
+
+const testMaxBodySize = 4096
+
+func newTestService(t *testing.T, allowPrivate bool) *service {
+	t.Helper()
+
+	svc := new(service)
+	svc.log = log.New(io.Discard, "", 0)
+	svc.setup(configSchema.PreviewsConfiguration{
+		Timeout:     time.Second,
+		MaxBodySize: testMaxBodySize,
+		CacheTTL:    time.Minute,
+		CacheSize:   10,
+	}, allowPrivate)
+
+	return svc
+}
+
+// newShop starts test server counting page requests.
+func newShop(t *testing.T) (*httptest.Server, *int32) {
+	t.Helper()
+
+	requests := new(int32)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/teapot", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = io.WriteString(w, openGraphPage)
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/teapot", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+	mux.HandleFunc("/huge", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = fmt.Fprintf(
+			w, "<html><head><!-- %s --><title>Too far</title></head></html>",
+			strings.Repeat("x", testMaxBodySize),
+		)
+	})
+	mux.HandleFunc("/image.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte("\x89PNG"))
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(5 * time.Second):
+		case <-r.Context().Done():
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server, requests
+}
+
+func TestPreview(t *testing.T) {
+	t.Parallel()
+
+	shop, requests := newShop(t)
+	svc := newTestService(t, true)
+	ctx := context.Background()
+
+	preview, err := svc.Preview(ctx, shop.URL+"/teapot")
+	require.NoError(t, err)
+	require.Equal(t, "Teapot & cups", preview.Title)
+	require.Equal(t, shop.URL+"/images/teapot.jpg", preview.Image)
+	require.Equal(t, "1299.90", preview.Price)
+
+	// cached
+	preview.Title = "changed by caller"
+
+	cached, err := svc.Preview(ctx, shop.URL+"/teapot#fragment")
+	require.NoError(t, err)
+	require.Equal(t, "Teapot & cups", cached.Title)
+	require.EqualValues(t, 1, atomic.LoadInt32(requests))
+}
+
+func TestPreviewRedirect(t *testing.T) {
+	t.Parallel()
+
+	shop, _ := newShop(t)
+	svc := newTestService(t, true)
+
+	preview, err := svc.Preview(context.Background(), shop.URL+"/moved")
+	require.NoError(t, err)
+	require.Equal(t, shop.URL+"/teapot", preview.URL)
+	require.Equal(t, "Teapot & cups", preview.Title)
+}
+
+func TestPreviewBodyLimit(t *testing.T) {
+	t.Parallel()
+
+	shop, _ := newShop(t)
+	svc := newTestService(t, true)
+
+	preview, err := svc.Preview(context.Background(), shop.URL+"/huge")
+	require.NoError(t, err)
+	require.Empty(t, preview.Title)
+}
+
+func TestPreviewErrors(t *testing.T) {
+	t.Parallel()
+
+	shop, _ := newShop(t)
+
+	cases := []struct {
+		name         string
+		url          string
+		allowPrivate bool
+		expectedErr  error
+	}{
+		{"private address", shop.URL + "/teapot", false, validation.ErrValidationFailed},
+		{"invalid scheme", "gopher://example.com", true, validation.ErrValidationFailed},
+		{"not found", shop.URL + "/missing", true, errbase.ErrUpstream},
+		{"redirect loop", shop.URL + "/loop", true, errbase.ErrUpstream},
+		{"not html", shop.URL + "/image.png", true, errbase.ErrUnsupportedMediaType},
+		{"timeout", shop.URL + "/slow", true, errbase.ErrUpstream},
+	}
+
+	for _, data := range cases {
+		data := data
+
+		t.Run(data.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := newTestService(t, data.allowPrivate)
+
+			_, err := svc.Preview(context.Background(), data.url)
+			require.ErrorIs(t, err, data.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,8 @@
+/*
+Package santa contains functions and entities of Secret Santa domain.
+
+Group owner invites users to the group and sets exclusion rules, e.g. spouses or last year's pairs.
+When the names are drawn, each joined member is assigned a receiver of their gift. The draw either
+respects all exclusion rules or fails. Members can see their own assignment only.
+*/
+package santa
@@ -0,0 +1,362 @@
+/*
+Package handlers contains API handlers for Secret Santa endpoints.
+*/
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/outcatcher/anwil/domains/core/negotiation"
+	"github.com/outcatcher/anwil/domains/core/services"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/outcatcher/anwil/domains/core/validation"
+	"github.com/outcatcher/anwil/domains/santa/service/schema"
+	"github.com/outcatcher/anwil/domains/users/auth"
+)
+
+type groupRequest struct {
+	Name              string `json:"name" form:"name" validate:"required,max=100"`
+	PreviousGroupUUID string `json:"previous_group_uuid" form:"previous_group_uuid" validate:"omitempty,uuid"`
+}
+
+type inviteRequest struct {
+	Username string `json:"username" form:"username" validate:"required"`
+}
+
+type exclusionRequest struct {
+	GiverUUID    string `json:"giver_uuid" form:"giver_uuid" validate:"required,uuid"`
+	ReceiverUUID string `json:"receiver_uuid" form:"receiver_uuid" validate:"required,uuid"`
+	Mutual       bool   `json:"mutual" form:"mutual"`
+}
+
+// AddSantaHandlers - adds Secret Santa endpoints.
+func AddSantaHandlers(state svcSchema.ProvidingServices) svcSchema.AddHandlersFunc {
+	return func(_, secGroup *echo.Group) error {
+		santaService, err := services.GetServiceFromProvider[schema.SantaService](state, schema.ServiceID)
+		if err != nil {
+			return fmt.Errorf("error adding santa handlers: %w", err)
+		}
+
+		consumes := negotiation.Consumes(negotiation.DataTypes...)
+
+		secGroup.POST("/santa/groups", handleCreateGroup(santaService), consumes)
+		secGroup.GET("/santa/groups", handleListGroups(santaService, schema.StatusJoined))
+		secGroup.GET("/santa/invites", handleListGroups(santaService, schema.StatusInvited))
+		secGroup.GET("/santa/groups/:uuid", handleGetGroup(santaService))
+		secGroup.DELETE("/santa/groups/:uuid", handleDeleteGroup(santaService))
+
+		secGroup.POST("/santa/groups/:uuid/members", handleInviteMember(santaService), consumes)
+		// join and draw are requested without body
+		secGroup.POST("/santa/groups/:uuid/join", handleJoinGroup(santaService), negotiation.Consumes())
+		secGroup.DELETE("/santa/groups/:uuid/members/:member", handleRemoveMember(santaService))
+
+		secGroup.GET("/santa/groups/:uuid/exclusions", handleListExclusions(santaService))
+		secGroup.POST("/santa/groups/:uuid/exclusions", handleAddExclusion(santaService), consumes)
+		secGroup.DELETE("/santa/groups/:uuid/exclusions/:giver/:receiver", handleRemoveExclusion(santaService))
+
+		secGroup.POST("/santa/groups/:uuid/draw", handleDraw(santaService), negotiation.Consumes())
+		secGroup.GET("/santa/groups/:uuid/assignment", handleGetAssignment(santaService))
+
+		return nil
+	}
+}
+
+// uuidParams returns validated UUID path parameters.
+func uuidParams(c echo.Context, names ...string) ([]string, error) {
+	values := make([]string, len(names))
+
+	for i, name := range names {
+		value := c.Param(name)
+
+		if _, err := uuid.Parse(value); err != nil {
+			return nil, fmt.Errorf("%w: invalid %s UUID %q", validation.ErrValidationFailed, name, value)
+		}
+
+		values[i] = value
+	}
+
+	return values, nil
+}
+
+// bindRequest binds and validates request body.
+func bindRequest(c echo.Context, req any) error {
+	if err := c.Bind(req); err != nil {
+		return fmt.Errorf("error binding request: %w", err)
+	}
+
+	if err := validation.ValidateJSONCtx(c.Request().Context(), req); err != nil {
+		return fmt.Errorf("error validating request: %w", err)
+	}
+
+	return nil
+}
+
+func handleCreateGroup(santa schema.SantaService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error creating santa group: %w", err)
+		}
+
+		req := new(groupRequest)
+
+		if err := bindRequest(c, req); err != nil {
+			return err
+		}
+
+		group, err := santa.CreateGroup(c.Request().Context(), claims.UserUUID, schema.GroupInput{
+			Name:              req.Name,
+			PreviousGroupUUID: req.PreviousGroupUUID,
+		})
+		if err != nil {
+			return fmt.Errorf("error creating santa group: %w", err)
+		}
+
+		return negotiation.Respond(c, http.StatusCreated, group)
+	}
+}
+
+func handleListGroups(santa schema.SantaService, status string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error listing santa groups: %w", err)
+		}
+
+		groups, err := santa.ListGroups(c.Request().Context(), claims.UserUUID, status)
+		if err != nil {
+			return fmt.Errorf("error listing santa groups: %w", err)
+		}
+
+		return negotiation.Respond(c, http.StatusOK, groups)
+	}
+}
+
+func handleGetGroup(santa schema.SantaService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error getting santa group: %w", err)
+		}
+
+		params, err := uuidParams(c, "uuid")
+		if err != nil {
+			return fmt.Errorf("error getting santa group: %w", err)
+		}
+
+		group, err := santa.GetGroup(c.Request().Context(), claims.UserUUID, params[0])
+		if err != nil {
+			return fmt.Errorf("error getting santa group: %w", err)
+		}
+
+		return negotiation.Respond(c, http.StatusOK, group)
+	}
+}
+
+func handleDeleteGroup(santa schema.SantaService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error deleting santa group: %w", err)
+		}
+
+		params, err := uuidParams(c, "uuid")
+		if err != nil {
+			return fmt.Errorf("error deleting santa group: %w", err)
+		}
+
+		if err := santa.DeleteGroup(c.Request().Context(), claims.UserUUID, params[0]); err != nil {
+			return fmt.Errorf("error deleting santa group: %w", err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func handleInviteMember(santa schema.SantaService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error inviting santa group member: %w", err)
+		}
+
+		params, err := uuidParams(c, "uuid")
+		if err != nil {
+			return fmt.Errorf("error inviting santa group member: %w", err)
+		}
+
+		req := new(inviteRequest)
+
+		if err := bindRequest(c, req); err != nil {
+			return err
+		}
+
+		member, err := santa.InviteMember(c.Request().Context(), claims.UserUUID, params[0], req.Username)
+		if err != nil {
+			return fmt.Errorf("error inviting santa group member: %w", err)
+		}
+
+		return negotiation.Respond(c, http.StatusCreated, member)
+	}
+}
+
+func handleJoinGroup(santa schema.SantaService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error joining santa group: %w", err)
+		}
+
+		params, err := uuidParams(c, "uuid")
+		if err != nil {
+			return fmt.Errorf("error joining santa group: %w", err)
+		}
+
+		if err := santa.JoinGroup(c.Request().Context(), claims.UserUUID, params[0]); err != nil {
+			return fmt.Errorf("error joining santa group: %w", err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func handleRemoveMember(santa schema.SantaService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error removing santa group member: %w", err)
+		}
+
+		params, err := uuidParams(c, "uuid", "member")
+		if err != nil {
+			return fmt.Errorf("error removing santa group member: %w", err)
+		}
+
+		if err := santa.RemoveMember(c.Request().Context(), claims.UserUUID, params[0], params[1]); err != nil {
+			return fmt.Errorf("error removing santa group member: %w", err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func handleListExclusions(santa schema.SantaService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error listing santa exclusions: %w", err)
+		}
+
+		params, err := uuidParams(c, "uuid")
+		if err != nil {
+			return fmt.Errorf("error listing santa exclusions: %w", err)
+		}
+
+		exclusions, err := santa.ListExclusions(c.Request().Context(), claims.UserUUID, params[0])
+		if err != nil {
+			return fmt.Errorf("error listing santa exclusions: %w", err)
+		}
+
+		return negotiation.Respond(c, http.StatusOK, exclusions)
+	}
+}
+
+func handleAddExclusion(santa schema.SantaService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error adding santa exclusion: %w", err)
+		}
+
+		params, err := uuidParams(c, "uuid")
+		if err != nil {
+			return fmt.Errorf("error adding santa exclusion: %w", err)
+		}
+
+		req := new(exclusionRequest)
+
+		if err := bindRequest(c, req); err != nil {
+			return err
+		}
+
+		err = santa.AddExclusion(c.Request().Context(), claims.UserUUID, params[0], schema.Exclusion{
+			GiverUUID:    req.GiverUUID,
+			ReceiverUUID: req.ReceiverUUID,
+			Mutual:       req.Mutual,
+		})
+		if err != nil {
+			return fmt.Errorf("error adding santa exclusion: %w", err)
+		}
+
+		return c.NoContent(http.StatusCreated)
+	}
+}
+
+func handleRemoveExclusion(santa schema.SantaService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error removing santa exclusion: %w", err)
+		}
+
+		params, err := uuidParams(c, "uuid", "giver", "receiver")
+		if err != nil {
+			return fmt.Errorf("error removing santa exclusion: %w", err)
+		}
+
+		err = santa.RemoveExclusion(c.Request().Context(), claims.UserUUID, params[0], schema.Exclusion{
+			GiverUUID:    params[1],
+			ReceiverUUID: params[2],
+			Mutual:       false,
+		})
+		if err != nil {
+			return fmt.Errorf("error removing santa exclusion: %w", err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func handleDraw(santa schema.SantaService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error drawing santa group: %w", err)
+		}
+
+		params, err := uuidParams(c, "uuid")
+		if err != nil {
+			return fmt.Errorf("error drawing santa group: %w", err)
+		}
+
+		if err := santa.Draw(c.Request().Context(), claims.UserUUID, params[0]); err != nil {
+			return fmt.Errorf("error drawing santa group: %w", err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func handleGetAssignment(santa schema.SantaService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error getting santa assignment: %w", err)
+		}
+
+		params, err := uuidParams(c, "uuid")
+		if err != nil {
+			return fmt.Errorf("error getting santa assignment: %w", err)
+		}
+
+		receiver, err := santa.GetAssignment(c.Request().Context(), claims.UserUUID, params[0])
+		if err != nil {
+			return fmt.Errorf("error getting santa assignment: %w", err)
+		}
+
+		return negotiation.Respond(c, http.StatusOK, receiver)
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	"github.com/outcatcher/anwil/domains/core/validation"
+	"github.com/outcatcher/anwil/domains/santa/service/schema"
+	"github.com/outcatcher/anwil/domains/santa/storage"
+)
+
+// Draw assigns a receiver to each joined member respecting the exclusions. Pending invites are dropped.
+func (s *service) Draw(ctx context.Context, ownerUUID, groupUUID string) error {
+	group, err := s.ownedGroup(ctx, ownerUUID, groupUUID)
+	if err != nil {
+		return fmt.Errorf("error drawing santa group: %w", err)
+	}
+
+	if group.DrawnAt.Valid {
+		return fmt.Errorf("error drawing santa group: %w", errAlreadyDrawn)
+	}
+
+	members, err := s.storage.ListMembers(ctx, groupUUID)
+	if err != nil {
+		return fmt.Errorf("error drawing santa group: %w", err)
+	}
+
+	joined := make([]string, 0, len(members))
+
+	for _, member := range members {
+		if member.Status == storage.StatusJoined {
+			joined = append(joined, member.WisherUUID)
+		}
+	}
+
+	if len(joined) < minDrawMembers {
+		return fmt.Errorf("%w: at least %d joined members are required for the draw",
+			validation.ErrValidationFailed, minDrawMembers)
+	}
+
+	exclusions, err := s.storage.ListExclusions(ctx, groupUUID)
+	if err != nil {
+		return fmt.Errorf("error drawing santa group: %w", err)
+	}
+
+	excluded := make(map[pair]bool, len(exclusions))
+
+	for _, exclusion := range exclusions {
+		excluded[pair{giver: exclusion.GiverUUID, receiver: exclusion.ReceiverUUID}] = true
+	}
+
+	drawn, err := draw(joined, excluded)
+	if err != nil {
+		return fmt.Errorf("error drawing santa group: %w", err)
+	}
+
+	assignments := make([]storage.Pair, 0, len(drawn))
+
+	for giver, receiver := range drawn {
+		assignments = append(assignments, storage.Pair{GroupUUID: groupUUID, GiverUUID: giver, ReceiverUUID: receiver})
+	}
+
+	if err := s.storage.SaveDraw(ctx, groupUUID, assignments); err != nil {
+		return fmt.Errorf("error drawing santa group: %w", err)
+	}
+
+	if err := s.queue.Enqueue(ctx, notifyDrawJobKind, notifyDrawPayload{GroupUUID: groupUUID}); err != nil {
+		// assignments are available anyway
+		s.log.Printf("error scheduling santa group %s draw notifications: %s", groupUUID, err)
+	}
+
+	return nil
+}
+
+// GetAssignment returns receiver assigned to the user in the group.
+func (s *service) GetAssignment(ctx context.Context, userUUID, groupUUID string) (*schema.Member, error) {
+	group, _, err := s.memberGroup(ctx, userUUID, groupUUID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting santa assignment: %w", err)
+	}
+
+	if !group.DrawnAt.Valid {
+		return nil, fmt.Errorf("%w: names are not drawn yet", errbase.ErrNotFound)
+	}
+
+	assignment, err := s.storage.GetAssignment(ctx, groupUUID, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting santa assignment: %w", err)
+	}
+
+	receiver, err := s.toMember(ctx, assignment.ReceiverUUID, "")
+	if err != nil {
+		return nil, fmt.Errorf("error getting santa assignment: %w", err)
+	}
+
+	return receiver, nil
+}
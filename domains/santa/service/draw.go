@@ -0,0 +1,112 @@
+package service
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	"github.com/outcatcher/anwil/domains/core/errbase"
+)
+
+// ErrNoValidDraw - error for exclusions making any assignment impossible.
+var ErrNoValidDraw = fmt.Errorf("%w: no assignment respects all exclusions", errbase.ErrConflict)
+
+// pair - giver and receiver UUIDs.
+type pair struct {
+	giver    string
+	receiver string
+}
+
+// draw assigns a receiver to each member so that nobody is assigned to themselves,
+// no excluded pair is assigned and each member receives exactly one gift.
+//
+// Assignment is a perfect matching in the graph of allowed giver-receiver pairs. It's found with
+// augmenting paths (Kuhn's algorithm), which finds the matching whenever it exists,
+// so ErrNoValidDraw means the exclusions are impossible to satisfy. Members and candidates
+// are shuffled to randomize the result.
+func draw(members []string, excluded map[pair]bool) (map[string]string, error) {
+	order := append([]string(nil), members...)
+
+	if err := shuffle(order); err != nil {
+		return nil, err
+	}
+
+	count := len(order)
+	candidates := make([][]string, count)
+	index := make(map[string]int, count)
+
+	for i, member := range order {
+		index[member] = i
+	}
+
+	for i, giver := range order {
+		for _, receiver := range order {
+			if giver == receiver || excluded[pair{giver: giver, receiver: receiver}] {
+				continue
+			}
+
+			candidates[i] = append(candidates[i], receiver)
+		}
+
+		if err := shuffle(candidates[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	// giverOf maps receiver index to the index of the giver assigned, -1 for unassigned receivers
+	giverOf := make([]int, count)
+	for i := range giverOf {
+		giverOf[i] = -1
+	}
+
+	var augment func(giver int, visited []bool) bool
+
+	augment = func(giver int, visited []bool) bool {
+		for _, receiver := range candidates[giver] {
+			r := index[receiver]
+			if visited[r] {
+				continue
+			}
+
+			visited[r] = true
+
+			// receiver is free or its current giver can be reassigned
+			if giverOf[r] < 0 || augment(giverOf[r], visited) {
+				giverOf[r] = giver
+
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for giver := range order {
+		if !augment(giver, make([]bool, count)) {
+			return nil, ErrNoValidDraw
+		}
+	}
+
+	assignments := make(map[string]string, count)
+
+	for receiver, giver := range giverOf {
+		assignments[order[giver]] = order[receiver]
+	}
+
+	return assignments, nil
+}
+
+// shuffle shuffles items using cryptographically secure random numbers,
+// so the result can't be predicted by the group owner.
+func shuffle(items []string) error {
+	for i := len(items) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return fmt.Errorf("error shuffling members: %w", err)
+		}
+
+		items[i], items[j.Int64()] = items[j.Int64()], items[i]
+	}
+
+	return nil
+}
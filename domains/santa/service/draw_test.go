@@ -0,0 +1,115 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func members(count int) []string {
+	result := make([]string, count)
+
+	for i := range result {
+		result[i] = fmt.Sprintf("member-%d", i)
+	}
+
+	return result
+}
+
+// requireValidDraw checks that each member gives and receives exactly one gift respecting exclusions.
+func requireValidDraw(t *testing.T, members []string, excluded map[pair]bool, assignments map[string]string) {
+	t.Helper()
+
+	require.Len(t, assignments, len(members))
+
+	received := make(map[string]bool, len(members))
+
+	for _, giver := range members {
+		receiver, ok := assignments[giver]
+		require.True(t, ok, "member %s gives nothing", giver)
+		require.NotEqual(t, giver, receiver)
+		require.False(t, excluded[pair{giver: giver, receiver: receiver}], "excluded pair %s-%s", giver, receiver)
+		require.False(t, received[receiver], "member %s receives twice", receiver)
+
+		received[receiver] = true
+	}
+}
+
+func TestDraw(t *testing.T) {
+	t.Parallel()
+
+	group := members(6)
+
+	cases := map[string]map[pair]bool{
+		"no exclusions": {},
+		"spouses": {
+			{giver: group[0], receiver: group[1]}: true,
+			{giver: group[1], receiver: group[0]}: true,
+			{giver: group[2], receiver: group[3]}: true,
+			{giver: group[3], receiver: group[2]}: true,
+		},
+		// member-0 can only give to member-1 and member-1 only to member-2
+		"single option": {
+			{giver: group[0], receiver: group[2]}: true,
+			{giver: group[0], receiver: group[3]}: true,
+			{giver: group[0], receiver: group[4]}: true,
+			{giver: group[0], receiver: group[5]}: true,
+			{giver: group[1], receiver: group[0]}: true,
+			{giver: group[1], receiver: group[3]}: true,
+			{giver: group[1], receiver: group[4]}: true,
+			{giver: group[1], receiver: group[5]}: true,
+		},
+	}
+
+	for name, excluded := range cases {
+		excluded := excluded
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// draw is random, so it's checked several times
+			for i := 0; i < 50; i++ {
+				assignments, err := draw(group, excluded)
+				require.NoError(t, err)
+
+				requireValidDraw(t, group, excluded, assignments)
+			}
+		})
+	}
+}
+
+func TestDraw_impossible(t *testing.T) {
+	t.Parallel()
+
+	group := members(4)
+
+	cases := map[string]map[pair]bool{
+		// member-0 can't give to anyone
+		"excluded giver": {
+			{giver: group[0], receiver: group[1]}: true,
+			{giver: group[0], receiver: group[2]}: true,
+			{giver: group[0], receiver: group[3]}: true,
+		},
+		// members 1-3 can only give to member-0
+		"single receiver": {
+			{giver: group[1], receiver: group[2]}: true,
+			{giver: group[1], receiver: group[3]}: true,
+			{giver: group[2], receiver: group[1]}: true,
+			{giver: group[2], receiver: group[3]}: true,
+			{giver: group[3], receiver: group[1]}: true,
+			{giver: group[3], receiver: group[2]}: true,
+		},
+	}
+
+	for name, excluded := range cases {
+		excluded := excluded
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := draw(group, excluded)
+			require.ErrorIs(t, err, ErrNoValidDraw)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/outcatcher/anwil/domains/core/validation"
+	"github.com/outcatcher/anwil/domains/santa/service/schema"
+	"github.com/outcatcher/anwil/domains/santa/storage"
+)
+
+// AddExclusion forbids giver to be assigned to the receiver. Mutual exclusions forbid both directions.
+func (s *service) AddExclusion(ctx context.Context, ownerUUID, groupUUID string, exclusion schema.Exclusion) error {
+	group, err := s.ownedGroup(ctx, ownerUUID, groupUUID)
+	if err != nil {
+		return fmt.Errorf("error adding santa exclusion: %w", err)
+	}
+
+	if group.DrawnAt.Valid {
+		return fmt.Errorf("error adding santa exclusion: %w", errAlreadyDrawn)
+	}
+
+	if exclusion.GiverUUID == exclusion.ReceiverUUID {
+		return fmt.Errorf("%w: giver and receiver should be different members", validation.ErrValidationFailed)
+	}
+
+	for _, memberUUID := range []string{exclusion.GiverUUID, exclusion.ReceiverUUID} {
+		if _, err := s.storage.GetMember(ctx, groupUUID, memberUUID); err != nil {
+			return fmt.Errorf("%w: %s is not a group member", validation.ErrValidationFailed, memberUUID)
+		}
+	}
+
+	pairs := []storage.Pair{{GroupUUID: groupUUID, GiverUUID: exclusion.GiverUUID, ReceiverUUID: exclusion.ReceiverUUID}}
+
+	if exclusion.Mutual {
+		pairs = append(pairs, storage.Pair{
+			GroupUUID:    groupUUID,
+			GiverUUID:    exclusion.ReceiverUUID,
+			ReceiverUUID: exclusion.GiverUUID,
+		})
+	}
+
+	if err := s.storage.InsertExclusions(ctx, pairs); err != nil {
+		return fmt.Errorf("error adding santa exclusion: %w", err)
+	}
+
+	return nil
+}
+
+// ListExclusions returns exclusions of the group owned by the user.
+func (s *service) ListExclusions(ctx context.Context, ownerUUID, groupUUID string) ([]schema.Exclusion, error) {
+	if _, err := s.ownedGroup(ctx, ownerUUID, groupUUID); err != nil {
+		return nil, fmt.Errorf("error listing santa exclusions: %w", err)
+	}
+
+	stored, err := s.storage.ListExclusions(ctx, groupUUID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing santa exclusions: %w", err)
+	}
+
+	exclusions := make([]schema.Exclusion, len(stored))
+
+	for i, pair := range stored {
+		exclusions[i] = schema.Exclusion{GiverUUID: pair.GiverUUID, ReceiverUUID: pair.ReceiverUUID, Mutual: false}
+	}
+
+	return exclusions, nil
+}
+
+// RemoveExclusion removes exclusion of the group owned by the user.
+func (s *service) RemoveExclusion(ctx context.Context, ownerUUID, groupUUID string, exclusion schema.Exclusion) error {
+	group, err := s.ownedGroup(ctx, ownerUUID, groupUUID)
+	if err != nil {
+		return fmt.Errorf("error removing santa exclusion: %w", err)
+	}
+
+	if group.DrawnAt.Valid {
+		return fmt.Errorf("error removing santa exclusion: %w", errAlreadyDrawn)
+	}
+
+	err = s.storage.DeleteExclusion(ctx, storage.Pair{
+		GroupUUID:    groupUUID,
+		GiverUUID:    exclusion.GiverUUID,
+		ReceiverUUID: exclusion.ReceiverUUID,
+	})
+	if err != nil {
+		return fmt.Errorf("error removing santa exclusion: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/outcatcher/anwil/domains/santa/service/schema"
+)
+
+// exportedAssignment - assignment of the user in the personal data export.
+type exportedAssignment struct {
+	GroupUUID    string `json:"group_uuid"`
+	ReceiverUUID string `json:"receiver_uuid"`
+}
+
+// ExportUserData returns Secret Santa groups and assignments of the user for personal data export.
+//
+// Memberships are removed with the user, so there is no PrepareErasure.
+func (s *service) ExportUserData(ctx context.Context, userUUID string) (*svcSchema.UserDataExport, error) {
+	joined, err := s.ListGroups(ctx, userUUID, schema.StatusJoined)
+	if err != nil {
+		return nil, fmt.Errorf("error exporting santa groups: %w", err)
+	}
+
+	invited, err := s.ListGroups(ctx, userUUID, schema.StatusInvited)
+	if err != nil {
+		return nil, fmt.Errorf("error exporting santa groups: %w", err)
+	}
+
+	stored, err := s.storage.ListAssignments(ctx, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("error exporting santa assignments: %w", err)
+	}
+
+	assignments := make([]exportedAssignment, len(stored))
+
+	for i, assignment := range stored {
+		assignments[i] = exportedAssignment{GroupUUID: assignment.GroupUUID, ReceiverUUID: assignment.ReceiverUUID}
+	}
+
+	data := map[string]any{"groups": joined, "invites": invited, "assignments": assignments}
+
+	return &svcSchema.UserDataExport{Data: data, Files: nil}, nil
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	"github.com/outcatcher/anwil/domains/core/validation"
+	notificationsSchema "github.com/outcatcher/anwil/domains/notifications/schema"
+	"github.com/outcatcher/anwil/domains/santa/service/schema"
+	"github.com/outcatcher/anwil/domains/santa/storage"
+)
+
+const (
+	// maxMembers - max number of members and invites of single group
+	maxMembers = 100
+	// minDrawMembers - min number of joined members required for the draw
+	minDrawMembers = 3
+)
+
+var errAlreadyDrawn = fmt.Errorf("%w: names are already drawn", errbase.ErrConflict)
+
+func toGroup(group *storage.Group) *schema.Group {
+	result := &schema.Group{
+		UUID:      group.UUID,
+		Name:      group.Name,
+		OwnerUUID: group.OwnerUUID,
+		DrawnAt:   nil,
+		CreatedAt: group.CreatedAt,
+		Members:   nil,
+	}
+
+	if group.DrawnAt.Valid {
+		drawnAt := group.DrawnAt.Time
+		result.DrawnAt = &drawnAt
+	}
+
+	return result
+}
+
+// toMember returns member with the user profile data.
+func (s *service) toMember(ctx context.Context, userUUID, status string) (*schema.Member, error) {
+	user, err := s.users.GetUserByUUID(ctx, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting santa group member: %w", err)
+	}
+
+	return &schema.Member{UUID: user.UUID, Username: user.Username, FullName: user.FullName, Status: status}, nil
+}
+
+// memberGroup returns group if the user is invited to it or joined it.
+func (s *service) memberGroup(
+	ctx context.Context, userUUID, groupUUID string,
+) (*storage.Group, *storage.Member, error) {
+	group, err := s.storage.GetGroup(ctx, groupUUID)
+	if err != nil {
+		return nil, nil, err //nolint:wrapcheck
+	}
+
+	member, err := s.storage.GetMember(ctx, groupUUID, userUUID)
+	if err != nil {
+		// groups of other users are indistinguishable from missing ones
+		return nil, nil, fmt.Errorf("santa group %s: %w", groupUUID, errbase.ErrNotFound)
+	}
+
+	return group, member, nil
+}
+
+// ownedGroup returns group if the user owns it.
+func (s *service) ownedGroup(ctx context.Context, ownerUUID, groupUUID string) (*storage.Group, error) {
+	group, _, err := s.memberGroup(ctx, ownerUUID, groupUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if group.OwnerUUID != ownerUUID {
+		return nil, fmt.Errorf("%w: only group owner can manage the group", errbase.ErrForbidden)
+	}
+
+	return group, nil
+}
+
+// CreateGroup creates new group owned by the user. The owner is the first joined member.
+func (s *service) CreateGroup(ctx context.Context, ownerUUID string, input schema.GroupInput) (*schema.Group, error) {
+	if input.PreviousGroupUUID != "" {
+		previous, err := s.ownedGroup(ctx, ownerUUID, input.PreviousGroupUUID)
+		if err != nil {
+			return nil, fmt.Errorf("error creating santa group: %w", err)
+		}
+
+		if !previous.DrawnAt.Valid {
+			return nil, fmt.Errorf("%w: names in previous group are not drawn yet", validation.ErrValidationFailed)
+		}
+	}
+
+	created, err := s.storage.InsertGroup(ctx, storage.Group{ //nolint:exhaustruct
+		OwnerUUID: ownerUUID,
+		Name:      input.Name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating santa group: %w", err)
+	}
+
+	if input.PreviousGroupUUID != "" {
+		if err := s.storage.CopyExclusions(ctx, input.PreviousGroupUUID, created.UUID); err != nil {
+			return nil, fmt.Errorf("error creating santa group: %w", err)
+		}
+	}
+
+	return toGroup(created), nil
+}
+
+// ListGroups returns groups the user is invited to or joined, newest first.
+func (s *service) ListGroups(ctx context.Context, userUUID, status string) ([]schema.Group, error) {
+	if status != schema.StatusInvited && status != schema.StatusJoined {
+		return nil, fmt.Errorf("%w: unknown member status %s", validation.ErrValidationFailed, status)
+	}
+
+	stored, err := s.storage.ListGroups(ctx, userUUID, status)
+	if err != nil {
+		return nil, fmt.Errorf("error listing santa groups: %w", err)
+	}
+
+	groups := make([]schema.Group, len(stored))
+
+	for i := range stored {
+		groups[i] = *toGroup(&stored[i])
+	}
+
+	return groups, nil
+}
+
+// GetGroup returns group with members. Group is visible to its members and invited users only.
+func (s *service) GetGroup(ctx context.Context, userUUID, groupUUID string) (*schema.Group, error) {
+	stored, _, err := s.memberGroup(ctx, userUUID, groupUUID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting santa group: %w", err)
+	}
+
+	members, err := s.storage.ListMembers(ctx, groupUUID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting santa group: %w", err)
+	}
+
+	group := toGroup(stored)
+	group.Members = make([]schema.Member, 0, len(members))
+
+	for _, member := range members {
+		converted, err := s.toMember(ctx, member.WisherUUID, member.Status)
+		if err != nil {
+			return nil, fmt.Errorf("error getting santa group: %w", err)
+		}
+
+		group.Members = append(group.Members, *converted)
+	}
+
+	return group, nil
+}
+
+// DeleteGroup removes group owned by the user.
+func (s *service) DeleteGroup(ctx context.Context, ownerUUID, groupUUID string) error {
+	if _, err := s.ownedGroup(ctx, ownerUUID, groupUUID); err != nil {
+		return fmt.Errorf("error deleting santa group: %w", err)
+	}
+
+	if err := s.storage.DeleteGroup(ctx, groupUUID); err != nil {
+		return fmt.Errorf("error deleting santa group: %w", err)
+	}
+
+	return nil
+}
+
+// InviteMember invites user to the group owned by another user.
+func (s *service) InviteMember(ctx context.Context, ownerUUID, groupUUID, username string) (*schema.Member, error) {
+	group, err := s.ownedGroup(ctx, ownerUUID, groupUUID)
+	if err != nil {
+		return nil, fmt.Errorf("error inviting santa group member: %w", err)
+	}
+
+	if group.DrawnAt.Valid {
+		return nil, fmt.Errorf("error inviting santa group member: %w", errAlreadyDrawn)
+	}
+
+	user, err := s.users.GetUser(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("error inviting santa group member: %w", err)
+	}
+
+	members, err := s.storage.ListMembers(ctx, groupUUID)
+	if err != nil {
+		return nil, fmt.Errorf("error inviting santa group member: %w", err)
+	}
+
+	for _, member := range members {
+		if member.WisherUUID == user.UUID {
+			return nil, fmt.Errorf("%w: user %s is already %s", errbase.ErrConflict, username, member.Status)
+		}
+	}
+
+	if len(members) >= maxMembers {
+		return nil, fmt.Errorf("%w: no more than %d members are allowed", errbase.ErrConflict, maxMembers)
+	}
+
+	err = s.storage.InsertMember(ctx, storage.Member{ //nolint:exhaustruct
+		GroupUUID:  groupUUID,
+		WisherUUID: user.UUID,
+		Status:     storage.StatusInvited,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error inviting santa group member: %w", err)
+	}
+
+	err = s.notifier.Notify(ctx, notificationsSchema.Notification{
+		UserUUID: user.UUID,
+		Type:     notificationsSchema.TypeSantaInvite,
+		Title:    "You are invited to Secret Santa",
+		Body:     fmt.Sprintf("You are invited to join Secret Santa group %q.", group.Name),
+		Link:     s.cfg.API.BaseURL() + "/api/v1/santa/groups/" + groupUUID,
+		Data:     map[string]any{"group_uuid": groupUUID},
+	})
+	if err != nil {
+		// invite is visible in the list of invites anyway
+		s.log.Printf("error notifying user %s about santa group %s invite: %s", user.UUID, groupUUID, err)
+	}
+
+	return &schema.Member{
+		UUID:     user.UUID,
+		Username: user.Username,
+		FullName: user.FullName,
+		Status:   schema.StatusInvited,
+	}, nil
+}
+
+// JoinGroup accepts invite to the group.
+func (s *service) JoinGroup(ctx context.Context, userUUID, groupUUID string) error {
+	group, member, err := s.memberGroup(ctx, userUUID, groupUUID)
+	if err != nil {
+		return fmt.Errorf("error joining santa group: %w", err)
+	}
+
+	if group.DrawnAt.Valid {
+		return fmt.Errorf("error joining santa group: %w", errAlreadyDrawn)
+	}
+
+	if member.Status == storage.StatusJoined {
+		return nil
+	}
+
+	if err := s.storage.UpdateMemberStatus(ctx, groupUUID, userUUID, storage.StatusJoined); err != nil {
+		return fmt.Errorf("error joining santa group: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveMember removes member or invite from the group. Owner can remove anyone but themselves,
+// other members can remove themselves only, leaving the group or declining the invite.
+func (s *service) RemoveMember(ctx context.Context, userUUID, groupUUID, memberUUID string) error {
+	group, _, err := s.memberGroup(ctx, userUUID, groupUUID)
+	if err != nil {
+		return fmt.Errorf("error removing santa group member: %w", err)
+	}
+
+	if memberUUID == group.OwnerUUID {
+		return fmt.Errorf("%w: owner can't leave the group, delete the group instead", validation.ErrValidationFailed)
+	}
+
+	if userUUID != group.OwnerUUID && userUUID != memberUUID {
+		return fmt.Errorf("%w: only group owner can remove other members", errbase.ErrForbidden)
+	}
+
+	if group.DrawnAt.Valid {
+		return fmt.Errorf("error removing santa group member: %w", errAlreadyDrawn)
+	}
+
+	if _, err := s.storage.GetMember(ctx, groupUUID, memberUUID); err != nil {
+		return fmt.Errorf("error removing santa group member: %w", err)
+	}
+
+	if err := s.storage.DeleteMember(ctx, groupUUID, memberUUID); err != nil {
+		return fmt.Errorf("error removing santa group member: %w", err)
+	}
+
+	return nil
+}
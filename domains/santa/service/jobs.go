@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/outcatcher/anwil/domains/core/services"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/outcatcher/anwil/domains/jobs"
+	notificationsSchema "github.com/outcatcher/anwil/domains/notifications/schema"
+	"github.com/outcatcher/anwil/domains/santa/service/schema"
+	"github.com/outcatcher/anwil/domains/santa/storage"
+)
+
+const notifyDrawJobKind = "santa.notify_draw"
+
+// notifyDrawPayload - payload of the job notifying group members about the draw.
+type notifyDrawPayload struct {
+	GroupUUID string `json:"group_uuid"`
+}
+
+// addSantaJobs registers Secret Santa service jobs.
+func addSantaJobs(state svcSchema.ProvidingServices) svcSchema.AddJobsFunc {
+	return func(registry svcSchema.JobRegistry) error {
+		svc, err := services.GetServiceFromProvider[*service](state, schema.ServiceID)
+		if err != nil {
+			return fmt.Errorf("error adding santa jobs: %w", err)
+		}
+
+		if err := registry.Handle(notifyDrawJobKind, jobs.Typed(svc.notifyDraw)); err != nil {
+			return fmt.Errorf("error adding santa jobs: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// notifyDraw notifies joined group members that their assignments are ready.
+//
+// Notification failures are logged only, so retries don't notify the same members twice.
+func (s *service) notifyDraw(ctx context.Context, payload notifyDrawPayload) error {
+	group, err := s.storage.GetGroup(ctx, payload.GroupUUID)
+	if err != nil {
+		return fmt.Errorf("error notifying santa group members: %w", err)
+	}
+
+	members, err := s.storage.ListMembers(ctx, group.UUID)
+	if err != nil {
+		return fmt.Errorf("error notifying santa group members: %w", err)
+	}
+
+	for _, member := range members {
+		if member.Status != storage.StatusJoined {
+			continue
+		}
+
+		err := s.notifier.Notify(ctx, notificationsSchema.Notification{
+			UserUUID: member.WisherUUID,
+			Type:     notificationsSchema.TypeSantaDrawn,
+			Title:    "Secret Santa names are drawn",
+			Body: fmt.Sprintf(
+				"Names are drawn in Secret Santa group %q. Find out who you are giving a gift to.", group.Name,
+			),
+			Link: s.cfg.API.BaseURL() + "/api/v1/santa/groups/" + group.UUID + "/assignment",
+			Data: map[string]any{"group_uuid": group.UUID},
+		})
+		if err != nil {
+			s.log.Printf("error notifying user %s about santa group %s draw: %s", member.WisherUUID, group.UUID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+/*
+Package schema contains service definition for Secret Santa service
+*/
+package schema
+
+import (
+	"context"
+	"time"
+
+	"github.com/outcatcher/anwil/domains/core/services/schema"
+)
+
+// ServiceID - ID for Secret Santa service.
+const ServiceID schema.ServiceID = "santa"
+
+// Member statuses.
+const (
+	StatusInvited = "invited"
+	StatusJoined  = "joined"
+)
+
+// SantaService - service handling Secret Santa groups and draws.
+type SantaService interface {
+	// CreateGroup creates new group owned by the user. The owner is the first joined member.
+	CreateGroup(ctx context.Context, ownerUUID string, input GroupInput) (*Group, error)
+	// ListGroups returns groups the user is invited to or joined, newest first.
+	ListGroups(ctx context.Context, userUUID, status string) ([]Group, error)
+	// GetGroup returns group with members. Group is visible to its members and invited users only.
+	GetGroup(ctx context.Context, userUUID, groupUUID string) (*Group, error)
+	// DeleteGroup removes group owned by the user.
+	DeleteGroup(ctx context.Context, ownerUUID, groupUUID string) error
+
+	// InviteMember invites user to the group owned by another user.
+	InviteMember(ctx context.Context, ownerUUID, groupUUID, username string) (*Member, error)
+	// JoinGroup accepts invite to the group.
+	JoinGroup(ctx context.Context, userUUID, groupUUID string) error
+	// RemoveMember removes member or invite from the group. Owner can remove anyone but themselves,
+	// other members can remove themselves only, leaving the group or declining the invite.
+	RemoveMember(ctx context.Context, userUUID, groupUUID, memberUUID string) error
+
+	// AddExclusion forbids giver to be assigned to the receiver. Mutual exclusions forbid both directions.
+	AddExclusion(ctx context.Context, ownerUUID, groupUUID string, exclusion Exclusion) error
+	// ListExclusions returns exclusions of the group owned by the user.
+	ListExclusions(ctx context.Context, ownerUUID, groupUUID string) ([]Exclusion, error)
+	// RemoveExclusion removes exclusion of the group owned by the user.
+	RemoveExclusion(ctx context.Context, ownerUUID, groupUUID string, exclusion Exclusion) error
+
+	// Draw assigns a receiver to each joined member respecting the exclusions. Pending invites are dropped.
+	Draw(ctx context.Context, ownerUUID, groupUUID string) error
+	// GetAssignment returns receiver assigned to the user in the group.
+	GetAssignment(ctx context.Context, userUUID, groupUUID string) (*Member, error)
+}
+
+// GroupInput - attributes of the new group.
+type GroupInput struct {
+	Name string
+	// PreviousGroupUUID is an optional drawn group of the same owner,
+	// its assignments and exclusions are added as exclusions of the new group
+	PreviousGroupUUID string
+}
+
+// Group - Secret Santa group.
+type Group struct {
+	UUID      string     `json:"uuid"`
+	Name      string     `json:"name"`
+	OwnerUUID string     `json:"owner_uuid"`
+	DrawnAt   *time.Time `json:"drawn_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	// Members are returned for single group only
+	Members []Member `json:"members,omitempty"`
+}
+
+// Member - group member.
+type Member struct {
+	UUID     string `json:"uuid"`
+	Username string `json:"username"`
+	FullName string `json:"full_name"`
+	Status   string `json:"status,omitempty"`
+}
+
+// Exclusion - pair of members which can't be assigned.
+type Exclusion struct {
+	GiverUUID    string `json:"giver_uuid"`
+	ReceiverUUID string `json:"receiver_uuid"`
+	// Mutual is used on creation only
+	Mutual bool `json:"-"`
+}
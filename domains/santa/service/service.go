@@ -0,0 +1,93 @@
+/*
+Package service contains Secret Santa service methods
+*/
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
+	logSchema "github.com/outcatcher/anwil/domains/core/logging/schema"
+	"github.com/outcatcher/anwil/domains/core/services"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	jobsSchema "github.com/outcatcher/anwil/domains/jobs/schema"
+	notificationsSchema "github.com/outcatcher/anwil/domains/notifications/schema"
+	"github.com/outcatcher/anwil/domains/santa/handlers"
+	"github.com/outcatcher/anwil/domains/santa/service/schema"
+	santaStorage "github.com/outcatcher/anwil/domains/santa/storage"
+	storageSchema "github.com/outcatcher/anwil/domains/storage/schema"
+	usersSchema "github.com/outcatcher/anwil/domains/users/service/schema"
+)
+
+// service - Secret Santa service.
+type service struct {
+	cfg     *configSchema.Configuration
+	storage santaStorage.SantaStorage
+
+	log      *log.Logger
+	queue    jobsSchema.Queue
+	notifier notificationsSchema.Notifier
+	users    usersSchema.UserFinder
+}
+
+// UseConfig attaches configuration to the service.
+func (s *service) UseConfig(configuration *configSchema.Configuration) {
+	s.cfg = configuration
+}
+
+// UseStorage attaches given DB storage to the service.
+func (s *service) UseStorage(db storageSchema.QueryExecutor) {
+	s.storage = santaStorage.New(db)
+}
+
+// UseLogger attaches logger to the service.
+func (s *service) UseLogger(logger *log.Logger) {
+	s.log = logger
+}
+
+// UseJobQueue attaches background job queue to the service.
+func (s *service) UseJobQueue(queue jobsSchema.Queue) {
+	s.queue = queue
+}
+
+// UseNotifier attaches notifier to the service.
+func (s *service) UseNotifier(notifier notificationsSchema.Notifier) {
+	s.notifier = notifier
+}
+
+// UseUserFinder attaches users lookup.
+func (s *service) UseUserFinder(finder usersSchema.UserFinder) {
+	s.users = finder
+}
+
+func santaServiceInit(_ context.Context, state any) (any, error) {
+	svc := new(service)
+
+	err := services.InjectServiceWith(
+		svc, state,
+		storageSchema.StorageInject,
+		logSchema.LoggerInject,
+		configSchema.ConfigInject,
+		jobsSchema.JobQueueInject,
+		notificationsSchema.NotifierInject,
+		usersSchema.UserFinderInject,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing santa service: %w", err)
+	}
+
+	return svc, nil
+}
+
+// NewSantaService returns new Secret Santa service definition.
+func NewSantaService() svcSchema.ServiceDefinition {
+	return svcSchema.ServiceDefinition{
+		ID:               schema.ServiceID,
+		Init:             santaServiceInit,
+		DependsOn:        []svcSchema.ServiceID{usersSchema.ServiceID},
+		InitHandlersFunc: handlers.AddSantaHandlers,
+		InitJobsFunc:     addSantaJobs,
+	}
+}
@@ -0,0 +1,403 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"testing"
+	"time"
+
+	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	th "github.com/outcatcher/anwil/domains/core/testhelpers"
+	"github.com/outcatcher/anwil/domains/core/validation"
+	notificationsSchema "github.com/outcatcher/anwil/domains/notifications/schema"
+	"github.com/outcatcher/anwil/domains/santa/service/schema"
+	"github.com/outcatcher/anwil/domains/santa/storage"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockStorage struct {
+	mock.Mock
+}
+
+func (m *mockStorage) InsertGroup(ctx context.Context, group storage.Group) (*storage.Group, error) {
+	args := m.Called(ctx, group)
+
+	return args.Get(0).(*storage.Group), args.Error(1) //nolint:forcetypeassert
+}
+
+func (m *mockStorage) GetGroup(ctx context.Context, uuid string) (*storage.Group, error) {
+	args := m.Called(ctx, uuid)
+
+	return args.Get(0).(*storage.Group), args.Error(1) //nolint:forcetypeassert
+}
+
+func (m *mockStorage) ListGroups(ctx context.Context, wisherUUID, status string) ([]storage.Group, error) {
+	args := m.Called(ctx, wisherUUID, status)
+
+	return args.Get(0).([]storage.Group), args.Error(1) //nolint:forcetypeassert
+}
+
+func (m *mockStorage) DeleteGroup(ctx context.Context, uuid string) error {
+	return m.Called(ctx, uuid).Error(0)
+}
+
+func (m *mockStorage) InsertMember(ctx context.Context, member storage.Member) error {
+	return m.Called(ctx, member).Error(0)
+}
+
+func (m *mockStorage) GetMember(ctx context.Context, groupUUID, wisherUUID string) (*storage.Member, error) {
+	args := m.Called(ctx, groupUUID, wisherUUID)
+
+	return args.Get(0).(*storage.Member), args.Error(1) //nolint:forcetypeassert
+}
+
+func (m *mockStorage) ListMembers(ctx context.Context, groupUUID string) ([]storage.Member, error) {
+	args := m.Called(ctx, groupUUID)
+
+	return args.Get(0).([]storage.Member), args.Error(1) //nolint:forcetypeassert
+}
+
+func (m *mockStorage) UpdateMemberStatus(ctx context.Context, groupUUID, wisherUUID, status string) error {
+	return m.Called(ctx, groupUUID, wisherUUID, status).Error(0)
+}
+
+func (m *mockStorage) DeleteMember(ctx context.Context, groupUUID, wisherUUID string) error {
+	return m.Called(ctx, groupUUID, wisherUUID).Error(0)
+}
+
+func (m *mockStorage) InsertExclusions(ctx context.Context, exclusions []storage.Pair) error {
+	return m.Called(ctx, exclusions).Error(0)
+}
+
+func (m *mockStorage) CopyExclusions(ctx context.Context, sourceGroupUUID, targetGroupUUID string) error {
+	return m.Called(ctx, sourceGroupUUID, targetGroupUUID).Error(0)
+}
+
+func (m *mockStorage) ListExclusions(ctx context.Context, groupUUID string) ([]storage.Pair, error) {
+	args := m.Called(ctx, groupUUID)
+
+	return args.Get(0).([]storage.Pair), args.Error(1) //nolint:forcetypeassert
+}
+
+func (m *mockStorage) DeleteExclusion(ctx context.Context, exclusion storage.Pair) error {
+	return m.Called(ctx, exclusion).Error(0)
+}
+
+func (m *mockStorage) SaveDraw(ctx context.Context, groupUUID string, assignments []storage.Pair) error {
+	return m.Called(ctx, groupUUID, assignments).Error(0)
+}
+
+func (m *mockStorage) GetAssignment(ctx context.Context, groupUUID, giverUUID string) (*storage.Pair, error) {
+	args := m.Called(ctx, groupUUID, giverUUID)
+
+	return args.Get(0).(*storage.Pair), args.Error(1) //nolint:forcetypeassert
+}
+
+func (m *mockStorage) ListAssignments(ctx context.Context, giverUUID string) ([]storage.Pair, error) {
+	args := m.Called(ctx, giverUUID)
+
+	return args.Get(0).([]storage.Pair), args.Error(1) //nolint:forcetypeassert
+}
+
+const (
+	groupUUID = "group"
+	ownerUUID = "owner"
+)
+
+func newTestService(store storage.SantaStorage) *service {
+	return &service{
+		cfg: &configSchema.Configuration{ //nolint:exhaustruct
+			API: configSchema.APIConfiguration{PublicURL: "https://anwil.example.com"}, //nolint:exhaustruct
+		},
+		storage:  store,
+		log:      log.Default(),
+		queue:    new(th.MockQueue),
+		notifier: new(th.MockNotifier),
+		users:    new(th.FakeUsers),
+	}
+}
+
+// groupStorage returns storage mock with the group and given members.
+func groupStorage(drawn bool, members ...storage.Member) *mockStorage {
+	ctx := context.Background()
+
+	store := new(mockStorage)
+	store.
+		On("GetGroup", ctx, groupUUID).
+		Return(&storage.Group{ //nolint:exhaustruct
+			UUID:      groupUUID,
+			OwnerUUID: ownerUUID,
+			Name:      "Office",
+			DrawnAt:   sql.NullTime{Time: time.Now(), Valid: drawn},
+		}, nil)
+	store.On("ListMembers", ctx, groupUUID).Return(members, nil)
+	for i := range members {
+		store.
+			On("GetMember", ctx, groupUUID, members[i].WisherUUID).
+			Return(&members[i], nil)
+	}
+
+	store.
+		On("GetMember", ctx, groupUUID, mock.MatchedBy(func(uuid string) bool {
+			for _, member := range members {
+				if member.WisherUUID == uuid {
+					return false
+				}
+			}
+
+			return true
+		})).
+		Return((*storage.Member)(nil), errbase.ErrNotFound)
+
+	return store
+}
+
+func joined(uuids ...string) []storage.Member {
+	members := make([]storage.Member, len(uuids))
+
+	for i, uuid := range uuids {
+		members[i] = storage.Member{GroupUUID: groupUUID, WisherUUID: uuid, Status: storage.StatusJoined} //nolint:exhaustruct
+	}
+
+	return members
+}
+
+func TestCreateGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("from previous group", func(t *testing.T) {
+		t.Parallel()
+
+		store := groupStorage(true, joined(ownerUUID)...)
+		store.
+			On("InsertGroup", ctx, storage.Group{OwnerUUID: ownerUUID, Name: "Office 2024"}).   //nolint:exhaustruct
+			Return(&storage.Group{UUID: "new", OwnerUUID: ownerUUID, Name: "Office 2024"}, nil) //nolint:exhaustruct
+		store.On("CopyExclusions", ctx, groupUUID, "new").Return(nil)
+
+		group, err := newTestService(store).CreateGroup(ctx, ownerUUID, schema.GroupInput{
+			Name:              "Office 2024",
+			PreviousGroupUUID: groupUUID,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "new", group.UUID)
+
+		store.AssertNumberOfCalls(t, "CopyExclusions", 1)
+	})
+
+	t.Run("previous group not drawn", func(t *testing.T) {
+		t.Parallel()
+
+		store := groupStorage(false, joined(ownerUUID)...)
+
+		_, err := newTestService(store).CreateGroup(ctx, ownerUUID, schema.GroupInput{
+			Name:              "Office 2024",
+			PreviousGroupUUID: groupUUID,
+		})
+		require.ErrorIs(t, err, validation.ErrValidationFailed)
+
+		store.AssertNotCalled(t, "InsertGroup")
+	})
+}
+
+func TestGetGroup_notMember(t *testing.T) {
+	t.Parallel()
+
+	_, err := newTestService(groupStorage(false, joined(ownerUUID)...)).GetGroup(context.Background(), "other", groupUUID)
+	require.ErrorIs(t, err, errbase.ErrNotFound)
+}
+
+func TestInviteMember(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		store := groupStorage(false, joined(ownerUUID)...)
+		store.
+			On("InsertMember", ctx, storage.Member{ //nolint:exhaustruct
+				GroupUUID:  groupUUID,
+				WisherUUID: "john",
+				Status:     storage.StatusInvited,
+			}).
+			Return(nil)
+
+		notifier := new(th.MockNotifier)
+		notifier.
+			On("Notify", ctx, mock.MatchedBy(func(notification notificationsSchema.Notification) bool {
+				return notification.UserUUID == "john" && notification.Type == notificationsSchema.TypeSantaInvite
+			})).
+			Return(nil)
+
+		svc := newTestService(store)
+		svc.notifier = notifier
+
+		member, err := svc.InviteMember(ctx, ownerUUID, groupUUID, "john")
+		require.NoError(t, err)
+		require.Equal(t, schema.StatusInvited, member.Status)
+
+		notifier.AssertNumberOfCalls(t, "Notify", 1)
+	})
+
+	t.Run("already member", func(t *testing.T) {
+		t.Parallel()
+
+		store := groupStorage(false, joined(ownerUUID, "john")...)
+
+		_, err := newTestService(store).InviteMember(ctx, ownerUUID, groupUUID, "john")
+		require.ErrorIs(t, err, errbase.ErrConflict)
+	})
+
+	t.Run("not owner", func(t *testing.T) {
+		t.Parallel()
+
+		store := groupStorage(false, joined(ownerUUID, "john")...)
+
+		_, err := newTestService(store).InviteMember(ctx, "john", groupUUID, "jane")
+		require.ErrorIs(t, err, errbase.ErrForbidden)
+	})
+
+	t.Run("drawn", func(t *testing.T) {
+		t.Parallel()
+
+		store := groupStorage(true, joined(ownerUUID)...)
+
+		_, err := newTestService(store).InviteMember(ctx, ownerUUID, groupUUID, "john")
+		require.ErrorIs(t, err, errbase.ErrConflict)
+	})
+}
+
+func TestRemoveMember(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	store := groupStorage(false, joined(ownerUUID, "john", "jane")...)
+	store.On("DeleteMember", ctx, groupUUID, "john").Return(nil)
+
+	svc := newTestService(store)
+
+	require.ErrorIs(t, svc.RemoveMember(ctx, ownerUUID, groupUUID, ownerUUID), validation.ErrValidationFailed)
+	require.ErrorIs(t, svc.RemoveMember(ctx, "jane", groupUUID, "john"), errbase.ErrForbidden)
+	require.NoError(t, svc.RemoveMember(ctx, "john", groupUUID, "john"), "member can't leave")
+
+	store.AssertNumberOfCalls(t, "DeleteMember", 1)
+}
+
+func TestAddExclusion(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	store := groupStorage(false, joined(ownerUUID, "john", "jane")...)
+	store.
+		On("InsertExclusions", ctx, []storage.Pair{
+			{GroupUUID: groupUUID, GiverUUID: "john", ReceiverUUID: "jane"},
+			{GroupUUID: groupUUID, GiverUUID: "jane", ReceiverUUID: "john"},
+		}).
+		Return(nil)
+
+	svc := newTestService(store)
+
+	spouses := schema.Exclusion{GiverUUID: "john", ReceiverUUID: "jane", Mutual: true}
+	require.NoError(t, svc.AddExclusion(ctx, ownerUUID, groupUUID, spouses))
+
+	stranger := schema.Exclusion{GiverUUID: "john", ReceiverUUID: "bob", Mutual: false}
+	err := svc.AddExclusion(ctx, ownerUUID, groupUUID, stranger)
+	require.ErrorIs(t, err, validation.ErrValidationFailed, "exclusion with not a member is added")
+
+	store.AssertNumberOfCalls(t, "InsertExclusions", 1)
+}
+
+func TestDrawGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		members := append(joined(ownerUUID, "john", "jane"), storage.Member{ //nolint:exhaustruct
+			GroupUUID:  groupUUID,
+			WisherUUID: "invited",
+			Status:     storage.StatusInvited,
+		})
+
+		store := groupStorage(false, members...)
+		store.
+			On("ListExclusions", ctx, groupUUID).
+			Return([]storage.Pair{{GroupUUID: groupUUID, GiverUUID: ownerUUID, ReceiverUUID: "john"}}, nil)
+		store.
+			On("SaveDraw", ctx, groupUUID, mock.AnythingOfType("[]storage.Pair")).
+			Return(nil)
+
+		queue := new(th.MockQueue)
+		queue.On("Enqueue", ctx, notifyDrawJobKind, notifyDrawPayload{GroupUUID: groupUUID}).Return(nil)
+
+		svc := newTestService(store)
+		svc.queue = queue
+
+		require.NoError(t, svc.Draw(ctx, ownerUUID, groupUUID))
+
+		saved := store.Calls[len(store.Calls)-1].Arguments.Get(2).([]storage.Pair) //nolint:forcetypeassert
+		assignments := make(map[string]string, len(saved))
+
+		for _, assignment := range saved {
+			assignments[assignment.GiverUUID] = assignment.ReceiverUUID
+		}
+
+		// the only valid assignment with owner not giving to john
+		require.Equal(t, map[string]string{ownerUUID: "jane", "jane": "john", "john": ownerUUID}, assignments)
+
+		queue.AssertNumberOfCalls(t, "Enqueue", 1)
+	})
+
+	t.Run("not enough members", func(t *testing.T) {
+		t.Parallel()
+
+		store := groupStorage(false, joined(ownerUUID, "john")...)
+
+		require.ErrorIs(t, newTestService(store).Draw(ctx, ownerUUID, groupUUID), validation.ErrValidationFailed)
+	})
+
+	t.Run("impossible", func(t *testing.T) {
+		t.Parallel()
+
+		store := groupStorage(false, joined(ownerUUID, "john", "jane")...)
+		store.
+			On("ListExclusions", ctx, groupUUID).
+			Return([]storage.Pair{
+				{GroupUUID: groupUUID, GiverUUID: ownerUUID, ReceiverUUID: "john"},
+				{GroupUUID: groupUUID, GiverUUID: ownerUUID, ReceiverUUID: "jane"},
+			}, nil)
+
+		require.ErrorIs(t, newTestService(store).Draw(ctx, ownerUUID, groupUUID), ErrNoValidDraw)
+
+		store.AssertNotCalled(t, "SaveDraw")
+	})
+}
+
+func TestGetAssignment(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	store := groupStorage(true, joined(ownerUUID, "john", "jane")...)
+	store.
+		On("GetAssignment", ctx, groupUUID, "john").
+		Return(&storage.Pair{GroupUUID: groupUUID, GiverUUID: "john", ReceiverUUID: "jane"}, nil)
+
+	receiver, err := newTestService(store).GetAssignment(ctx, "john", groupUUID)
+	require.NoError(t, err)
+	require.Equal(t, "jane", receiver.UUID)
+	require.Equal(t, "jane", receiver.Username)
+
+	_, err = newTestService(groupStorage(false, joined(ownerUUID, "john")...)).GetAssignment(ctx, "john", groupUUID)
+	require.ErrorIs(t, err, errbase.ErrNotFound)
+}
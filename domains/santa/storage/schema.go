@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Member statuses.
+const (
+	StatusInvited = "invited"
+	StatusJoined  = "joined"
+)
+
+// Group - entity of `santa_groups` table.
+type Group struct {
+	UUID      string       `db:"uuid"`
+	OwnerUUID string       `db:"owner_uuid"`
+	Name      string       `db:"name"`
+	DrawnAt   sql.NullTime `db:"drawn_at"`
+	CreatedAt time.Time    `db:"created_at"`
+}
+
+// Member - entity of `santa_members` table.
+type Member struct {
+	GroupUUID  string    `db:"group_uuid"`
+	WisherUUID string    `db:"wisher_uuid"`
+	Status     string    `db:"status"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// Pair - entity of `santa_exclusions` and `santa_assignments` tables.
+type Pair struct {
+	GroupUUID    string `db:"group_uuid"`
+	GiverUUID    string `db:"giver_uuid"`
+	ReceiverUUID string `db:"receiver_uuid"`
+}
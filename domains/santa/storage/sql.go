@@ -0,0 +1,404 @@
+/*
+Package storage contains db-related operations with Secret Santa groups.
+*/
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	storageSchema "github.com/outcatcher/anwil/domains/storage/schema"
+)
+
+// santaStorage - storage of Secret Santa groups.
+type santaStorage struct {
+	db storageSchema.QueryExecutor
+}
+
+// New creates a new SantaStorage instance.
+func New(db storageSchema.QueryExecutor) SantaStorage {
+	return &santaStorage{db: db}
+}
+
+// InsertGroup creates new group with the owner as a joined member.
+func (s *santaStorage) InsertGroup(ctx context.Context, group Group) (*Group, error) {
+	inserted := new(Group)
+
+	err := s.db.GetContext(
+		ctx,
+		inserted,
+		`WITH created AS (
+		     INSERT INTO santa_groups (owner_uuid, name) VALUES ($1, $2) RETURNING *
+		 ), owner AS (
+		     INSERT INTO santa_members (group_uuid, wisher_uuid, status)
+		     SELECT uuid, owner_uuid, 'joined' FROM created
+		 )
+		 SELECT * FROM created;`,
+		group.OwnerUUID, group.Name,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting santa group: %w", err)
+	}
+
+	return inserted, nil
+}
+
+// GetGroup returns single group by UUID.
+func (s *santaStorage) GetGroup(ctx context.Context, uuid string) (*Group, error) {
+	group := new(Group)
+
+	err := s.db.GetContext(ctx, group, `SELECT * FROM santa_groups WHERE uuid = $1;`, uuid)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("no santa group found: %w", errbase.ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error selecting santa group: %w", err)
+	}
+
+	return group, nil
+}
+
+// ListGroups returns groups where the user has given member status, newest first.
+func (s *santaStorage) ListGroups(ctx context.Context, wisherUUID, status string) ([]Group, error) {
+	var groups []Group
+
+	err := sqlx.SelectContext(
+		ctx, s.db, &groups,
+		`SELECT g.* FROM santa_groups g
+		 JOIN santa_members m ON m.group_uuid = g.uuid
+		 WHERE m.wisher_uuid = $1 AND m.status = $2
+		 ORDER BY g.created_at DESC;`,
+		wisherUUID, status,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting santa groups: %w", err)
+	}
+
+	return groups, nil
+}
+
+// DeleteGroup removes the group with all its members, exclusions and assignments.
+func (s *santaStorage) DeleteGroup(ctx context.Context, uuid string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM santa_groups WHERE uuid = $1;`, uuid)
+	if err != nil {
+		return fmt.Errorf("error deleting santa group: %w", err)
+	}
+
+	return nil
+}
+
+// InsertMember adds member to the group, existing members are not changed.
+func (s *santaStorage) InsertMember(ctx context.Context, member Member) error {
+	_, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO santa_members (group_uuid, wisher_uuid, status) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING;`,
+		member.GroupUUID, member.WisherUUID, member.Status,
+	)
+	if err != nil {
+		return fmt.Errorf("error inserting santa group member: %w", err)
+	}
+
+	return nil
+}
+
+// GetMember returns single group member.
+func (s *santaStorage) GetMember(ctx context.Context, groupUUID, wisherUUID string) (*Member, error) {
+	member := new(Member)
+
+	err := s.db.GetContext(
+		ctx, member,
+		`SELECT * FROM santa_members WHERE group_uuid = $1 AND wisher_uuid = $2;`,
+		groupUUID, wisherUUID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("no santa group member found: %w", errbase.ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error selecting santa group member: %w", err)
+	}
+
+	return member, nil
+}
+
+// ListMembers returns members of the group, oldest first.
+func (s *santaStorage) ListMembers(ctx context.Context, groupUUID string) ([]Member, error) {
+	var members []Member
+
+	err := sqlx.SelectContext(
+		ctx, s.db, &members,
+		`SELECT * FROM santa_members WHERE group_uuid = $1 ORDER BY created_at;`,
+		groupUUID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting santa group members: %w", err)
+	}
+
+	return members, nil
+}
+
+// requireNotDrawn returns ErrConflict if member change is not applied because the group is drawn.
+func requireNotDrawn(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting affected rows: %w", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%w: names are already drawn", errbase.ErrConflict)
+	}
+
+	return nil
+}
+
+// UpdateMemberStatus changes status of the group member unless names are drawn.
+//
+// Group is locked by the same statement, so the change can't be missed by the concurrent draw.
+func (s *santaStorage) UpdateMemberStatus(ctx context.Context, groupUUID, wisherUUID, status string) error {
+	result, err := s.db.ExecContext(
+		ctx,
+		`WITH open AS (
+		     SELECT uuid FROM santa_groups WHERE uuid = $1 AND drawn_at IS NULL FOR SHARE
+		 )
+		 UPDATE santa_members SET status = $3 WHERE group_uuid IN (SELECT uuid FROM open) AND wisher_uuid = $2;`,
+		groupUUID, wisherUUID, status,
+	)
+	if err != nil {
+		return fmt.Errorf("error updating santa group member: %w", err)
+	}
+
+	return requireNotDrawn(result)
+}
+
+// DeleteMember removes member from the group together with exclusions of the member unless names are drawn.
+//
+// Group is locked by the same statement, so the change can't be missed by the concurrent draw.
+func (s *santaStorage) DeleteMember(ctx context.Context, groupUUID, wisherUUID string) error {
+	result, err := s.db.ExecContext(
+		ctx,
+		`WITH open AS (
+		     SELECT uuid FROM santa_groups WHERE uuid = $1 AND drawn_at IS NULL FOR SHARE
+		 ), exclusions AS (
+		     DELETE FROM santa_exclusions
+		     WHERE group_uuid IN (SELECT uuid FROM open) AND (giver_uuid = $2 OR receiver_uuid = $2)
+		 )
+		 DELETE FROM santa_members WHERE group_uuid IN (SELECT uuid FROM open) AND wisher_uuid = $2;`,
+		groupUUID, wisherUUID,
+	)
+	if err != nil {
+		return fmt.Errorf("error deleting santa group member: %w", err)
+	}
+
+	return requireNotDrawn(result)
+}
+
+// InsertExclusions adds exclusions to the group, existing ones are skipped.
+func (s *santaStorage) InsertExclusions(ctx context.Context, exclusions []Pair) error {
+	for _, exclusion := range exclusions {
+		_, err := s.db.NamedExecContext(
+			ctx,
+			`INSERT INTO santa_exclusions (group_uuid, giver_uuid, receiver_uuid)
+			 VALUES (:group_uuid, :giver_uuid, :receiver_uuid)
+			 ON CONFLICT DO NOTHING;`,
+			exclusion,
+		)
+		if err != nil {
+			return fmt.Errorf("error inserting santa exclusion: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// CopyExclusions adds exclusions and assignments of the source group as exclusions of the target group.
+func (s *santaStorage) CopyExclusions(ctx context.Context, sourceGroupUUID, targetGroupUUID string) error {
+	_, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO santa_exclusions (group_uuid, giver_uuid, receiver_uuid)
+		 SELECT $2, giver_uuid, receiver_uuid FROM santa_exclusions WHERE group_uuid = $1
+		 UNION
+		 SELECT $2, giver_uuid, receiver_uuid FROM santa_assignments WHERE group_uuid = $1
+		 ON CONFLICT DO NOTHING;`,
+		sourceGroupUUID, targetGroupUUID,
+	)
+	if err != nil {
+		return fmt.Errorf("error copying santa exclusions: %w", err)
+	}
+
+	return nil
+}
+
+// ListExclusions returns exclusions of the group.
+func (s *santaStorage) ListExclusions(ctx context.Context, groupUUID string) ([]Pair, error) {
+	var exclusions []Pair
+
+	err := sqlx.SelectContext(
+		ctx, s.db, &exclusions,
+		`SELECT * FROM santa_exclusions WHERE group_uuid = $1 ORDER BY giver_uuid, receiver_uuid;`,
+		groupUUID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting santa exclusions: %w", err)
+	}
+
+	return exclusions, nil
+}
+
+// DeleteExclusion removes single exclusion.
+func (s *santaStorage) DeleteExclusion(ctx context.Context, exclusion Pair) error {
+	result, err := s.db.NamedExecContext(
+		ctx,
+		`DELETE FROM santa_exclusions
+		 WHERE group_uuid = :group_uuid AND giver_uuid = :giver_uuid AND receiver_uuid = :receiver_uuid;`,
+		exclusion,
+	)
+	if err != nil {
+		return fmt.Errorf("error deleting santa exclusion: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error deleting santa exclusion: %w", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("santa exclusion: %w", errbase.ErrNotFound)
+	}
+
+	return nil
+}
+
+// SaveDraw stores assignments marking the group drawn and removing pending invites.
+//
+// Group is locked first, so concurrent draws can't both succeed and members can't join or leave
+// until the draw is saved. Givers are checked to be exactly the joined members after the lock,
+// as members could change since they were listed for the draw.
+func (s *santaStorage) SaveDraw(ctx context.Context, groupUUID string, assignments []Pair) error {
+	err := storageSchema.InTx(ctx, s.db, func(tx storageSchema.QueryExecutor) error {
+		var drawnAt sql.NullTime
+
+		err := tx.GetContext(ctx, &drawnAt, `SELECT drawn_at FROM santa_groups WHERE uuid = $1 FOR UPDATE;`, groupUUID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("no santa group found: %w", errbase.ErrNotFound)
+		}
+
+		if err != nil {
+			return fmt.Errorf("error locking santa group: %w", err)
+		}
+
+		if drawnAt.Valid {
+			return fmt.Errorf("%w: names are already drawn", errbase.ErrConflict)
+		}
+
+		var joined []string
+
+		err = sqlx.SelectContext(
+			ctx, tx, &joined,
+			`SELECT wisher_uuid FROM santa_members WHERE group_uuid = $1 AND status = 'joined';`,
+			groupUUID,
+		)
+		if err != nil {
+			return fmt.Errorf("error selecting santa group members: %w", err)
+		}
+
+		if !sameGivers(joined, assignments) {
+			return fmt.Errorf("%w: group members changed during the draw", errbase.ErrConflict)
+		}
+
+		return insertDraw(ctx, tx, groupUUID, assignments)
+	})
+	if err != nil {
+		return fmt.Errorf("error saving santa draw: %w", err)
+	}
+
+	return nil
+}
+
+// sameGivers checks that each member is the giver of exactly one assignment.
+func sameGivers(members []string, assignments []Pair) bool {
+	if len(members) != len(assignments) {
+		return false
+	}
+
+	givers := make(map[string]bool, len(assignments))
+
+	for _, assignment := range assignments {
+		givers[assignment.GiverUUID] = true
+	}
+
+	for _, member := range members {
+		if !givers[member] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// insertDraw marks the group drawn, removes pending invites and inserts assignments.
+func insertDraw(ctx context.Context, tx storageSchema.QueryExecutor, groupUUID string, assignments []Pair) error {
+	givers := make(pq.StringArray, len(assignments))
+	receivers := make(pq.StringArray, len(assignments))
+
+	for i, assignment := range assignments {
+		givers[i], receivers[i] = assignment.GiverUUID, assignment.ReceiverUUID
+	}
+
+	_, err := tx.ExecContext(
+		ctx,
+		`WITH drawn AS (
+		     UPDATE santa_groups SET drawn_at = now() WHERE uuid = $1 RETURNING uuid
+		 ), invites AS (
+		     DELETE FROM santa_members WHERE group_uuid IN (SELECT uuid FROM drawn) AND status = 'invited'
+		 )
+		 INSERT INTO santa_assignments (group_uuid, giver_uuid, receiver_uuid)
+		 SELECT drawn.uuid, pair.giver, pair.receiver
+		 FROM drawn, unnest($2::uuid[], $3::uuid[]) AS pair (giver, receiver);`,
+		groupUUID, givers, receivers,
+	)
+	if err != nil {
+		return fmt.Errorf("error inserting santa assignments: %w", err)
+	}
+
+	return nil
+}
+
+// GetAssignment returns assignment of the giver in the group.
+func (s *santaStorage) GetAssignment(ctx context.Context, groupUUID, giverUUID string) (*Pair, error) {
+	assignment := new(Pair)
+
+	err := s.db.GetContext(
+		ctx, assignment,
+		`SELECT * FROM santa_assignments WHERE group_uuid = $1 AND giver_uuid = $2;`,
+		groupUUID, giverUUID,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("no santa assignment found: %w", errbase.ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error selecting santa assignment: %w", err)
+	}
+
+	return assignment, nil
+}
+
+// ListAssignments returns assignments of the user as a giver.
+func (s *santaStorage) ListAssignments(ctx context.Context, giverUUID string) ([]Pair, error) {
+	var assignments []Pair
+
+	err := sqlx.SelectContext(
+		ctx, s.db, &assignments, `SELECT * FROM santa_assignments WHERE giver_uuid = $1;`, giverUUID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting santa assignments: %w", err)
+	}
+
+	return assignments, nil
+}
@@ -0,0 +1,38 @@
+package storage
+
+import "context"
+
+// SantaStorage - storage of Secret Santa groups.
+type SantaStorage interface {
+	// InsertGroup creates new group with the owner as a joined member.
+	InsertGroup(ctx context.Context, group Group) (*Group, error)
+	GetGroup(ctx context.Context, uuid string) (*Group, error)
+	// ListGroups returns groups where the user has given member status, newest first.
+	ListGroups(ctx context.Context, wisherUUID, status string) ([]Group, error)
+	DeleteGroup(ctx context.Context, uuid string) error
+
+	// InsertMember adds member to the group, existing members are not changed.
+	InsertMember(ctx context.Context, member Member) error
+	GetMember(ctx context.Context, groupUUID, wisherUUID string) (*Member, error)
+	// ListMembers returns members of the group, oldest first.
+	ListMembers(ctx context.Context, groupUUID string) ([]Member, error)
+	// UpdateMemberStatus changes status of the group member. ErrConflict is returned if the group is drawn.
+	UpdateMemberStatus(ctx context.Context, groupUUID, wisherUUID, status string) error
+	// DeleteMember removes member from the group together with exclusions of the member.
+	// ErrConflict is returned if the group is drawn.
+	DeleteMember(ctx context.Context, groupUUID, wisherUUID string) error
+
+	// InsertExclusions adds exclusions to the group, existing ones are skipped.
+	InsertExclusions(ctx context.Context, exclusions []Pair) error
+	// CopyExclusions adds exclusions and assignments of the source group as exclusions of the target group.
+	CopyExclusions(ctx context.Context, sourceGroupUUID, targetGroupUUID string) error
+	ListExclusions(ctx context.Context, groupUUID string) ([]Pair, error)
+	DeleteExclusion(ctx context.Context, exclusion Pair) error
+
+	// SaveDraw stores assignments marking the group drawn and removing pending invites.
+	// ErrConflict is returned if the group is already drawn or joined members differ from the givers.
+	SaveDraw(ctx context.Context, groupUUID string, assignments []Pair) error
+	GetAssignment(ctx context.Context, groupUUID, giverUUID string) (*Pair, error)
+	// ListAssignments returns assignments of the user as a giver.
+	ListAssignments(ctx context.Context, giverUUID string) ([]Pair, error)
+}
@@ -0,0 +1,20 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/outcatcher/anwil/domains/core/config/schema"
+)
+
+const (
+	minReconnectInterval = time.Second
+	maxReconnectInterval = time.Minute
+)
+
+// NewListener creates Postgres LISTEN/NOTIFY listener with given configuration.
+//
+// Listener reconnects automatically, callback is called on connection state changes.
+func NewListener(cfg schema.DatabaseConfiguration, callback pq.EventCallbackType) *pq.Listener {
+	return pq.NewListener(dbString(cfg), minReconnectInterval, maxReconnectInterval, callback)
+}
@@ -0,0 +1,43 @@
+package schema
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Transactor - storage able to begin transactions, i.e. *sqlx.DB.
+type Transactor interface {
+	BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error)
+}
+
+// InTx runs fn in a new transaction of db, committing it if fn succeeds and rolling it back otherwise.
+//
+// If db can't begin transactions, e.g. it's already a transaction, fn is run with db as is.
+func InTx(ctx context.Context, db QueryExecutor, fn func(tx QueryExecutor) error) error {
+	transactor, ok := db.(Transactor)
+	if !ok {
+		return fn(db)
+	}
+
+	tx, err := transactor.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return fmt.Errorf("%w (rollback failed: %s)", err, rollbackErr)
+		}
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	"github.com/outcatcher/anwil/domains/storage"
+	storageSchema "github.com/outcatcher/anwil/domains/storage/schema"
+	"github.com/outcatcher/anwil/domains/stream/schema"
+)
+
+const (
+	// notifyChannel - Postgres channel used for fan-out
+	notifyChannel = "anwil_stream"
+	// maxPayloadSize - Postgres limits NOTIFY payload to 8000 bytes
+	maxPayloadSize = 7900
+	// pingInterval - interval of checking listener connection when there are no notifications
+	pingInterval = 90 * time.Second
+)
+
+var errAlreadyStarted = errors.New("event stream is already started")
+
+// message - event with recipients sent between instances.
+type message struct {
+	Users []string     `json:"users"`
+	Event schema.Event `json:"event"`
+}
+
+// Broker publishes events through Postgres and dispatches received events to local subscribers.
+type Broker struct {
+	cfg configSchema.DatabaseConfiguration
+	db  storageSchema.QueryExecutor
+	log *log.Logger
+
+	hub *hub
+
+	mu       sync.Mutex
+	listener *pq.Listener
+	done     chan struct{}
+}
+
+// New creates new event broker using given DB.
+func New(cfg configSchema.DatabaseConfiguration, db storageSchema.QueryExecutor, logger *log.Logger) *Broker {
+	return &Broker{
+		cfg: cfg,
+		db:  db,
+		log: logger,
+		hub: newHub(),
+	}
+}
+
+// Publish sends event to all connected clients of given users on all API instances.
+func (b *Broker) Publish(ctx context.Context, userUUIDs []string, eventType string, data any) error {
+	if len(userUUIDs) == 0 {
+		return nil
+	}
+
+	encodedData, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("error encoding event data: %w", err)
+	}
+
+	payload, err := json.Marshal(message{
+		Users: userUUIDs,
+		Event: schema.Event{Type: eventType, Data: encodedData},
+	})
+	if err != nil {
+		return fmt.Errorf("error encoding event: %w", err)
+	}
+
+	if len(payload) > maxPayloadSize {
+		return fmt.Errorf("%w: event %s is %d bytes", errbase.ErrTooLarge, eventType, len(payload))
+	}
+
+	if _, err := b.db.ExecContext(ctx, `SELECT pg_notify($1, $2);`, notifyChannel, string(payload)); err != nil {
+		return fmt.Errorf("error publishing event: %w", err)
+	}
+
+	return nil
+}
+
+// Subscribe returns channel of events for the user. Channel is closed when ctx is done or broker is stopped.
+func (b *Broker) Subscribe(ctx context.Context, userUUID string) <-chan schema.Event {
+	return b.hub.subscribe(ctx, userUUID)
+}
+
+// Start starts listening for events published by all instances.
+func (b *Broker) Start() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.listener != nil {
+		return errAlreadyStarted
+	}
+
+	listener := storage.NewListener(b.cfg, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			b.log.Printf("event stream listener error: %s", err)
+		}
+	})
+
+	if err := listener.Listen(notifyChannel); err != nil {
+		_ = listener.Close()
+
+		return fmt.Errorf("error starting event stream: %w", err)
+	}
+
+	b.listener = listener
+	b.done = make(chan struct{})
+
+	go b.listen(listener)
+
+	b.log.Println("event stream started")
+
+	return nil
+}
+
+// listen dispatches received events until listener is closed.
+func (b *Broker) listen(listener *pq.Listener) {
+	defer close(b.done)
+
+	for {
+		select {
+		case notification, ok := <-listener.Notify:
+			if !ok {
+				return
+			}
+
+			b.handleNotification(notification)
+		case <-time.After(pingInterval):
+			go func() {
+				if err := listener.Ping(); err != nil {
+					b.log.Printf("event stream listener ping failed: %s", err)
+				}
+			}()
+		}
+	}
+}
+
+func (b *Broker) handleNotification(notification *pq.Notification) {
+	// nil notification is sent after reconnection, events published meanwhile are lost
+	if notification == nil {
+		b.hub.broadcast(schema.Event{Type: schema.TypeResync, Data: nil})
+
+		return
+	}
+
+	var msg message
+
+	if err := json.Unmarshal([]byte(notification.Extra), &msg); err != nil {
+		b.log.Printf("error decoding streamed event: %s", err)
+
+		return
+	}
+
+	b.hub.dispatch(msg.Users, msg.Event)
+}
+
+// Stop stops listening for events and closes all subscriptions.
+func (b *Broker) Stop() error {
+	b.hub.close()
+
+	b.mu.Lock()
+	listener, done := b.listener, b.done
+	b.mu.Unlock()
+
+	if listener == nil {
+		return nil
+	}
+
+	if err := listener.Close(); err != nil {
+		return fmt.Errorf("error stopping event stream: %w", err)
+	}
+
+	<-done
+
+	return nil
+}
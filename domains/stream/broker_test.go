@@ -0,0 +1,95 @@
+package stream
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	th "github.com/outcatcher/anwil/domains/core/testhelpers"
+	"github.com/outcatcher/anwil/domains/stream/schema"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublish(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		var payload string
+
+		mockDB := new(th.MockDBExecutor)
+		mockDB.
+			On("ExecContext", ctx, mock.AnythingOfType("string"), mock.Anything).
+			Run(func(args mock.Arguments) {
+				payload = args.Get(2).([]any)[1].(string) //nolint:forcetypeassert
+			}).
+			Return(driver.RowsAffected(1), nil)
+
+		broker := New(configSchema.DatabaseConfiguration{}, mockDB, log.Default())
+
+		require.NoError(t, broker.Publish(ctx, []string{"user"}, "test", map[string]int{"a": 1}))
+
+		var msg message
+
+		require.NoError(t, json.Unmarshal([]byte(payload), &msg))
+		require.Equal(t, []string{"user"}, msg.Users)
+		require.Equal(t, "test", msg.Event.Type)
+		require.JSONEq(t, `{"a":1}`, string(msg.Event.Data))
+	})
+
+	t.Run("no users", func(t *testing.T) {
+		t.Parallel()
+
+		mockDB := new(th.MockDBExecutor)
+		broker := New(configSchema.DatabaseConfiguration{}, mockDB, log.Default())
+
+		require.NoError(t, broker.Publish(ctx, nil, "test", nil))
+		mockDB.AssertNotCalled(t, "ExecContext")
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		t.Parallel()
+
+		mockDB := new(th.MockDBExecutor)
+		broker := New(configSchema.DatabaseConfiguration{}, mockDB, log.Default())
+
+		err := broker.Publish(ctx, []string{"user"}, "test", strings.Repeat("a", maxPayloadSize))
+		require.ErrorIs(t, err, errbase.ErrTooLarge)
+		mockDB.AssertNotCalled(t, "ExecContext")
+	})
+}
+
+func TestHandleNotification(t *testing.T) {
+	t.Parallel()
+
+	broker := New(configSchema.DatabaseConfiguration{}, nil, log.Default())
+	events := broker.Subscribe(context.Background(), "user")
+
+	broker.handleNotification(&pq.Notification{
+		Channel: notifyChannel,
+		Extra:   `{"users":["user"],"event":{"type":"test","data":{"a":1}}}`,
+	})
+
+	event := <-events
+	require.Equal(t, "test", event.Type)
+	require.JSONEq(t, `{"a":1}`, string(event.Data))
+
+	// reconnection
+	broker.handleNotification(nil)
+	require.Equal(t, schema.TypeResync, (<-events).Type)
+
+	require.NoError(t, broker.Stop())
+
+	_, ok := <-events
+	require.False(t, ok)
+}
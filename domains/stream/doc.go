@@ -0,0 +1,9 @@
+/*
+Package stream contains real-time event streaming to connected clients.
+
+Services publish events addressed to users. Events are fanned out to all API instances
+with Postgres LISTEN/NOTIFY and sent to connected clients of the users as Server-Sent Events.
+
+Publishing service is responsible for choosing the users allowed to see the event.
+*/
+package stream
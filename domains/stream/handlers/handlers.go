@@ -0,0 +1,96 @@
+/*
+Package handlers contains API handler streaming events to connected clients.
+*/
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/outcatcher/anwil/domains/stream/schema"
+	"github.com/outcatcher/anwil/domains/users/auth"
+)
+
+// heartbeatInterval - interval of comments sent to keep idle connections open through proxies.
+const heartbeatInterval = 30 * time.Second
+
+// MIMEEventStream - content type of Server-Sent Events stream.
+const MIMEEventStream = "text/event-stream"
+
+// AddStreamHandlers - adds endpoint streaming events of the authenticated user.
+func AddStreamHandlers(subscriber schema.Subscriber) svcSchema.AddHandlersFunc {
+	return func(_, secGroup *echo.Group) error {
+		secGroup.GET("/me/events", handleEvents(subscriber, heartbeatInterval))
+
+		return nil
+	}
+}
+
+// writeEvent writes event in Server-Sent Events format.
+func writeEvent(w io.Writer, event schema.Event) error {
+	data := event.Data
+	if len(data) == 0 {
+		data = []byte("{}")
+	}
+
+	// event data is compact JSON, so it has no newlines
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+		return fmt.Errorf("error writing event: %w", err)
+	}
+
+	return nil
+}
+
+func handleEvents(subscriber schema.Subscriber, heartbeat time.Duration) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error streaming events: %w", err)
+		}
+
+		ctx := c.Request().Context()
+		events := subscriber.Subscribe(ctx, claims.UserUUID)
+
+		resp := c.Response()
+		resp.Header().Set(echo.HeaderContentType, MIMEEventStream)
+		resp.Header().Set(echo.HeaderCacheControl, "no-cache")
+		// disables response buffering in nginx
+		resp.Header().Set("X-Accel-Buffering", "no")
+		resp.WriteHeader(http.StatusOK)
+
+		// client is disconnected on write errors, so they only stop the stream
+		if _, err := io.WriteString(resp, ": connected\n\n"); err != nil {
+			return nil //nolint:nilerr
+		}
+
+		resp.Flush()
+
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case event, ok := <-events:
+				if !ok {
+					return nil // server is stopping or client is too slow
+				}
+
+				if err := writeEvent(resp, event); err != nil {
+					return nil //nolint:nilerr
+				}
+			case <-ticker.C:
+				if _, err := io.WriteString(resp, ": ping\n\n"); err != nil {
+					return nil //nolint:nilerr
+				}
+			}
+
+			resp.Flush()
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package stream
+
+import (
+	"context"
+	"sync"
+
+	"github.com/outcatcher/anwil/domains/stream/schema"
+)
+
+// subscriberBuffer - number of events buffered for each subscriber.
+// Subscribers not reading events fast enough are disconnected.
+const subscriberBuffer = 32
+
+// hub dispatches events to subscribers of the current instance.
+type hub struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan schema.Event]struct{}
+	closed      bool
+}
+
+func newHub() *hub {
+	return &hub{subscribers: make(map[string]map[chan schema.Event]struct{})}
+}
+
+// subscribe returns channel of events for the user. Channel is closed when ctx is done or hub is closed.
+func (h *hub) subscribe(ctx context.Context, userUUID string) <-chan schema.Event {
+	events := make(chan schema.Event, subscriberBuffer)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		close(events)
+
+		return events
+	}
+
+	if h.subscribers[userUUID] == nil {
+		h.subscribers[userUUID] = make(map[chan schema.Event]struct{})
+	}
+
+	h.subscribers[userUUID][events] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		h.remove(userUUID, events)
+	}()
+
+	return events
+}
+
+// remove closes subscription if it's not closed yet. Should be called with mu locked.
+func (h *hub) remove(userUUID string, events chan schema.Event) {
+	userSubscribers := h.subscribers[userUUID]
+
+	if _, ok := userSubscribers[events]; !ok {
+		return
+	}
+
+	delete(userSubscribers, events)
+	close(events)
+
+	if len(userSubscribers) == 0 {
+		delete(h.subscribers, userUUID)
+	}
+}
+
+// send sends event to the subscriber, disconnecting it if the buffer is full. Should be called with mu locked.
+func (h *hub) send(userUUID string, events chan schema.Event, event schema.Event) {
+	select {
+	case events <- event:
+	default:
+		// client will reconnect and reload the data
+		h.remove(userUUID, events)
+	}
+}
+
+// dispatch sends event to all subscribers of given users.
+func (h *hub) dispatch(userUUIDs []string, event schema.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, userUUID := range userUUIDs {
+		for events := range h.subscribers[userUUID] {
+			h.send(userUUID, events, event)
+		}
+	}
+}
+
+// broadcast sends event to all subscribers.
+func (h *hub) broadcast(event schema.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for userUUID, userSubscribers := range h.subscribers {
+		for events := range userSubscribers {
+			h.send(userUUID, events, event)
+		}
+	}
+}
+
+// close closes all subscriptions. New subscriptions are closed immediately.
+func (h *hub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+
+	for userUUID, userSubscribers := range h.subscribers {
+		for events := range userSubscribers {
+			h.remove(userUUID, events)
+		}
+	}
+}
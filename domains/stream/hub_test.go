@@ -0,0 +1,83 @@
+package stream
+
+import (
+	"context"
+	"testing"
+
+	"github.com/outcatcher/anwil/domains/stream/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHubDispatch(t *testing.T) {
+	t.Parallel()
+
+	h := newHub()
+	ctx := context.Background()
+
+	first := h.subscribe(ctx, "first")
+	second := h.subscribe(ctx, "second")
+
+	event := schema.Event{Type: "test", Data: []byte(`{"a":1}`)}
+
+	h.dispatch([]string{"first", "unknown"}, event)
+
+	require.Equal(t, event, <-first)
+	require.Empty(t, second)
+
+	h.broadcast(event)
+
+	require.Equal(t, event, <-first)
+	require.Equal(t, event, <-second)
+}
+
+func TestHubUnsubscribe(t *testing.T) {
+	t.Parallel()
+
+	h := newHub()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := h.subscribe(ctx, "user")
+
+	cancel()
+
+	_, ok := <-events
+	require.False(t, ok)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	require.Empty(t, h.subscribers)
+}
+
+func TestHubSlowSubscriber(t *testing.T) {
+	t.Parallel()
+
+	h := newHub()
+	events := h.subscribe(context.Background(), "user")
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		h.dispatch([]string{"user"}, schema.Event{Type: "test", Data: nil})
+	}
+
+	received := 0
+	for range events {
+		received++
+	}
+
+	require.Equal(t, subscriberBuffer, received)
+}
+
+func TestHubClose(t *testing.T) {
+	t.Parallel()
+
+	h := newHub()
+	events := h.subscribe(context.Background(), "user")
+
+	h.close()
+
+	_, ok := <-events
+	require.False(t, ok)
+
+	_, ok = <-h.subscribe(context.Background(), "user")
+	require.False(t, ok)
+}
@@ -0,0 +1,61 @@
+/*
+Package schema contains real-time event streaming DTOs and interfaces
+*/
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/outcatcher/anwil/domains/core/services"
+)
+
+// TypeResync is sent to all connected clients when events could be lost, e.g. after reconnection to the DB.
+// Clients are expected to reload displayed data.
+const TypeResync = "resync"
+
+// Event - event streamed to connected clients.
+type Event struct {
+	// Type is a type of the event, e.g. "notification"
+	Type string `json:"type"`
+	// Data is JSON-encoded event payload
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// Publisher publishes events to connected clients of the users on all API instances.
+type Publisher interface {
+	// Publish sends event to all connected clients of given users.
+	// Event data is encoded as JSON, encoded size is limited to a few kilobytes.
+	Publish(ctx context.Context, userUUIDs []string, eventType string, data any) error
+}
+
+// Subscriber provides events for connected clients.
+type Subscriber interface {
+	// Subscribe returns channel of events for the user. Channel is closed when ctx is done or stream is stopped.
+	Subscribe(ctx context.Context, userUUID string) <-chan Event
+}
+
+// WithPublisher defines service or state having event publisher attached.
+type WithPublisher interface {
+	Publisher() Publisher
+}
+
+// RequiresPublisher defines service which can use event publisher attached.
+type RequiresPublisher interface {
+	UsePublisher(publisher Publisher)
+}
+
+// PublisherInject adds event publisher to the service.
+func PublisherInject(consumer, provider any) error {
+	reqPublisher, provPublisher, err := services.ValidateArgInterfaces[RequiresPublisher, WithPublisher](
+		consumer, provider,
+	)
+	if err != nil {
+		return fmt.Errorf("error injecting event publisher: %w", err)
+	}
+
+	reqPublisher.UsePublisher(provPublisher.Publisher())
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	"github.com/outcatcher/anwil/domains/users/service/schema"
+)
+
+// RequireAdmin allows requests of authenticated admins only.
+//
+// Role is loaded on each request, so role changes are applied without issuing new token.
+func RequireAdmin(usr schema.UserService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims, err := ClaimsFromContext(c)
+			if err != nil {
+				return fmt.Errorf("error checking admin role: %w", err)
+			}
+
+			user, err := usr.GetUserByUUID(c.Request().Context(), claims.UserUUID)
+			if err != nil {
+				return fmt.Errorf("error checking admin role: %w", err)
+			}
+
+			if user.Role != schema.RoleAdmin {
+				return fmt.Errorf("%w: admin role required", errbase.ErrForbidden)
+			}
+
+			return next(c)
+		}
+	}
+}
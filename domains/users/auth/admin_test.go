@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	th "github.com/outcatcher/anwil/domains/core/testhelpers"
+	"github.com/outcatcher/anwil/domains/users/service/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireAdmin(t *testing.T) {
+	t.Parallel()
+
+	next := func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	}
+
+	claims := &schema.Claims{Username: th.RandomString("user-", 5), UserUUID: th.RandomString("uuid-", 5)}
+
+	t.Run("admin", func(t *testing.T) {
+		t.Parallel()
+
+		echoCtx := newContext(t)
+		echoCtx.Set(ContextKey, &jwt.Token{Claims: claims})
+
+		require.NoError(t, RequireAdmin(&th.FakeUsers{User: schema.User{Role: schema.RoleAdmin}})(next)(echoCtx))
+		require.Equal(t, http.StatusNoContent, echoCtx.Response().Status)
+	})
+
+	t.Run("wisher", func(t *testing.T) {
+		t.Parallel()
+
+		echoCtx := newContext(t)
+		echoCtx.Set(ContextKey, &jwt.Token{Claims: claims})
+
+		err := RequireAdmin(&th.FakeUsers{User: schema.User{Role: schema.RoleWisher}})(next)(echoCtx)
+		require.ErrorIs(t, err, errbase.ErrForbidden)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		t.Parallel()
+
+		err := RequireAdmin(&th.FakeUsers{User: schema.User{Role: schema.RoleAdmin}})(next)(newContext(t))
+		require.ErrorIs(t, err, errbase.ErrUnauthorized)
+	})
+}
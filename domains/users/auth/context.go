@@ -0,0 +1,39 @@
+/*
+Package auth contains helpers for accessing authenticated user data in handlers.
+*/
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	"github.com/outcatcher/anwil/domains/users/service/schema"
+)
+
+// ContextKey - key of the echo context value holding parsed JWT.
+const ContextKey = "username"
+
+var errMissingClaims = errors.New("missing user claims")
+
+// NewClaims returns empty claims to be filled by JWT middleware.
+func NewClaims(echo.Context) jwt.Claims {
+	return new(schema.Claims)
+}
+
+// ClaimsFromContext returns claims of the user authenticated by JWT middleware.
+func ClaimsFromContext(c echo.Context) (*schema.Claims, error) {
+	token, ok := c.Get(ContextKey).(*jwt.Token)
+	if !ok {
+		return nil, fmt.Errorf("%w: %w", errbase.ErrUnauthorized, errMissingClaims)
+	}
+
+	claims, ok := token.Claims.(*schema.Claims)
+	if !ok || claims.Username == "" {
+		return nil, fmt.Errorf("%w: %w", errbase.ErrUnauthorized, errMissingClaims)
+	}
+
+	return claims, nil
+}
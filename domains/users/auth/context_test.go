@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	th "github.com/outcatcher/anwil/domains/core/testhelpers"
+	"github.com/outcatcher/anwil/domains/users/service/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func newContext(t *testing.T) echo.Context {
+	t.Helper()
+
+	req := &http.Request{
+		URL:    new(url.URL),
+		Method: http.MethodGet,
+		Header: make(http.Header),
+	}
+
+	return echo.New().NewContext(req, th.ClosingRecorder(t))
+}
+
+func TestClaimsFromContext(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		expected := &schema.Claims{Username: th.RandomString("user-", 5)}
+
+		echoCtx := newContext(t)
+		echoCtx.Set(ContextKey, &jwt.Token{Claims: expected})
+
+		claims, err := ClaimsFromContext(echoCtx)
+		require.NoError(t, err)
+		require.Equal(t, expected, claims)
+	})
+
+	cases := map[string]any{
+		"missing":      nil,
+		"not token":    "token",
+		"map claims":   &jwt.Token{Claims: jwt.MapClaims{"username": "user"}},
+		"empty claims": &jwt.Token{Claims: new(schema.Claims)},
+	}
+
+	for name, value := range cases {
+		value := value
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			echoCtx := newContext(t)
+			echoCtx.Set(ContextKey, value)
+
+			_, err := ClaimsFromContext(echoCtx)
+			require.ErrorIs(t, err, errbase.ErrUnauthorized)
+		})
+	}
+}
@@ -5,14 +5,15 @@ import (
 	"net/http"
 
 	"github.com/labstack/echo/v4"
+	"github.com/outcatcher/anwil/domains/core/negotiation"
 	"github.com/outcatcher/anwil/domains/users/service/schema"
 )
 
 type credentialsRequest struct {
 	// Username
-	Username string `json:"username" validate:"required"`
+	Username string `json:"username" form:"username" validate:"required"`
 	// User password
-	Password string `json:"password" validate:"required"`
+	Password string `json:"password" form:"password" validate:"required"`
 }
 
 type jwtResponse struct {
@@ -23,7 +24,7 @@ func handleAuthorize(usr schema.UserService) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		req := new(credentialsRequest)
 
-		if err := bindAndValidateJSON(c, req); err != nil {
+		if err := bindAndValidate(c, req); err != nil {
 			return fmt.Errorf("error authorizing user: %w", err)
 		}
 
@@ -37,6 +38,6 @@ func handleAuthorize(usr schema.UserService) echo.HandlerFunc {
 			return fmt.Errorf("error authorizing user: %w", err)
 		}
 
-		return c.JSON(http.StatusOK, jwtResponse{Token: tok})
+		return negotiation.Respond(c, http.StatusOK, jwtResponse{Token: tok})
 	}
 }
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/outcatcher/anwil/domains/core/negotiation"
+	"github.com/outcatcher/anwil/domains/core/validation"
+	"github.com/outcatcher/anwil/domains/users/auth"
+	"github.com/outcatcher/anwil/domains/users/service/schema"
+)
+
+// avatarFormField - multipart form field containing uploaded avatar.
+const avatarFormField = "file"
+
+// formFile returns multipart form part with given name.
+//
+// Form is streamed, so the file is never stored in memory or on disk as a whole.
+func formFile(c echo.Context, name string) (io.Reader, error) {
+	reader, err := c.Request().MultipartReader()
+	if err != nil {
+		return nil, fmt.Errorf("%w: multipart form expected: %w", validation.ErrValidationFailed, err)
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: form field '%s' is missing", validation.ErrValidationFailed, name)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid multipart form: %w", validation.ErrValidationFailed, err)
+		}
+
+		if part.FormName() == name {
+			return part, nil
+		}
+	}
+}
+
+func handleUploadAvatar(usr schema.UserService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error uploading avatar: %w", err)
+		}
+
+		file, err := formFile(c, avatarFormField)
+		if err != nil {
+			return fmt.Errorf("error uploading avatar: %w", err)
+		}
+
+		user, err := usr.UploadAvatar(c.Request().Context(), claims.Username, file)
+		if err != nil {
+			return fmt.Errorf("error uploading avatar: %w", err)
+		}
+
+		return negotiation.Respond(c, http.StatusOK, user)
+	}
+}
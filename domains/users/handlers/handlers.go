@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/labstack/echo/v4"
+	"github.com/outcatcher/anwil/domains/core/negotiation"
 	"github.com/outcatcher/anwil/domains/core/services"
 	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
 	"github.com/outcatcher/anwil/domains/core/validation"
@@ -21,18 +22,30 @@ func AddUserHandlers(state svcSchema.ProvidingServices) svcSchema.AddHandlersFun
 			return fmt.Errorf("error adding user hanlders: %w", err)
 		}
 
-		baseGroup.POST("/login", handleAuthorize(userService))
-		baseGroup.POST("/wisher", handleUserRegister(userService))
+		consumesData := negotiation.Consumes(negotiation.DataTypes...)
+
+		baseGroup.POST("/login", handleAuthorize(userService), consumesData)
+		baseGroup.POST("/wisher", handleUserRegister(userService), consumesData)
+		baseGroup.GET("/wisher/verify-email", handleVerifyEmail(userService))
+		baseGroup.POST("/wisher/verify-email", handleResendVerification(userService), consumesData)
+		baseGroup.POST("/password-reset", handleRequestPasswordReset(userService), consumesData)
+		baseGroup.POST("/password-reset/confirm", handleResetPassword(userService), consumesData)
+
+		secGroup.GET("/me", handleGetMe(userService))
+		secGroup.PATCH("/me", handleUpdateMe(userService), consumesData)
+		secGroup.DELETE("/me", handleDeleteMe(userService))
+		secGroup.POST("/me/password", handleChangePassword(userService), consumesData)
+		secGroup.PUT("/me/avatar", handleUploadAvatar(userService), negotiation.Consumes(negotiation.MIMEMultipart))
 
 		return nil
 	}
 }
 
-// bindAndValidateJSON binds request body to structure,
+// bindAndValidate binds request body of any supported content type to structure,
 // validates it using `validate` tag and trows errors into gin context.
-func bindAndValidateJSON(c echo.Context, req any) error {
+func bindAndValidate(c echo.Context, req any) error {
 	if err := c.Bind(req); err != nil {
-		return fmt.Errorf("error binding JSON: %w", err)
+		return fmt.Errorf("error binding request: %w", err)
 	}
 
 	if err := validation.ValidateJSONCtx(c.Request().Context(), req); err != nil {
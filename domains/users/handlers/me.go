@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/outcatcher/anwil/domains/core/negotiation"
+	"github.com/outcatcher/anwil/domains/users/auth"
+	"github.com/outcatcher/anwil/domains/users/service/schema"
+)
+
+type updateProfileRequest struct {
+	FullName *string `json:"full_name" form:"full_name" validate:"omitempty,max=200"`
+	Avatar   *string `json:"avatar" form:"avatar" validate:"omitempty,url|len=0"` // empty value removes avatar
+	// empty value resets currency to the default one
+	Currency *string `json:"currency" form:"currency" validate:"omitempty,iso4217|len=0"`
+}
+
+type changePasswordRequest struct {
+	CurrentPassword string `json:"current_password" form:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" form:"new_password" validate:"required"`
+}
+
+func handleGetMe(usr schema.UserService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error getting current user: %w", err)
+		}
+
+		user, err := usr.GetUser(c.Request().Context(), claims.Username)
+		if err != nil {
+			return fmt.Errorf("error getting current user: %w", err)
+		}
+
+		return negotiation.Respond(c, http.StatusOK, user)
+	}
+}
+
+func handleUpdateMe(usr schema.UserService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error updating profile: %w", err)
+		}
+
+		req := new(updateProfileRequest)
+
+		if err := bindAndValidate(c, req); err != nil {
+			return fmt.Errorf("error updating profile: %w", err)
+		}
+
+		user, err := usr.UpdateProfile(c.Request().Context(), claims.Username, schema.ProfileUpdate{
+			FullName: req.FullName,
+			Avatar:   req.Avatar,
+			Currency: req.Currency,
+		})
+		if err != nil {
+			return fmt.Errorf("error updating profile: %w", err)
+		}
+
+		return negotiation.Respond(c, http.StatusOK, user)
+	}
+}
+
+func handleChangePassword(usr schema.UserService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error changing password: %w", err)
+		}
+
+		req := new(changePasswordRequest)
+
+		if err := bindAndValidate(c, req); err != nil {
+			return fmt.Errorf("error changing password: %w", err)
+		}
+
+		err = usr.ChangePassword(c.Request().Context(), claims.Username, req.CurrentPassword, req.NewPassword)
+		if err != nil {
+			return fmt.Errorf("error changing password: %w", err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func handleDeleteMe(usr schema.UserService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error deleting user: %w", err)
+		}
+
+		if err := usr.DeleteUser(c.Request().Context(), claims.Username); err != nil {
+			return fmt.Errorf("error deleting user: %w", err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
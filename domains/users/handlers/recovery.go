@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/outcatcher/anwil/domains/core/validation"
+	"github.com/outcatcher/anwil/domains/users/service/schema"
+)
+
+type emailRequest struct {
+	Email string `json:"email" form:"email" validate:"required,email"`
+}
+
+type resetPasswordRequest struct {
+	Token    string `json:"token" form:"token" validate:"required"`
+	Password string `json:"password" form:"password" validate:"required"`
+}
+
+func handleVerifyEmail(usr schema.UserService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token := c.QueryParam("token")
+		if token == "" {
+			return fmt.Errorf("%w: query parameter 'token' is missing", validation.ErrValidationFailed)
+		}
+
+		if err := usr.VerifyEmail(c.Request().Context(), token); err != nil {
+			return fmt.Errorf("error verifying email: %w", err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// handleResendVerification requests new verification email.
+//
+// Response is the same for known and unknown emails.
+func handleResendVerification(usr schema.UserService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := new(emailRequest)
+
+		if err := bindAndValidate(c, req); err != nil {
+			return fmt.Errorf("error sending email verification: %w", err)
+		}
+
+		if err := usr.SendEmailVerification(c.Request().Context(), req.Email); err != nil {
+			return fmt.Errorf("error sending email verification: %w", err)
+		}
+
+		return c.NoContent(http.StatusAccepted)
+	}
+}
+
+// handleRequestPasswordReset requests password reset token.
+//
+// Response is the same for known and unknown emails.
+func handleRequestPasswordReset(usr schema.UserService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := new(emailRequest)
+
+		if err := bindAndValidate(c, req); err != nil {
+			return fmt.Errorf("error requesting password reset: %w", err)
+		}
+
+		if err := usr.RequestPasswordReset(c.Request().Context(), req.Email); err != nil {
+			return fmt.Errorf("error requesting password reset: %w", err)
+		}
+
+		return c.NoContent(http.StatusAccepted)
+	}
+}
+
+func handleResetPassword(usr schema.UserService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := new(resetPasswordRequest)
+
+		if err := bindAndValidate(c, req); err != nil {
+			return fmt.Errorf("error resetting password: %w", err)
+		}
+
+		if err := usr.ResetPassword(c.Request().Context(), req.Token, req.Password); err != nil {
+			return fmt.Errorf("error resetting password: %w", err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
@@ -9,9 +9,10 @@ import (
 )
 
 type createUser struct {
-	Username string `json:"username" validate:"required"`
-	Password string `json:"password" validate:"required"`
-	FullName string `json:"full_name"`
+	Username string `json:"username" form:"username" validate:"required"`
+	Password string `json:"password" form:"password" validate:"required"`
+	FullName string `json:"full_name" form:"full_name"`
+	Email    string `json:"email" form:"email" validate:"omitempty,email"`
 }
 
 func handleUserRegister(usr schema.UserService) echo.HandlerFunc {
@@ -19,7 +20,7 @@ func handleUserRegister(usr schema.UserService) echo.HandlerFunc {
 		ctx := c.Request().Context()
 		req := new(createUser)
 
-		if err := bindAndValidateJSON(c, req); err != nil {
+		if err := bindAndValidate(c, req); err != nil {
 			return fmt.Errorf("error registering user: %w", err)
 		}
 
@@ -27,6 +28,7 @@ func handleUserRegister(usr schema.UserService) echo.HandlerFunc {
 			Username: req.Username,
 			Password: req.Password,
 			FullName: req.FullName,
+			Email:    req.Email,
 		})
 		if err != nil {
 			return fmt.Errorf("error registering user: %w", err)
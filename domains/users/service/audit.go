@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+
+	auditSchema "github.com/outcatcher/anwil/domains/audit/schema"
+	"github.com/outcatcher/anwil/domains/users/service/schema"
+)
+
+// audit records audit log entry. The action is already done at this point, so failure is only logged.
+func (u *service) audit(ctx context.Context, entry auditSchema.Entry) {
+	if err := u.auditor.Audit(ctx, entry); err != nil {
+		u.log.Printf("error recording audit entry %s: %s", entry.Action, err)
+	}
+}
+
+// redacted replaces values of personal attributes in audit entries.
+//
+// Audit log is append-only, so personal data stored there would outlive user account deletion.
+const redacted = "[redacted]"
+
+// personalAttributes are profile attributes only recorded as changed, without values.
+var personalAttributes = []string{"username", "full_name", "email"}
+
+// redactPersonal replaces values of personal attributes present in the attributes.
+func redactPersonal(attributes map[string]any) {
+	for _, key := range personalAttributes {
+		if _, ok := attributes[key]; ok {
+			attributes[key] = redacted
+		}
+	}
+}
+
+// profileAttributes returns audited user attributes.
+func profileAttributes(user *schema.User) map[string]any {
+	return map[string]any{
+		"username":  user.Username,
+		"full_name": user.FullName,
+		"email":     user.Email,
+		"avatar":    user.Avatar,
+		"currency":  user.Currency,
+	}
+}
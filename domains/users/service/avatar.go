@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/outcatcher/anwil/domains/images"
+	"github.com/outcatcher/anwil/domains/users/service/schema"
+)
+
+// avatarsPrefix - blob key prefix of uploaded avatars.
+const avatarsPrefix = "avatars"
+
+// thumbnailKey returns blob key of the thumbnail of the image with given key.
+func thumbnailKey(key string) string {
+	ext := path.Ext(key)
+
+	return strings.TrimSuffix(key, ext) + "-thumb" + ext
+}
+
+// blobURL returns public URL of the blob.
+func (u *service) blobURL(key string) string {
+	return u.cfg.API.BaseURL() + "/api/v1/blobs/" + key
+}
+
+// UploadAvatar processes uploaded image and sets it as user avatar.
+//
+// Previous uploaded avatar is removed.
+func (u *service) UploadAvatar(ctx context.Context, username string, image io.Reader) (*schema.User, error) {
+	user, err := u.storage.GetUser(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("error uploading avatar: %w", err)
+	}
+
+	maxSize := u.cfg.Images.MaxUploadSize
+	if maxSize <= 0 {
+		maxSize = images.DefaultMaxUploadSize
+	}
+
+	data, err := images.ReadLimited(image, maxSize)
+	if err != nil {
+		return nil, fmt.Errorf("error uploading avatar: %w", err)
+	}
+
+	processed, err := images.Process(data, u.cfg.Images.ThumbnailSize)
+	if err != nil {
+		return nil, fmt.Errorf("error uploading avatar: %w", err)
+	}
+
+	// new key for each upload, so cached old avatars are not shown
+	key := path.Join(avatarsPrefix, user.UUID, uuid.NewString()+processed.Image.Extension)
+
+	err = u.blobs.Put(ctx, key, processed.Image.ContentType, processed.Image.Data)
+	if err != nil {
+		return nil, fmt.Errorf("error uploading avatar: %w", err)
+	}
+
+	err = u.blobs.Put(ctx, thumbnailKey(key), processed.Thumbnail.ContentType, processed.Thumbnail.Data)
+	if err != nil {
+		u.removeAvatar(ctx, key)
+
+		return nil, fmt.Errorf("error uploading avatar thumbnail: %w", err)
+	}
+
+	if err := u.storage.SetUploadedAvatar(ctx, user.UUID, key); err != nil {
+		u.removeAvatar(ctx, key)
+
+		return nil, fmt.Errorf("error uploading avatar: %w", err)
+	}
+
+	if user.AvatarKey != "" {
+		u.removeAvatar(ctx, user.AvatarKey)
+	}
+
+	return u.GetUser(ctx, username)
+}
+
+// removeAvatar removes uploaded avatar with its thumbnail.
+//
+// Errors are only logged, as the avatar is not used anymore.
+func (u *service) removeAvatar(ctx context.Context, key string) {
+	if err := u.deleteAvatarBlobs(ctx, key); err != nil {
+		u.log.Printf("error removing avatar %s: %s", key, err)
+	}
+}
+
+func (u *service) deleteAvatarBlobs(ctx context.Context, key string) error {
+	if err := u.blobs.Delete(ctx, key); err != nil {
+		return fmt.Errorf("error deleting avatar: %w", err)
+	}
+
+	if err := u.blobs.Delete(ctx, thumbnailKey(key)); err != nil {
+		return fmt.Errorf("error deleting avatar thumbnail: %w", err)
+	}
+
+	return nil
+}
+
+// PrepareErasure returns function removing uploaded avatar of the user. User record itself is deleted by DeleteUser.
+func (u *service) PrepareErasure(ctx context.Context, userUUID string) (svcSchema.EraseFunc, error) {
+	user, err := u.storage.GetUserByUUID(ctx, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("error preparing user data erasure: %w", err)
+	}
+
+	return func(ctx context.Context) error {
+		if user.AvatarKey == "" {
+			return nil
+		}
+
+		if err := u.deleteAvatarBlobs(ctx, user.AvatarKey); err != nil {
+			return fmt.Errorf("error erasing user data: %w", err)
+		}
+
+		return nil
+	}, nil
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"image"
+	"image/png"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+
+	blobsSchema "github.com/outcatcher/anwil/domains/blobs/schema"
+	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	th "github.com/outcatcher/anwil/domains/core/testhelpers"
+	userStorage "github.com/outcatcher/anwil/domains/users/storage"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryBlobs - in-memory blob store.
+type memoryBlobs struct {
+	mu    sync.Mutex
+	blobs map[string][]byte
+}
+
+func (m *memoryBlobs) Put(_ context.Context, key, _ string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.blobs[key] = data
+
+	return nil
+}
+
+func (m *memoryBlobs) Get(_ context.Context, key string) (*blobsSchema.Blob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data, ok := m.blobs[key]
+	if !ok {
+		return nil, errbase.ErrNotFound
+	}
+
+	return &blobsSchema.Blob{ReadCloser: io.NopCloser(bytes.NewReader(data)), Size: int64(len(data))}, nil
+}
+
+func (m *memoryBlobs) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.blobs, key)
+
+	return nil
+}
+
+func (m *memoryBlobs) keys() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]string, 0, len(m.blobs))
+
+	for key := range m.blobs {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+func (s *UsersSuite) newUploadingService(mockDB *th.MockDBExecutor) (*service, *memoryBlobs) {
+	blobs := &memoryBlobs{blobs: make(map[string][]byte)}
+
+	svc := s.newService(mockDB)
+	svc.blobs = blobs
+	svc.log = log.Default()
+	svc.cfg = &configSchema.Configuration{
+		API:    configSchema.APIConfiguration{PublicURL: "https://anwil.example.com"},
+		Images: configSchema.ImagesConfiguration{MaxUploadSize: 1 << 20, ThumbnailSize: 32},
+	}
+
+	return svc, blobs
+}
+
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, png.Encode(buf, image.NewRGBA(image.Rect(0, 0, 100, 50))))
+
+	return buf.Bytes()
+}
+
+func (s *UsersSuite) TestUploadAvatar() {
+	t := s.T()
+	ctx := context.Background()
+
+	t.Parallel()
+
+	wisher := userStorage.Wisher{
+		UUID:      th.RandomString("uuid-", 10),
+		Username:  th.RandomString("user-", 10),
+		AvatarKey: "avatars/old.png",
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		var storedKey string
+
+		mockDB := new(th.MockDBExecutor)
+		mockGetWisher(ctx, mockDB, wisher)
+		mockDB.
+			On("ExecContext", ctx, mock.AnythingOfType("string"), mock.Anything).
+			Run(func(args mock.Arguments) {
+				storedKey = args.Get(2).([]any)[1].(string) //nolint:forcetypeassert
+			}).
+			Return(driver.RowsAffected(1), nil)
+
+		svc, blobs := s.newUploadingService(mockDB)
+		require.NoError(t, blobs.Put(ctx, wisher.AvatarKey, "", nil))
+		require.NoError(t, blobs.Put(ctx, thumbnailKey(wisher.AvatarKey), "", nil))
+
+		_, err := svc.UploadAvatar(ctx, wisher.Username, bytes.NewReader(testPNG(t)))
+		require.NoError(t, err)
+
+		require.True(t, strings.HasPrefix(storedKey, "avatars/"+wisher.UUID+"/"), storedKey)
+		require.ElementsMatch(t, []string{storedKey, thumbnailKey(storedKey)}, blobs.keys())
+	})
+
+	t.Run("not an image", func(t *testing.T) {
+		t.Parallel()
+
+		mockDB := new(th.MockDBExecutor)
+		mockGetWisher(ctx, mockDB, wisher)
+
+		svc, blobs := s.newUploadingService(mockDB)
+
+		_, err := svc.UploadAvatar(ctx, wisher.Username, strings.NewReader("<html></html>"))
+		require.ErrorIs(t, err, errbase.ErrUnsupportedMediaType)
+
+		require.Empty(t, blobs.keys())
+		mockDB.AssertNotCalled(t, "ExecContext")
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		t.Parallel()
+
+		mockDB := new(th.MockDBExecutor)
+		mockGetWisher(ctx, mockDB, wisher)
+
+		svc, _ := s.newUploadingService(mockDB)
+		svc.cfg.Images.MaxUploadSize = 10
+
+		_, err := svc.UploadAvatar(ctx, wisher.Username, bytes.NewReader(testPNG(t)))
+		require.ErrorIs(t, err, errbase.ErrTooLarge)
+	})
+}
+
+func (s *UsersSuite) TestToUser_uploadedAvatar() {
+	t := s.T()
+
+	t.Parallel()
+
+	svc, _ := s.newUploadingService(new(th.MockDBExecutor))
+
+	user := svc.toUser(&userStorage.Wisher{AvatarKey: "avatars/uuid/image.jpg"})
+	require.Equal(t, "https://anwil.example.com/api/v1/blobs/avatars/uuid/image.jpg", user.Avatar)
+	require.Equal(t, "https://anwil.example.com/api/v1/blobs/avatars/uuid/image-thumb.jpg", user.AvatarThumbnail)
+}
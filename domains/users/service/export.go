@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+)
+
+// ExportUserData returns user profile data for personal data export.
+func (u *service) ExportUserData(ctx context.Context, userUUID string) (*svcSchema.UserDataExport, error) {
+	user, err := u.storage.GetUserByUUID(ctx, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("error exporting user data: %w", err)
+	}
+
+	export := &svcSchema.UserDataExport{Data: u.toUser(user)}
+
+	if user.AvatarKey != "" {
+		export.Files = append(export.Files, svcSchema.ExportedFile{
+			Name: "avatar" + path.Ext(user.AvatarKey),
+			Open: func(ctx context.Context) (io.ReadCloser, error) {
+				return u.blobs.Get(ctx, user.AvatarKey) //nolint:wrapcheck
+			},
+		})
+	}
+
+	return export, nil
+}
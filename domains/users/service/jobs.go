@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/outcatcher/anwil/domains/core/services"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/outcatcher/anwil/domains/jobs"
+	storageSchema "github.com/outcatcher/anwil/domains/storage/schema"
+	"github.com/outcatcher/anwil/domains/users/service/schema"
+)
+
+const (
+	emailVerificationJobKind = "users.send_email_verification"
+	passwordResetJobKind     = "users.send_password_reset"
+)
+
+// emailPayload - payload of the job sending email to the given address.
+type emailPayload struct {
+	Email string `json:"email"`
+}
+
+// addUserJobs registers users service jobs.
+func addUserJobs(state svcSchema.ProvidingServices) svcSchema.AddJobsFunc {
+	return func(registry svcSchema.JobRegistry) error {
+		svc, err := services.GetServiceFromProvider[*service](state, schema.ServiceID)
+		if err != nil {
+			return fmt.Errorf("error adding user jobs: %w", err)
+		}
+
+		if err := registry.Handle(emailVerificationJobKind, jobs.Typed(svc.sendEmailVerification)); err != nil {
+			return fmt.Errorf("error adding user jobs: %w", err)
+		}
+
+		if err := registry.Handle(passwordResetJobKind, jobs.Typed(svc.sendPasswordReset)); err != nil {
+			return fmt.Errorf("error adding user jobs: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// deleteEmailJobs removes jobs sending emails to the given address using given transaction.
+func (u *service) deleteEmailJobs(ctx context.Context, tx storageSchema.QueryExecutor, email string) error {
+	if email == "" {
+		return nil
+	}
+
+	payload := emailPayload{Email: normalizeEmail(email)}
+
+	for _, kind := range []string{emailVerificationJobKind, passwordResetJobKind} {
+		if err := u.queue.DeleteJobs(ctx, tx, kind, payload); err != nil {
+			return fmt.Errorf("error deleting email jobs: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	auditSchema "github.com/outcatcher/anwil/domains/audit/schema"
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	notificationsSchema "github.com/outcatcher/anwil/domains/notifications/schema"
+	storageSchema "github.com/outcatcher/anwil/domains/storage/schema"
+	"github.com/outcatcher/anwil/domains/users/service/schema"
+	"github.com/outcatcher/anwil/domains/users/storage"
+)
+
+func toNullString(value *string) sql.NullString {
+	if value == nil {
+		return sql.NullString{}
+	}
+
+	return sql.NullString{String: *value, Valid: true}
+}
+
+// UpdateProfile changes user profile data, returning updated user.
+func (u *service) UpdateProfile(ctx context.Context, username string, update schema.ProfileUpdate) (*schema.User, error) {
+	user, err := u.storage.GetUser(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("error updating profile: %w", err)
+	}
+
+	before := u.toUser(user)
+
+	err = u.storage.UpdateProfile(ctx, user.UUID, storage.ProfileUpdate{
+		FullName: toNullString(update.FullName),
+		Avatar:   toNullString(update.Avatar),
+		Currency: toNullString(update.Currency),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error updating profile: %w", err)
+	}
+
+	// uploaded avatar is replaced by the given URL
+	if update.Avatar != nil && user.AvatarKey != "" {
+		u.removeAvatar(ctx, user.AvatarKey)
+	}
+
+	updated, err := u.GetUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	u.auditProfileChange(ctx, before, updated)
+
+	return updated, nil
+}
+
+// auditProfileChange records changed profile attributes.
+func (u *service) auditProfileChange(ctx context.Context, before, after *schema.User) {
+	changedBefore, changedAfter, err := auditSchema.Diff(profileAttributes(before), profileAttributes(after))
+	if err != nil {
+		u.log.Printf("error recording profile change of user %s: %s", before.UUID, err)
+
+		return
+	}
+
+	if len(changedAfter) == 0 {
+		return // nothing changed
+	}
+
+	redactPersonal(changedBefore)
+	redactPersonal(changedAfter)
+
+	u.audit(ctx, auditSchema.Entry{
+		Action:     auditSchema.ActionProfileUpdate,
+		ActorUUID:  before.UUID,
+		TargetUUID: before.UUID,
+		Before:     changedBefore,
+		After:      changedAfter,
+	})
+}
+
+// ChangePassword replaces user password after checking the current one.
+//
+// Passwords expected to be not encrypted.
+func (u *service) ChangePassword(ctx context.Context, username, currentPassword, newPassword string) error {
+	user, err := u.GetUser(ctx, username)
+	if err != nil {
+		return fmt.Errorf("error changing password: %w", err)
+	}
+
+	err = validatePassword(currentPassword, user.Password, u.privateKey)
+	if errors.Is(err, errbase.ErrUnauthorized) {
+		return fmt.Errorf("%w: current password is invalid", errbase.ErrForbidden)
+	}
+
+	if err != nil {
+		return fmt.Errorf("error changing password: %w", err)
+	}
+
+	if err := checkRequirements(newPassword); err != nil {
+		return fmt.Errorf("error changing password: %w", err)
+	}
+
+	encrypted, err := encrypt(newPassword, u.privateKey)
+	if err != nil {
+		return fmt.Errorf("error encrypting new password: %w", err)
+	}
+
+	if err := u.storage.UpdatePassword(ctx, user.UUID, encrypted); err != nil {
+		return fmt.Errorf("error changing password: %w", err)
+	}
+
+	// password hashes are never recorded
+	u.audit(ctx, auditSchema.Entry{
+		Action:     auditSchema.ActionPasswordChange,
+		ActorUUID:  user.UUID,
+		TargetUUID: user.UUID,
+		Before:     nil,
+		After:      nil,
+	})
+
+	u.notifyPasswordChanged(ctx, user.UUID)
+
+	return nil
+}
+
+// notifyPasswordChanged informs user about password change. Password is already changed at this point,
+// so failure is only logged.
+func (u *service) notifyPasswordChanged(ctx context.Context, userUUID string) {
+	err := u.notifier.Notify(ctx, notificationsSchema.Notification{
+		UserUUID: userUUID,
+		Type:     notificationsSchema.TypePasswordChanged,
+		Title:    "Password changed",
+		Body:     "Your Anwil password was changed. If it wasn't you, reset the password right away.",
+		Link:     "",
+		Data:     nil,
+	})
+	if err != nil {
+		u.log.Printf("error notifying user %s about password change: %s", userUUID, err)
+	}
+}
+
+// DeleteUser erases all user data in all services and deletes the user.
+//
+// User record is deleted together with queued jobs holding personal data of the user,
+// data stored outside of the DB is removed only after the deletion is committed.
+func (u *service) DeleteUser(ctx context.Context, username string) error {
+	user, err := u.GetUser(ctx, username)
+	if err != nil {
+		return fmt.Errorf("error deleting user: %w", err)
+	}
+
+	erase, err := u.eraser.PrepareErasure(ctx, user.UUID)
+	if err != nil {
+		return fmt.Errorf("error deleting user: %w", err)
+	}
+
+	err = storageSchema.InTx(ctx, u.db, func(tx storageSchema.QueryExecutor) error {
+		if err := storage.New(tx).DeleteUser(ctx, user.UUID); err != nil {
+			return err
+		}
+
+		return u.deleteEmailJobs(ctx, tx, user.Email)
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting user: %w", err)
+	}
+
+	u.audit(ctx, auditSchema.Entry{
+		Action:     auditSchema.ActionDelete,
+		ActorUUID:  user.UUID,
+		TargetUUID: user.UUID,
+		Before:     nil,
+		After:      nil,
+	})
+
+	// user is already deleted at this point, so failure is only logged
+	if err := erase(ctx); err != nil {
+		u.log.Printf("error erasing data of deleted user %s: %s", user.UUID, err)
+	}
+
+	u.log.Printf("user %s deleted", user.UUID)
+
+	return nil
+}
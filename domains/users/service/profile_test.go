@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"log"
+	"testing"
+
+	auditSchema "github.com/outcatcher/anwil/domains/audit/schema"
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	th "github.com/outcatcher/anwil/domains/core/testhelpers"
+	"github.com/outcatcher/anwil/domains/core/validation"
+	notificationsSchema "github.com/outcatcher/anwil/domains/notifications/schema"
+	"github.com/outcatcher/anwil/domains/users/service/schema"
+	userStorage "github.com/outcatcher/anwil/domains/users/storage"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockEraser struct {
+	mock.Mock
+}
+
+func (m *mockEraser) PrepareErasure(ctx context.Context, userUUID string) (svcSchema.EraseFunc, error) {
+	if err := m.Called(ctx, userUUID).Error(0); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context) error {
+		return m.MethodCalled("Erase", ctx).Error(0)
+	}, nil
+}
+
+// mockGetWisher mocks loading given wisher by username.
+func mockGetWisher(ctx context.Context, mockDB *th.MockDBExecutor, wisher userStorage.Wisher) {
+	mockDB.
+		On("GetContext",
+			ctx, new(userStorage.Wisher), mock.AnythingOfType("string"), []any{wisher.Username},
+		).
+		Run(setWisher(wisher)).
+		Return(nil)
+}
+
+func (s *UsersSuite) TestUpdateProfile() {
+	t := s.T()
+	ctx := context.Background()
+
+	t.Parallel()
+
+	wisher := userStorage.Wisher{
+		UUID:     th.RandomString("uuid-", 10),
+		Username: th.RandomString("user-", 10),
+	}
+
+	newName := th.RandomString("name-", 10)
+
+	updated := wisher
+	updated.FullName = newName
+
+	mockDB := new(th.MockDBExecutor)
+	mockDB.
+		On("GetContext",
+			ctx, new(userStorage.Wisher), mock.AnythingOfType("string"), []any{wisher.Username},
+		).
+		Run(setWisher(wisher)).
+		Return(nil).
+		Once()
+	mockGetWisher(ctx, mockDB, updated)
+	mockDB.
+		On("ExecContext",
+			ctx, mock.AnythingOfType("string"),
+			[]any{wisher.UUID, sql.NullString{String: newName, Valid: true}, sql.NullString{}, sql.NullString{}},
+		).
+		Return(driver.RowsAffected(1), nil)
+
+	auditor := new(recordingAuditor)
+
+	users := s.newService(mockDB)
+	users.auditor = auditor
+
+	_, err := users.UpdateProfile(ctx, wisher.Username, schema.ProfileUpdate{FullName: &newName})
+	require.NoError(t, err)
+
+	mockDB.AssertNumberOfCalls(t, "ExecContext", 1)
+
+	require.Len(t, auditor.entries, 1)
+	require.Equal(t, auditSchema.ActionProfileUpdate, auditor.entries[0].Action)
+	require.Equal(t, map[string]any{"full_name": redacted}, auditor.entries[0].After)
+}
+
+func (s *UsersSuite) TestChangePassword() {
+	t := s.T()
+	ctx := context.Background()
+
+	t.Parallel()
+
+	currentPassword := th.RandomString("pwd-", 20)
+
+	encrypted, err := encrypt(currentPassword, s.privateKey)
+	require.NoError(t, err)
+
+	wisher := userStorage.Wisher{
+		UUID:     th.RandomString("uuid-", 10),
+		Username: th.RandomString("user-", 10),
+		Password: encrypted,
+	}
+
+	newPassword := th.RandomString("new-pwd-", 20)
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		var storedPassword string
+
+		mockDB := new(th.MockDBExecutor)
+		mockGetWisher(ctx, mockDB, wisher)
+		mockDB.
+			On("ExecContext", ctx, mock.AnythingOfType("string"), mock.Anything).
+			Run(func(args mock.Arguments) {
+				storedPassword = args.Get(2).([]any)[1].(string) //nolint:forcetypeassert
+			}).
+			Return(driver.RowsAffected(1), nil)
+
+		notifier := new(th.MockNotifier)
+		notifier.
+			On("Notify", ctx, mock.MatchedBy(func(notification notificationsSchema.Notification) bool {
+				return notification.UserUUID == wisher.UUID &&
+					notification.Type == notificationsSchema.TypePasswordChanged
+			})).
+			Return(nil)
+
+		users := s.newService(mockDB)
+		users.notifier = notifier
+
+		err := users.ChangePassword(ctx, wisher.Username, currentPassword, newPassword)
+		require.NoError(t, err)
+
+		s.requireEqualPasswords(newPassword, storedPassword)
+
+		notifier.AssertNumberOfCalls(t, "Notify", 1)
+	})
+
+	t.Run("invalid current password", func(t *testing.T) {
+		t.Parallel()
+
+		mockDB := new(th.MockDBExecutor)
+		mockGetWisher(ctx, mockDB, wisher)
+
+		err := s.newService(mockDB).ChangePassword(ctx, wisher.Username, newPassword, newPassword)
+		require.ErrorIs(t, err, errbase.ErrForbidden)
+
+		mockDB.AssertNotCalled(t, "ExecContext")
+	})
+
+	t.Run("simple new password", func(t *testing.T) {
+		t.Parallel()
+
+		mockDB := new(th.MockDBExecutor)
+		mockGetWisher(ctx, mockDB, wisher)
+
+		err := s.newService(mockDB).ChangePassword(ctx, wisher.Username, currentPassword, "qwerty")
+		require.ErrorIs(t, err, validation.ErrValidationFailed)
+
+		mockDB.AssertNotCalled(t, "ExecContext")
+	})
+}
+
+func (s *UsersSuite) TestDeleteUser() {
+	t := s.T()
+	ctx := context.Background()
+
+	t.Parallel()
+
+	wisher := userStorage.Wisher{
+		UUID:     th.RandomString("uuid-", 10),
+		Username: th.RandomString("user-", 10),
+		Email:    sql.NullString{String: "user@example.com", Valid: true},
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		mockDB := new(th.MockDBExecutor)
+		mockGetWisher(ctx, mockDB, wisher)
+		mockDB.
+			On("ExecContext", ctx, mock.AnythingOfType("string"), []any{wisher.UUID}).
+			Return(driver.RowsAffected(1), nil)
+
+		queue := new(th.MockQueue)
+		queue.On("DeleteJobs", ctx, emailVerificationJobKind, emailPayload{Email: "user@example.com"}).Return(nil)
+		queue.On("DeleteJobs", ctx, passwordResetJobKind, emailPayload{Email: "user@example.com"}).Return(nil)
+
+		eraser := new(mockEraser)
+		eraser.On("PrepareErasure", ctx, wisher.UUID).Return(nil)
+		eraser.
+			On("Erase", ctx).
+			Run(func(mock.Arguments) {
+				// files are removed only after the user is deleted
+				mockDB.AssertNumberOfCalls(t, "ExecContext", 1)
+			}).
+			Return(nil)
+
+		svc := s.newService(mockDB)
+		svc.queue = queue
+		svc.eraser = eraser
+		svc.log = log.Default()
+
+		require.NoError(t, svc.DeleteUser(ctx, wisher.Username))
+
+		eraser.AssertNumberOfCalls(t, "Erase", 1)
+		queue.AssertNumberOfCalls(t, "DeleteJobs", 2)
+	})
+
+	t.Run("prepare failed", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected erase error") //nolint:goerr113
+
+		mockDB := new(th.MockDBExecutor)
+		mockGetWisher(ctx, mockDB, wisher)
+
+		eraser := new(mockEraser)
+		eraser.On("PrepareErasure", ctx, wisher.UUID).Return(expectedErr)
+
+		svc := s.newService(mockDB)
+		svc.eraser = eraser
+
+		require.ErrorIs(t, svc.DeleteUser(ctx, wisher.Username), expectedErr)
+
+		mockDB.AssertNotCalled(t, "ExecContext")
+	})
+
+	t.Run("delete failed", func(t *testing.T) {
+		t.Parallel()
+
+		expectedErr := errors.New("expected delete error") //nolint:goerr113
+
+		mockDB := new(th.MockDBExecutor)
+		mockGetWisher(ctx, mockDB, wisher)
+		mockDB.
+			On("ExecContext", ctx, mock.AnythingOfType("string"), []any{wisher.UUID}).
+			Return(driver.RowsAffected(0), expectedErr)
+
+		eraser := new(mockEraser)
+		eraser.On("PrepareErasure", ctx, wisher.UUID).Return(nil)
+
+		svc := s.newService(mockDB)
+		svc.eraser = eraser
+
+		require.ErrorIs(t, svc.DeleteUser(ctx, wisher.Username), expectedErr)
+
+		eraser.AssertNotCalled(t, "Erase", ctx)
+	})
+}
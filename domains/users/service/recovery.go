@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	auditSchema "github.com/outcatcher/anwil/domains/audit/schema"
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	mailSchema "github.com/outcatcher/anwil/domains/mail/schema"
+	"github.com/outcatcher/anwil/domains/users/storage"
+)
+
+const (
+	emailVerificationTTL = 48 * time.Hour
+	passwordResetTTL     = time.Hour
+)
+
+const emailVerificationBody = `Hello, %s!
+
+Please confirm your email address by following the link:
+
+%s/api/v1/wisher/verify-email?token=%s
+
+The link is valid for %s.
+`
+
+const passwordResetBody = `Hello, %s!
+
+Somebody requested password reset for your Anwil account.
+If it wasn't you, just ignore this message.
+
+Password reset token:
+
+%s
+
+Use it with POST %s/api/v1/password-reset/confirm within %s.
+`
+
+// normalizeEmail returns email in a form it's stored in DB.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// issueToken generates and stores new single-use token.
+func (u *service) issueToken(ctx context.Context, wisherUUID, purpose string, ttl time.Duration) (string, error) {
+	token, claims, err := generateActionToken(wisherUUID, purpose, ttl, u.privateKey)
+	if err != nil {
+		return "", err
+	}
+
+	err = u.storage.InsertToken(ctx, storage.WisherToken{
+		UUID:       claims.ID,
+		WisherUUID: wisherUUID,
+		Purpose:    purpose,
+		ExpiresAt:  claims.ExpiresAt.Time,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error storing token: %w", err)
+	}
+
+	return token, nil
+}
+
+// findByEmail returns user with given email.
+func (u *service) findByEmail(ctx context.Context, email string) (*storage.Wisher, error) {
+	user, err := u.storage.GetUserByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		return nil, fmt.Errorf("error getting user by email: %w", err)
+	}
+
+	return user, nil
+}
+
+// SendEmailVerification enqueues sending of email verification link to the given email.
+//
+// Lookup and sending are done in background, so email usage can't be discovered by response time.
+func (u *service) SendEmailVerification(ctx context.Context, email string) error {
+	err := u.queue.Enqueue(ctx, emailVerificationJobKind, emailPayload{Email: normalizeEmail(email)})
+	if err != nil {
+		return fmt.Errorf("error sending email verification: %w", err)
+	}
+
+	return nil
+}
+
+// sendEmailVerification sends email verification link to the given email if it's not verified yet.
+//
+// Not existing and already verified emails are silently ignored, so email usage can't be discovered.
+func (u *service) sendEmailVerification(ctx context.Context, payload emailPayload) error {
+	user, err := u.findByEmail(ctx, payload.Email)
+	if errors.Is(err, errbase.ErrNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("error sending email verification: %w", err)
+	}
+
+	if user.EmailVerified {
+		return nil
+	}
+
+	token, err := u.issueToken(ctx, user.UUID, storage.PurposeEmailVerification, emailVerificationTTL)
+	if err != nil {
+		return fmt.Errorf("error sending email verification: %w", err)
+	}
+
+	err = u.mailer.Send(ctx, mailSchema.Message{
+		To:      user.Email.String,
+		Subject: "Anwil: confirm your email",
+		Body:    fmt.Sprintf(emailVerificationBody, user.FullName, u.cfg.API.BaseURL(), token, emailVerificationTTL),
+	})
+	if err != nil {
+		return fmt.Errorf("error sending email verification: %w", err)
+	}
+
+	return nil
+}
+
+// VerifyEmail marks user email as verified using token sent by SendEmailVerification.
+func (u *service) VerifyEmail(ctx context.Context, token string) error {
+	claims, err := parseActionToken(token, storage.PurposeEmailVerification, u.privateKey.Public())
+	if err != nil {
+		return fmt.Errorf("error verifying email: %w", err)
+	}
+
+	err = u.storage.VerifyEmail(ctx, claims.ID)
+	if errors.Is(err, errbase.ErrNotFound) {
+		return fmt.Errorf("%w: token expired or already used", errbase.ErrUnauthorized)
+	}
+
+	if err != nil {
+		return fmt.Errorf("error verifying email: %w", err)
+	}
+
+	return nil
+}
+
+// RequestPasswordReset enqueues sending of password reset token to the given email.
+//
+// Lookup and sending are done in background, so email usage can't be discovered by response time.
+func (u *service) RequestPasswordReset(ctx context.Context, email string) error {
+	err := u.queue.Enqueue(ctx, passwordResetJobKind, emailPayload{Email: normalizeEmail(email)})
+	if err != nil {
+		return fmt.Errorf("error requesting password reset: %w", err)
+	}
+
+	return nil
+}
+
+// sendPasswordReset sends password reset token to the given email.
+//
+// Only verified emails can be used for password reset.
+// Unknown and not verified emails are silently ignored, so email usage can't be discovered.
+func (u *service) sendPasswordReset(ctx context.Context, payload emailPayload) error {
+	user, err := u.findByEmail(ctx, payload.Email)
+	if errors.Is(err, errbase.ErrNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("error requesting password reset: %w", err)
+	}
+
+	if !user.EmailVerified || !user.Enabled {
+		return nil
+	}
+
+	token, err := u.issueToken(ctx, user.UUID, storage.PurposePasswordReset, passwordResetTTL)
+	if err != nil {
+		return fmt.Errorf("error requesting password reset: %w", err)
+	}
+
+	err = u.mailer.Send(ctx, mailSchema.Message{
+		To:      user.Email.String,
+		Subject: "Anwil: password reset",
+		Body:    fmt.Sprintf(passwordResetBody, user.FullName, token, u.cfg.API.BaseURL(), passwordResetTTL),
+	})
+	if err != nil {
+		return fmt.Errorf("error requesting password reset: %w", err)
+	}
+
+	return nil
+}
+
+// ResetPassword sets new user password using token sent by RequestPasswordReset.
+//
+// password expected to be not encrypted.
+func (u *service) ResetPassword(ctx context.Context, token, password string) error {
+	claims, err := parseActionToken(token, storage.PurposePasswordReset, u.privateKey.Public())
+	if err != nil {
+		return fmt.Errorf("error resetting password: %w", err)
+	}
+
+	if err := checkRequirements(password); err != nil {
+		return fmt.Errorf("error resetting password: %w", err)
+	}
+
+	encrypted, err := encrypt(password, u.privateKey)
+	if err != nil {
+		return fmt.Errorf("error encrypting new password: %w", err)
+	}
+
+	err = u.storage.ResetPassword(ctx, claims.ID, encrypted)
+	if errors.Is(err, errbase.ErrNotFound) {
+		return fmt.Errorf("%w: token expired or already used", errbase.ErrUnauthorized)
+	}
+
+	if err != nil {
+		return fmt.Errorf("error resetting password: %w", err)
+	}
+
+	u.audit(ctx, auditSchema.Entry{
+		Action:     auditSchema.ActionPasswordReset,
+		ActorUUID:  claims.Subject,
+		TargetUUID: claims.Subject,
+		Before:     nil,
+		After:      nil,
+	})
+
+	u.notifyPasswordChanged(ctx, claims.Subject)
+
+	return nil
+}
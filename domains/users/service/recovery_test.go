@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"log"
+	"testing"
+	"time"
+
+	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	th "github.com/outcatcher/anwil/domains/core/testhelpers"
+	"github.com/outcatcher/anwil/domains/core/validation"
+	mailSchema "github.com/outcatcher/anwil/domains/mail/schema"
+	userStorage "github.com/outcatcher/anwil/domains/users/storage"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockMailer struct {
+	mock.Mock
+}
+
+func (m *mockMailer) Send(ctx context.Context, msg mailSchema.Message) error {
+	return m.Called(ctx, msg).Error(0)
+}
+
+func (s *UsersSuite) newMailingService(mockDB *th.MockDBExecutor, mailer mailSchema.Mailer) *service {
+	svc := s.newService(mockDB)
+	svc.mailer = mailer
+	svc.log = log.Default()
+	svc.cfg = &configSchema.Configuration{
+		API: configSchema.APIConfiguration{PublicURL: "https://anwil.example.com/"},
+	}
+
+	return svc
+}
+
+func (s *UsersSuite) TestActionTokens() {
+	t := s.T()
+
+	t.Parallel()
+
+	wisherUUID := th.RandomString("uuid-", 10)
+
+	token, claims, err := generateActionToken(
+		wisherUUID, userStorage.PurposePasswordReset, time.Minute, s.privateKey,
+	)
+	require.NoError(t, err)
+	require.NotEmpty(t, claims.ID)
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		parsed, err := parseActionToken(token, userStorage.PurposePasswordReset, s.privateKey.Public())
+		require.NoError(t, err)
+		require.Equal(t, claims.ID, parsed.ID)
+		require.Equal(t, wisherUUID, parsed.Subject)
+	})
+
+	t.Run("wrong purpose", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := parseActionToken(token, userStorage.PurposeEmailVerification, s.privateKey.Public())
+		require.ErrorIs(t, err, errPurposeMismatch)
+		require.ErrorIs(t, err, errbase.ErrUnauthorized)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		t.Parallel()
+
+		expired, _, err := generateActionToken(
+			wisherUUID, userStorage.PurposePasswordReset, -time.Minute, s.privateKey,
+		)
+		require.NoError(t, err)
+
+		_, err = parseActionToken(expired, userStorage.PurposePasswordReset, s.privateKey.Public())
+		require.ErrorIs(t, err, errbase.ErrUnauthorized)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := generateActionToken(wisherUUID, userStorage.PurposePasswordReset, time.Minute, nil)
+		require.Error(t, err)
+	})
+}
+
+func (s *UsersSuite) TestRequestPasswordReset() {
+	t := s.T()
+	ctx := context.Background()
+
+	t.Parallel()
+
+	queue := new(th.MockQueue)
+	queue.On("Enqueue", ctx, passwordResetJobKind, emailPayload{Email: "user@example.com"}).Return(nil)
+
+	svc := s.newMailingService(new(th.MockDBExecutor), nil)
+	svc.queue = queue
+
+	require.NoError(t, svc.RequestPasswordReset(ctx, " User@Example.com"))
+
+	queue.AssertNumberOfCalls(t, "Enqueue", 1)
+}
+
+func (s *UsersSuite) TestSendPasswordReset() {
+	t := s.T()
+	ctx := context.Background()
+
+	t.Parallel()
+
+	wisher := userStorage.Wisher{
+		UUID:          th.RandomString("uuid-", 10),
+		Username:      th.RandomString("user-", 10),
+		Email:         sql.NullString{String: "user@example.com", Valid: true},
+		EmailVerified: true,
+		Enabled:       true,
+	}
+
+	t.Run("verified email", func(t *testing.T) {
+		t.Parallel()
+
+		mockDB := new(th.MockDBExecutor)
+		mockDB.
+			On("GetContext",
+				ctx, new(userStorage.Wisher), mock.AnythingOfType("string"), []any{"user@example.com"},
+			).
+			Run(setWisher(wisher)).
+			Return(nil)
+		mockDB.
+			On("NamedExecContext",
+				ctx, mock.AnythingOfType("string"), mock.AnythingOfType("storage.WisherToken"),
+			).
+			Return(driver.RowsAffected(1), nil)
+
+		mailer := new(mockMailer)
+		mailer.
+			On("Send", ctx, mock.AnythingOfType("schema.Message")).
+			Return(nil)
+
+		err := s.newMailingService(mockDB, mailer).sendPasswordReset(ctx, emailPayload{Email: "user@example.com"})
+		require.NoError(t, err)
+
+		mailer.AssertNumberOfCalls(t, "Send", 1)
+
+		sent := mailer.Calls[0].Arguments.Get(1).(mailSchema.Message) //nolint:forcetypeassert
+		require.Equal(t, wisher.Email.String, sent.To)
+		require.Contains(t, sent.Body, "https://anwil.example.com/api/v1/password-reset/confirm")
+	})
+
+	t.Run("not verified email", func(t *testing.T) {
+		t.Parallel()
+
+		notVerified := wisher
+		notVerified.EmailVerified = false
+
+		mockDB := new(th.MockDBExecutor)
+		mockDB.
+			On("GetContext",
+				ctx, new(userStorage.Wisher), mock.AnythingOfType("string"), mock.Anything,
+			).
+			Run(setWisher(notVerified)).
+			Return(nil)
+
+		mailer := new(mockMailer)
+
+		err := s.newMailingService(mockDB, mailer).sendPasswordReset(ctx, emailPayload{Email: wisher.Email.String})
+		require.NoError(t, err)
+
+		mailer.AssertNotCalled(t, "Send")
+	})
+
+	t.Run("unknown email", func(t *testing.T) {
+		t.Parallel()
+
+		mockDB := new(th.MockDBExecutor)
+		mockDB.
+			On("GetContext",
+				ctx, new(userStorage.Wisher), mock.AnythingOfType("string"), mock.Anything,
+			).
+			Return(sql.ErrNoRows)
+
+		mailer := new(mockMailer)
+
+		err := s.newMailingService(mockDB, mailer).sendPasswordReset(ctx, emailPayload{Email: "unknown@example.com"})
+		require.NoError(t, err)
+
+		mailer.AssertNotCalled(t, "Send")
+	})
+}
+
+func (s *UsersSuite) TestResetPassword() {
+	t := s.T()
+	ctx := context.Background()
+
+	t.Parallel()
+
+	newPassword := th.RandomString("pwd-", 20)
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		token, claims, err := generateActionToken(
+			th.RandomString("uuid-", 10), userStorage.PurposePasswordReset, time.Minute, s.privateKey,
+		)
+		require.NoError(t, err)
+
+		var storedPassword string
+
+		mockDB := new(th.MockDBExecutor)
+		mockDB.
+			On("GetContext",
+				ctx, mock.AnythingOfType("*string"), mock.AnythingOfType("string"), mock.Anything,
+			).
+			Run(func(args mock.Arguments) {
+				queryArgs := args.Get(3).([]any) //nolint:forcetypeassert
+
+				require.Equal(t, claims.ID, queryArgs[0])
+				storedPassword = queryArgs[2].(string) //nolint:forcetypeassert
+			}).
+			Return(nil)
+
+		err = s.newMailingService(mockDB, nil).ResetPassword(ctx, token, newPassword)
+		require.NoError(t, err)
+
+		s.requireEqualPasswords(newPassword, storedPassword)
+	})
+
+	t.Run("used token", func(t *testing.T) {
+		t.Parallel()
+
+		token, _, err := generateActionToken(
+			th.RandomString("uuid-", 10), userStorage.PurposePasswordReset, time.Minute, s.privateKey,
+		)
+		require.NoError(t, err)
+
+		mockDB := new(th.MockDBExecutor)
+		mockDB.
+			On("GetContext",
+				ctx, mock.AnythingOfType("*string"), mock.AnythingOfType("string"), mock.Anything,
+			).
+			Return(sql.ErrNoRows)
+
+		err = s.newMailingService(mockDB, nil).ResetPassword(ctx, token, newPassword)
+		require.ErrorIs(t, err, errbase.ErrUnauthorized)
+	})
+
+	t.Run("verification token", func(t *testing.T) {
+		t.Parallel()
+
+		token, _, err := generateActionToken(
+			th.RandomString("uuid-", 10), userStorage.PurposeEmailVerification, time.Minute, s.privateKey,
+		)
+		require.NoError(t, err)
+
+		err = s.newMailingService(new(th.MockDBExecutor), nil).ResetPassword(ctx, token, newPassword)
+		require.ErrorIs(t, err, errbase.ErrUnauthorized)
+	})
+
+	t.Run("simple password", func(t *testing.T) {
+		t.Parallel()
+
+		token, _, err := generateActionToken(
+			th.RandomString("uuid-", 10), userStorage.PurposePasswordReset, time.Minute, s.privateKey,
+		)
+		require.NoError(t, err)
+
+		err = s.newMailingService(new(th.MockDBExecutor), nil).ResetPassword(ctx, token, "qwerty")
+		require.ErrorIs(t, err, validation.ErrValidationFailed)
+	})
+}
+
+func (s *UsersSuite) TestVerifyEmail() {
+	t := s.T()
+	ctx := context.Background()
+
+	t.Parallel()
+
+	token, claims, err := generateActionToken(
+		th.RandomString("uuid-", 10), userStorage.PurposeEmailVerification, time.Minute, s.privateKey,
+	)
+	require.NoError(t, err)
+
+	mockDB := new(th.MockDBExecutor)
+	mockDB.
+		On("GetContext",
+			ctx, mock.AnythingOfType("*string"), mock.AnythingOfType("string"),
+			[]any{claims.ID, userStorage.PurposeEmailVerification},
+		).
+		Return(nil)
+
+	require.NoError(t, s.newMailingService(mockDB, nil).VerifyEmail(ctx, token))
+}
@@ -6,14 +6,23 @@ package schema
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/outcatcher/anwil/domains/core/services"
 	"github.com/outcatcher/anwil/domains/core/services/schema"
 )
 
 // ServiceID - ID for user service.
 const ServiceID schema.ServiceID = "users"
 
+// User roles, matching `role` DB type.
+const (
+	RoleWisher = "wisher"
+	RoleAdmin  = "admin"
+)
+
 // Claims - JWT payload contents.
 type Claims struct {
 	jwt.RegisteredClaims
@@ -22,6 +31,13 @@ type Claims struct {
 	UserUUID string `json:"user_uuid"`
 }
 
+// ActionClaims - payload of single-use action tokens, e.g. email verification or password reset.
+type ActionClaims struct {
+	jwt.RegisteredClaims
+
+	Purpose string `json:"purpose"`
+}
+
 var (
 	// ErrUnexpectedSignMethod - signing method not supported.
 	ErrUnexpectedSignMethod = errors.New("unexpected signing method")
@@ -32,8 +48,60 @@ var (
 // UserService - service handling user-related functionality.
 type UserService interface {
 	GetUser(ctx context.Context, username string) (*User, error)
+	GetUserByUUID(ctx context.Context, uuid string) (*User, error)
 	SaveUser(ctx context.Context, user User) error
 	GenerateUserToken(ctx context.Context, user User) (string, error)
+
+	SendEmailVerification(ctx context.Context, email string) error
+	VerifyEmail(ctx context.Context, token string) error
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, password string) error
+
+	UpdateProfile(ctx context.Context, username string, update ProfileUpdate) (*User, error)
+	ChangePassword(ctx context.Context, username, currentPassword, newPassword string) error
+	DeleteUser(ctx context.Context, username string) error
+	// UploadAvatar processes uploaded image and sets it as user avatar.
+	UploadAvatar(ctx context.Context, username string, image io.Reader) (*User, error)
+}
+
+// UserFinder looks up users. Used by services referencing users.
+type UserFinder interface {
+	GetUser(ctx context.Context, username string) (*User, error)
+	GetUserByUUID(ctx context.Context, uuid string) (*User, error)
+}
+
+// RequiresUserFinder defines service which can use user finder attached.
+type RequiresUserFinder interface {
+	UseUserFinder(finder UserFinder)
+}
+
+// UserFinderInject adds users service as user finder to the service.
+//
+// Users service is taken from the provider, so consumer has to list it in `DependsOn`.
+func UserFinderInject(consumer, provider any) error {
+	reqFinder, provServices, err := services.ValidateArgInterfaces[
+		RequiresUserFinder, schema.ProvidingServices,
+	](consumer, provider)
+	if err != nil {
+		return fmt.Errorf("error injecting user finder: %w", err)
+	}
+
+	finder, err := services.GetServiceFromProvider[UserFinder](provServices, ServiceID)
+	if err != nil {
+		return fmt.Errorf("error injecting user finder: %w", err)
+	}
+
+	reqFinder.UseUserFinder(finder)
+
+	return nil
+}
+
+// ProfileUpdate holds changed user profile data. Nil fields are not changed.
+type ProfileUpdate struct {
+	FullName *string
+	Avatar   *string
+	// Currency is ISO 4217 code of preferred currency, empty value resets it to the default one
+	Currency *string
 }
 
 // User holds user data.
@@ -42,4 +110,15 @@ type User struct {
 	Username string `json:"username"`
 	Password string `json:"-"` // hex-encoded password, make sure it's not reaching JSON
 	FullName string `json:"full_name"`
+	// Role is one of RoleWisher or RoleAdmin
+	Role  string `json:"role"`
+	Email string `json:"email"`
+	// EmailVerified is true if user confirmed email ownership
+	EmailVerified bool `json:"email_verified"`
+	// Avatar is URL of user avatar image
+	Avatar string `json:"avatar"`
+	// AvatarThumbnail is URL of small version of uploaded avatar, empty for external avatars
+	AvatarThumbnail string `json:"avatar_thumbnail,omitempty"`
+	// Currency is ISO 4217 code of preferred currency, used for displaying totals
+	Currency string `json:"currency"`
 }
@@ -9,10 +9,15 @@ import (
 	"fmt"
 	"log"
 
+	auditSchema "github.com/outcatcher/anwil/domains/audit/schema"
+	blobsSchema "github.com/outcatcher/anwil/domains/blobs/schema"
 	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
 	logSchema "github.com/outcatcher/anwil/domains/core/logging/schema"
 	"github.com/outcatcher/anwil/domains/core/services"
 	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	jobsSchema "github.com/outcatcher/anwil/domains/jobs/schema"
+	mailSchema "github.com/outcatcher/anwil/domains/mail/schema"
+	notificationsSchema "github.com/outcatcher/anwil/domains/notifications/schema"
 	storageSchema "github.com/outcatcher/anwil/domains/storage/schema"
 	"github.com/outcatcher/anwil/domains/users/handlers"
 	"github.com/outcatcher/anwil/domains/users/service/schema"
@@ -22,9 +27,17 @@ import (
 // service - users service.
 type service struct {
 	cfg     *configSchema.Configuration
+	db      storageSchema.QueryExecutor
 	storage userStorage.UserStorage
 
-	log *log.Logger
+	log    *log.Logger
+	mailer mailSchema.Mailer
+	queue  jobsSchema.Queue
+	eraser svcSchema.UserDataEraser
+	blobs  blobsSchema.BlobStore
+
+	notifier notificationsSchema.Notifier
+	auditor  auditSchema.Auditor
 
 	privateKey ed25519.PrivateKey
 }
@@ -36,6 +49,7 @@ func (u *service) UseConfig(configuration *configSchema.Configuration) {
 
 // UseStorage attaches given DB storage to the service.
 func (u *service) UseStorage(db storageSchema.QueryExecutor) {
+	u.db = db
 	u.storage = userStorage.New(db)
 }
 
@@ -44,6 +58,36 @@ func (u *service) UseLogger(logger *log.Logger) {
 	u.log = logger
 }
 
+// UseMailer attaches mailer to the service.
+func (u *service) UseMailer(mailer mailSchema.Mailer) {
+	u.mailer = mailer
+}
+
+// UseJobQueue attaches background job queue.
+func (u *service) UseJobQueue(queue jobsSchema.Queue) {
+	u.queue = queue
+}
+
+// UseUserDataEraser attaches eraser of user data stored by other services.
+func (u *service) UseUserDataEraser(eraser svcSchema.UserDataEraser) {
+	u.eraser = eraser
+}
+
+// UseBlobStore attaches storage of uploaded files.
+func (u *service) UseBlobStore(store blobsSchema.BlobStore) {
+	u.blobs = store
+}
+
+// UseNotifier attaches notification publisher.
+func (u *service) UseNotifier(notifier notificationsSchema.Notifier) {
+	u.notifier = notifier
+}
+
+// UseAuditor attaches audit log recorder.
+func (u *service) UseAuditor(auditor auditSchema.Auditor) {
+	u.auditor = auditor
+}
+
 func userServiceInit(_ context.Context, state any) (any, error) {
 	svc := new(service)
 
@@ -52,6 +96,12 @@ func userServiceInit(_ context.Context, state any) (any, error) {
 		storageSchema.StorageInject,
 		logSchema.LoggerInject,
 		configSchema.ConfigInject,
+		mailSchema.MailerInject,
+		jobsSchema.JobQueueInject,
+		services.UserDataEraserInject,
+		blobsSchema.BlobStoreInject,
+		notificationsSchema.NotifierInject,
+		auditSchema.AuditorInject,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error initializing user service: %w", err)
@@ -74,5 +124,6 @@ func NewUserService() svcSchema.ServiceDefinition {
 		Init:             userServiceInit,
 		DependsOn:        nil,
 		InitHandlersFunc: handlers.AddUserHandlers,
+		InitJobsFunc:     addUserJobs,
 	}
 }
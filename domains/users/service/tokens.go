@@ -0,0 +1,69 @@
+package service
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	"github.com/outcatcher/anwil/domains/users/service/schema"
+)
+
+var errPurposeMismatch = errors.New("token purpose mismatch")
+
+// generateActionToken generates signed single-use token with new unique ID for given wisher and purpose.
+func generateActionToken(
+	wisherUUID, purpose string, ttl time.Duration, key ed25519.PrivateKey,
+) (string, *schema.ActionClaims, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return "", nil, fmt.Errorf("error generating action token: %w", schema.ErrInvalidPrivateKeySize)
+	}
+
+	now := time.Now().UTC()
+
+	claims := &schema.ActionClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   wisherUUID,
+			Issuer:    jwtIssuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+		Purpose: purpose,
+	}
+
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims).SignedString(key)
+	if err != nil {
+		return "", nil, fmt.Errorf("error creating signed string: %w", err)
+	}
+
+	return tokenString, claims, nil
+}
+
+// parseActionToken validates action token signature and purpose, returning token payload.
+//
+// Token single use is not checked here.
+func parseActionToken(tokenString, purpose string, key crypto.PublicKey) (*schema.ActionClaims, error) {
+	claims := new(schema.ActionClaims)
+
+	_, err := jwt.ParseWithClaims(tokenString, claims, Ed25519KeyFunc(key))
+	if err != nil {
+		var validationErr *jwt.ValidationError
+
+		if errors.As(err, &validationErr) {
+			return nil, fmt.Errorf("%w: %w", errbase.ErrUnauthorized, err)
+		}
+
+		return nil, fmt.Errorf("error validating action token: %w", err)
+	}
+
+	if claims.Purpose != purpose {
+		return nil, fmt.Errorf("%w: %w", errbase.ErrUnauthorized, errPurposeMismatch)
+	}
+
+	return claims, nil
+}
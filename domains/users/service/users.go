@@ -2,9 +2,11 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 
+	auditSchema "github.com/outcatcher/anwil/domains/audit/schema"
 	"github.com/outcatcher/anwil/domains/core/errbase"
 	"github.com/outcatcher/anwil/domains/users/service/schema"
 	"github.com/outcatcher/anwil/domains/users/storage"
@@ -17,12 +19,42 @@ func (u *service) GetUser(ctx context.Context, username string) (*schema.User, e
 		return nil, fmt.Errorf("error getting user: %w", err)
 	}
 
-	return &schema.User{
-		UUID:     user.UUID,
-		Username: user.Username,
-		Password: user.Password,
-		FullName: user.FullName,
-	}, nil
+	return u.toUser(user), nil
+}
+
+// GetUserByUUID returns user data by user UUID.
+func (u *service) GetUserByUUID(ctx context.Context, uuid string) (*schema.User, error) {
+	user, err := u.storage.GetUserByUUID(ctx, uuid)
+	if err != nil {
+		return nil, fmt.Errorf("error getting user: %w", err)
+	}
+
+	return u.toUser(user), nil
+}
+
+func (u *service) toUser(wisher *storage.Wisher) *schema.User {
+	user := &schema.User{
+		UUID:          wisher.UUID,
+		Username:      wisher.Username,
+		Password:      wisher.Password,
+		FullName:      wisher.FullName,
+		Role:          wisher.Role,
+		Email:         wisher.Email.String,
+		EmailVerified: wisher.EmailVerified,
+		Avatar:        wisher.Avatar,
+		Currency:      wisher.Currency,
+	}
+
+	if user.Currency == "" {
+		user.Currency = u.cfg.Currency.DefaultCurrency()
+	}
+
+	if wisher.AvatarKey != "" {
+		user.Avatar = u.blobURL(wisher.AvatarKey)
+		user.AvatarThumbnail = u.blobURL(thumbnailKey(wisher.AvatarKey))
+	}
+
+	return user
 }
 
 // SaveUser saves new user data.
@@ -48,15 +80,47 @@ func (u *service) SaveUser(ctx context.Context, user schema.User) error {
 		return err
 	}
 
-	err = u.storage.InsertUser(ctx, storage.Wisher{
+	email := normalizeEmail(user.Email)
+
+	if email != "" {
+		_, err = u.storage.GetUserByEmail(ctx, email)
+
+		switch {
+		case errors.Is(err, errbase.ErrNotFound):
+		case err == nil:
+			return fmt.Errorf("%w: email %s is already used", errbase.ErrConflict, email)
+		default:
+			return fmt.Errorf("error saving user: %w", err)
+		}
+	}
+
+	userUUID, err := u.storage.InsertUser(ctx, storage.Wisher{
 		Username: user.Username,
 		Password: pwd,
 		FullName: user.FullName,
+		Email:    sql.NullString{String: email, Valid: email != ""},
 	})
 	if err != nil {
 		return fmt.Errorf("error saving user: %w", err)
 	}
 
+	u.audit(ctx, auditSchema.Entry{
+		Action:     auditSchema.ActionRegister,
+		ActorUUID:  userUUID,
+		TargetUUID: userUUID,
+		Before:     nil,
+		After:      nil,
+	})
+
+	if email == "" {
+		return nil
+	}
+
+	// user is already created at this point, so verification can be requested again later on failure
+	if err := u.SendEmailVerification(ctx, email); err != nil {
+		u.log.Printf("error requesting verification email for user %s: %s", user.Username, err)
+	}
+
 	return nil
 }
 
@@ -64,6 +128,8 @@ func (u *service) SaveUser(ctx context.Context, user schema.User) error {
 func (u *service) GenerateUserToken(ctx context.Context, user schema.User) (string, error) {
 	existing, err := u.GetUser(ctx, user.Username)
 	if errors.Is(err, errbase.ErrNotFound) {
+		u.auditLoginFailed(ctx, "")
+
 		return "", fmt.Errorf("user %s: %w", user.Username, errbase.ErrNotFound)
 	}
 
@@ -72,13 +138,17 @@ func (u *service) GenerateUserToken(ctx context.Context, user schema.User) (stri
 	}
 
 	err = validatePassword(user.Password, existing.Password, u.privateKey)
+	if errors.Is(err, errbase.ErrUnauthorized) {
+		u.auditLoginFailed(ctx, existing.UUID)
+	}
+
 	if err != nil {
 		return "", fmt.Errorf("error validating user credentials: %w", err)
 	}
 
 	jwtClaims := &schema.Claims{
-		Username: user.Username,
-		UserUUID: user.Username,
+		Username: existing.Username,
+		UserUUID: existing.UUID,
 	}
 
 	tok, err := Generate(jwtClaims, u.privateKey)
@@ -86,5 +156,24 @@ func (u *service) GenerateUserToken(ctx context.Context, user schema.User) (stri
 		return "", fmt.Errorf("error generating user token: %w", err)
 	}
 
+	u.audit(ctx, auditSchema.Entry{
+		Action:     auditSchema.ActionLogin,
+		ActorUUID:  existing.UUID,
+		TargetUUID: existing.UUID,
+		Before:     nil,
+		After:      nil,
+	})
+
 	return tok, nil
 }
+
+// auditLoginFailed records failed login attempt. User UUID is empty for unknown users.
+func (u *service) auditLoginFailed(ctx context.Context, userUUID string) {
+	u.audit(ctx, auditSchema.Entry{
+		Action:     auditSchema.ActionLoginFailed,
+		ActorUUID:  "",
+		TargetUUID: userUUID,
+		Before:     nil,
+		After:      nil,
+	})
+}
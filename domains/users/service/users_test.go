@@ -4,10 +4,13 @@ import (
 	"context"
 	"crypto/ed25519"
 	"database/sql"
-	"database/sql/driver"
 	"encoding/hex"
+	"sync"
 	"testing"
 
+	"github.com/lib/pq"
+	auditSchema "github.com/outcatcher/anwil/domains/audit/schema"
+	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
 	"github.com/outcatcher/anwil/domains/core/errbase"
 	th "github.com/outcatcher/anwil/domains/core/testhelpers"
 	"github.com/outcatcher/anwil/domains/core/validation"
@@ -50,9 +53,35 @@ func (s *UsersSuite) requireEqualPasswords(raw, encrypted string) {
 	require.NoError(s.T(), validatePassword(raw, encrypted, s.privateKey))
 }
 
+// recordingAuditor collects recorded audit entries.
+type recordingAuditor struct {
+	mu      sync.Mutex
+	entries []auditSchema.Entry
+}
+
+func (r *recordingAuditor) Audit(_ context.Context, entry auditSchema.Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = append(r.entries, entry)
+
+	return nil
+}
+
 func (s *UsersSuite) newService(mockDB *th.MockDBExecutor) *service {
+	queue := new(th.MockQueue)
+	queue.On("Enqueue", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	notifier := new(th.MockNotifier)
+	notifier.On("Notify", mock.Anything, mock.Anything).Return(nil)
+
 	return &service{
+		cfg:        new(configSchema.Configuration),
+		db:         mockDB,
 		storage:    userStorage.New(mockDB),
+		queue:      queue,
+		notifier:   notifier,
+		auditor:    new(recordingAuditor),
 		privateKey: s.privateKey,
 	}
 }
@@ -127,9 +156,9 @@ func (s *UsersSuite) TestUsers_SaveUser() {
 			FullName: th.RandomString("full", 10),
 		}
 
-		result := driver.RowsAffected(1)
+		createdUUID := th.RandomString("uuid-", 10)
 
-		createdUser := &userStorage.Wisher{}
+		var createdPassword string
 
 		mockDB := new(th.MockDBExecutor)
 		mockDB.
@@ -138,18 +167,28 @@ func (s *UsersSuite) TestUsers_SaveUser() {
 			).
 			Return(sql.ErrNoRows)
 		mockDB.
-			On("NamedExecContext",
-				ctx, mock.AnythingOfType("string"), mock.AnythingOfType("storage.Wisher"),
+			On("GetContext",
+				ctx, mock.AnythingOfType("*string"), mock.AnythingOfType("string"), mock.Anything,
 			).
 			Run(func(args mock.Arguments) {
-				*createdUser = args.Get(2).(userStorage.Wisher) //nolint:forcetypeassert
+				*(args.Get(1).(*string)) = createdUUID            //nolint:forcetypeassert
+				createdPassword = args.Get(3).([]any)[1].(string) //nolint:forcetypeassert
 			}).
-			Return(result, nil)
+			Return(nil)
 
-		err := s.newService(mockDB).SaveUser(ctx, expectedUser)
+		auditor := new(recordingAuditor)
+
+		users := s.newService(mockDB)
+		users.auditor = auditor
+
+		err := users.SaveUser(ctx, expectedUser)
 		require.NoError(t, err)
 
-		s.requireEqualPasswords(expectedUser.Password, createdUser.Password)
+		s.requireEqualPasswords(expectedUser.Password, createdPassword)
+
+		require.Len(t, auditor.entries, 1)
+		require.Equal(t, auditSchema.ActionRegister, auditor.entries[0].Action)
+		require.Equal(t, createdUUID, auditor.entries[0].TargetUUID)
 	})
 
 	t.Run("existing user", func(t *testing.T) {
@@ -169,6 +208,29 @@ func (s *UsersSuite) TestUsers_SaveUser() {
 		require.ErrorIs(t, err, errbase.ErrConflict)
 	})
 
+	t.Run("concurrent registration", func(t *testing.T) {
+		t.Parallel()
+
+		mockDB := new(th.MockDBExecutor)
+		mockDB.
+			On("GetContext",
+				ctx, new(userStorage.Wisher), mock.AnythingOfType("string"), mock.Anything,
+			).
+			Return(sql.ErrNoRows)
+		mockDB.
+			On("GetContext",
+				ctx, mock.AnythingOfType("*string"), mock.AnythingOfType("string"), mock.Anything,
+			).
+			Return(&pq.Error{Code: "23505"})
+
+		err := s.newService(mockDB).SaveUser(ctx, schema.User{
+			Username: th.RandomString("username", 20),
+			Password: th.RandomString("pwd", 20),
+			Email:    "unique@example.com",
+		})
+		require.ErrorIs(t, err, errbase.ErrConflict)
+	})
+
 	t.Run("simple password", func(t *testing.T) {
 		t.Parallel()
 
@@ -186,6 +248,7 @@ func (s *UsersSuite) TestUsers_SaveUser() {
 
 		mockDB := new(th.MockDBExecutor)
 		users := &service{
+			cfg:        new(configSchema.Configuration),
 			storage:    userStorage.New(mockDB),
 			privateKey: nil,
 		}
@@ -248,9 +311,19 @@ func (s *UsersSuite) TestUsers_GenerateUserToken() {
 			FullName: expectedUser.FullName,
 		}
 
-		token, err := s.newService(mockDB).GenerateUserToken(ctx, testUser)
+		auditor := new(recordingAuditor)
+
+		users := s.newService(mockDB)
+		users.auditor = auditor
+
+		token, err := users.GenerateUserToken(ctx, testUser)
 		require.ErrorIs(t, err, errbase.ErrUnauthorized)
 		require.Empty(t, token)
+
+		require.Len(t, auditor.entries, 1)
+		require.Equal(t, auditSchema.ActionLoginFailed, auditor.entries[0].Action)
+		require.Equal(t, expectedUser.UUID, auditor.entries[0].TargetUUID)
+		require.Nil(t, auditor.entries[0].After)
 	})
 
 	t.Run("valid", func(t *testing.T) {
@@ -287,6 +360,7 @@ func (s *UsersSuite) TestUsers_GenerateUserToken() {
 			Return(nil)
 
 		users := &service{
+			cfg:        new(configSchema.Configuration),
 			storage:    userStorage.New(mockDB),
 			privateKey: nil,
 		}
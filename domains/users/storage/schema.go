@@ -1,11 +1,45 @@
 package storage
 
+import (
+	"database/sql"
+	"time"
+)
+
 // Wisher - entity of `wishers` table.
 type Wisher struct {
-	UUID     string `db:"uuid"`
-	Username string `db:"username"`
-	Password string `db:"password"`
-	FullName string `db:"full_name"`
-	Role     string `db:"role"`
-	Enabled  bool   `db:"enabled"`
+	UUID          string         `db:"uuid"`
+	Username      string         `db:"username"`
+	Password      string         `db:"password"`
+	FullName      string         `db:"full_name"`
+	Role          string         `db:"role"`
+	Enabled       bool           `db:"enabled"`
+	Email         sql.NullString `db:"email"`
+	EmailVerified bool           `db:"email_verified"`
+	Avatar        string         `db:"avatar"`
+	AvatarKey     string         `db:"avatar_key"`
+	Currency      string         `db:"currency"`
+}
+
+// ProfileUpdate - changed `wishers` profile fields. Fields with invalid values are not changed.
+//
+// Setting avatar URL removes uploaded avatar key.
+type ProfileUpdate struct {
+	FullName sql.NullString
+	Avatar   sql.NullString
+	Currency sql.NullString
 }
+
+// WisherToken - entity of `wisher_tokens` table.
+type WisherToken struct {
+	UUID       string       `db:"uuid"`
+	WisherUUID string       `db:"wisher_uuid"`
+	Purpose    string       `db:"purpose"`
+	ExpiresAt  time.Time    `db:"expires_at"`
+	UsedAt     sql.NullTime `db:"used_at"`
+}
+
+// Purposes of `wisher_tokens`, matching `token_purpose` DB type.
+const (
+	PurposeEmailVerification = "email_verification"
+	PurposePasswordReset     = "password_reset"
+)
@@ -9,10 +9,14 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/lib/pq"
 	"github.com/outcatcher/anwil/domains/core/errbase"
 	storageSchema "github.com/outcatcher/anwil/domains/storage/schema"
 )
 
+// uniqueViolation is a Postgres error code of unique constraint violation.
+const uniqueViolation = "23505"
+
 // userStorage - storage of users.
 type userStorage struct {
 	db storageSchema.QueryExecutor
@@ -23,18 +27,29 @@ func New(db storageSchema.QueryExecutor) UserStorage {
 	return &userStorage{db: db}
 }
 
-// InsertUser creates a user.
-func (u *userStorage) InsertUser(ctx context.Context, data Wisher) error {
-	_, err := u.db.NamedExecContext(
+// InsertUser creates a user returning its UUID.
+func (u *userStorage) InsertUser(ctx context.Context, data Wisher) (string, error) {
+	var userUUID string
+
+	err := u.db.GetContext(
 		ctx,
-		`INSERT INTO wishers (username, password, full_name) VALUES (:username, :password, :full_name);`,
-		data,
+		&userUUID,
+		`INSERT INTO wishers (username, password, full_name, email)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING uuid;`,
+		data.Username, data.Password, data.FullName, data.Email,
 	)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return "", fmt.Errorf("%w: username or email is already used", errbase.ErrConflict)
+	}
+
 	if err != nil {
-		return fmt.Errorf("inserting user failed: %w", err)
+		return "", fmt.Errorf("inserting user failed: %w", err)
 	}
 
-	return nil
+	return userUUID, nil
 }
 
 // GetUser returns single user by username.
@@ -52,3 +67,170 @@ func (u *userStorage) GetUser(ctx context.Context, username string) (*Wisher, er
 
 	return user, nil
 }
+
+// GetUserByEmail returns single user by email.
+func (u *userStorage) GetUserByEmail(ctx context.Context, email string) (*Wisher, error) {
+	user := new(Wisher)
+
+	err := u.db.GetContext(ctx, user, `SELECT * FROM wishers WHERE email = $1;`, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("no user found: %w", errbase.ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error selecting user: %w", err)
+	}
+
+	return user, nil
+}
+
+// GetUserByUUID returns single user by UUID.
+func (u *userStorage) GetUserByUUID(ctx context.Context, uuid string) (*Wisher, error) {
+	user := new(Wisher)
+
+	err := u.db.GetContext(ctx, user, `SELECT * FROM wishers WHERE uuid = $1;`, uuid)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("no user found: %w", errbase.ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error selecting user: %w", err)
+	}
+
+	return user, nil
+}
+
+// UpdateProfile changes user profile data.
+func (u *userStorage) UpdateProfile(ctx context.Context, uuid string, update ProfileUpdate) error {
+	result, err := u.db.ExecContext(
+		ctx,
+		`UPDATE wishers
+		 SET full_name  = COALESCE($2, full_name),
+		     avatar     = COALESCE($3, avatar),
+		     avatar_key = CASE WHEN $3::VARCHAR IS NULL THEN avatar_key ELSE '' END,
+		     currency   = COALESCE($4, currency)
+		 WHERE uuid = $1;`,
+		uuid, update.FullName, update.Avatar, update.Currency,
+	)
+	if err != nil {
+		return fmt.Errorf("error updating user profile: %w", err)
+	}
+
+	return requireAffected(result)
+}
+
+// SetUploadedAvatar replaces user avatar with the uploaded one.
+func (u *userStorage) SetUploadedAvatar(ctx context.Context, uuid, key string) error {
+	result, err := u.db.ExecContext(
+		ctx, `UPDATE wishers SET avatar = '', avatar_key = $2 WHERE uuid = $1;`, uuid, key,
+	)
+	if err != nil {
+		return fmt.Errorf("error updating user avatar: %w", err)
+	}
+
+	return requireAffected(result)
+}
+
+// UpdatePassword replaces user password.
+func (u *userStorage) UpdatePassword(ctx context.Context, uuid, password string) error {
+	result, err := u.db.ExecContext(ctx, `UPDATE wishers SET password = $2 WHERE uuid = $1;`, uuid, password)
+	if err != nil {
+		return fmt.Errorf("error updating user password: %w", err)
+	}
+
+	return requireAffected(result)
+}
+
+// DeleteUser deletes user. Related data is deleted by cascade.
+func (u *userStorage) DeleteUser(ctx context.Context, uuid string) error {
+	result, err := u.db.ExecContext(ctx, `DELETE FROM wishers WHERE uuid = $1;`, uuid)
+	if err != nil {
+		return fmt.Errorf("error deleting user: %w", err)
+	}
+
+	return requireAffected(result)
+}
+
+// requireAffected returns ErrNotFound if no rows were affected by the query.
+func requireAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error getting affected rows: %w", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("no user found: %w", errbase.ErrNotFound)
+	}
+
+	return nil
+}
+
+// InsertToken creates a single-use token record.
+func (u *userStorage) InsertToken(ctx context.Context, data WisherToken) error {
+	_, err := u.db.NamedExecContext(
+		ctx,
+		`INSERT INTO wisher_tokens (uuid, wisher_uuid, purpose, expires_at)
+		 VALUES (:uuid, :wisher_uuid, :purpose, :expires_at);`,
+		data,
+	)
+	if err != nil {
+		return fmt.Errorf("inserting token failed: %w", err)
+	}
+
+	return nil
+}
+
+// useTokenQuery marks token as used, returning wisher UUID. Nothing is returned for expired and used tokens.
+const useTokenQuery = `UPDATE wisher_tokens
+SET used_at = now()
+WHERE uuid = $1
+  AND purpose = $2
+  AND used_at IS NULL
+  AND expires_at > now()
+RETURNING wisher_uuid`
+
+// VerifyEmail marks user email as verified, using the token.
+func (u *userStorage) VerifyEmail(ctx context.Context, tokenUUID string) error {
+	var wisherUUID string
+
+	err := u.db.GetContext(
+		ctx,
+		&wisherUUID,
+		`WITH used AS (`+useTokenQuery+`)
+		UPDATE wishers SET email_verified = true FROM used WHERE wishers.uuid = used.wisher_uuid
+		RETURNING wishers.uuid;`,
+		tokenUUID, PurposeEmailVerification,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("no valid token found: %w", errbase.ErrNotFound)
+	}
+
+	if err != nil {
+		return fmt.Errorf("error verifying email: %w", err)
+	}
+
+	return nil
+}
+
+// ResetPassword replaces user password, using the token.
+func (u *userStorage) ResetPassword(ctx context.Context, tokenUUID, password string) error {
+	var wisherUUID string
+
+	err := u.db.GetContext(
+		ctx,
+		&wisherUUID,
+		`WITH used AS (`+useTokenQuery+`)
+		UPDATE wishers SET password = $3 FROM used WHERE wishers.uuid = used.wisher_uuid
+		RETURNING wishers.uuid;`,
+		tokenUUID, PurposePasswordReset, password,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("no valid token found: %w", errbase.ErrNotFound)
+	}
+
+	if err != nil {
+		return fmt.Errorf("error resetting password: %w", err)
+	}
+
+	return nil
+}
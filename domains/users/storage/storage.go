@@ -6,6 +6,18 @@ import (
 
 // UserStorage - хранилище данных пользователя.
 type UserStorage interface {
-	InsertUser(ctx context.Context, data Wisher) error
+	// InsertUser creates a user returning its UUID.
+	// Returns errbase.ErrConflict if username or email is already used.
+	InsertUser(ctx context.Context, data Wisher) (string, error)
 	GetUser(ctx context.Context, username string) (*Wisher, error)
+	GetUserByEmail(ctx context.Context, email string) (*Wisher, error)
+	GetUserByUUID(ctx context.Context, uuid string) (*Wisher, error)
+	UpdateProfile(ctx context.Context, uuid string, update ProfileUpdate) error
+	SetUploadedAvatar(ctx context.Context, uuid, key string) error
+	UpdatePassword(ctx context.Context, uuid, password string) error
+	DeleteUser(ctx context.Context, uuid string) error
+
+	InsertToken(ctx context.Context, data WisherToken) error
+	VerifyEmail(ctx context.Context, tokenUUID string) error
+	ResetPassword(ctx context.Context, tokenUUID, password string) error
 }
@@ -0,0 +1,8 @@
+/*
+Package webhooks contains functions and entities of outgoing webhooks domain.
+
+Users register webhook URLs for notification types. Events are signed with HMAC-SHA256
+using the webhook secret and delivered by background jobs with retries. Admins can register global
+webhooks receiving notifications of all users.
+*/
+package webhooks
@@ -0,0 +1,175 @@
+/*
+Package handlers contains API handlers for outgoing webhooks endpoints.
+*/
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/outcatcher/anwil/domains/core/negotiation"
+	"github.com/outcatcher/anwil/domains/core/services"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/outcatcher/anwil/domains/core/validation"
+	"github.com/outcatcher/anwil/domains/users/auth"
+	usersSchema "github.com/outcatcher/anwil/domains/users/service/schema"
+	"github.com/outcatcher/anwil/domains/webhooks/service/schema"
+)
+
+type webhookRequest struct {
+	URL        string   `json:"url" form:"url" validate:"required"`
+	EventTypes []string `json:"event_types" form:"event_types" validate:"required,min=1,dive,required"`
+}
+
+// AddWebhookHandlers - adds webhook-related endpoints.
+func AddWebhookHandlers(state svcSchema.ProvidingServices) svcSchema.AddHandlersFunc {
+	return func(_, secGroup *echo.Group) error {
+		webhookService, err := services.GetServiceFromProvider[schema.WebhookService](state, schema.ServiceID)
+		if err != nil {
+			return fmt.Errorf("error adding webhook handlers: %w", err)
+		}
+
+		userService, err := services.GetServiceFromProvider[usersSchema.UserService](state, usersSchema.ServiceID)
+		if err != nil {
+			return fmt.Errorf("error adding webhook handlers: %w", err)
+		}
+
+		addScopeHandlers(secGroup.Group("/me/webhooks"), webhookService, schema.ScopeUser)
+		addScopeHandlers(
+			secGroup.Group("/admin/webhooks", auth.RequireAdmin(userService)), webhookService, schema.ScopeGlobal,
+		)
+
+		return nil
+	}
+}
+
+// addScopeHandlers adds endpoints managing webhooks of the scope to the group.
+func addScopeHandlers(group *echo.Group, webhookService schema.WebhookService, scope string) {
+	group.POST("", handleCreateWebhook(webhookService, scope), negotiation.Consumes(negotiation.DataTypes...))
+	group.GET("", handleListWebhooks(webhookService, scope))
+	group.DELETE("/:uuid", handleDeleteWebhook(webhookService, scope))
+	group.GET("/:uuid/deliveries", handleListDeliveries(webhookService, scope))
+	// test is requested without body
+	group.POST("/:uuid/test", handleTestWebhook(webhookService, scope), negotiation.Consumes())
+}
+
+// webhookUUIDParam returns validated webhook UUID path parameter.
+func webhookUUIDParam(c echo.Context) (string, error) {
+	value := c.Param("uuid")
+
+	if _, err := uuid.Parse(value); err != nil {
+		return "", fmt.Errorf("%w: invalid webhook UUID %q", validation.ErrValidationFailed, value)
+	}
+
+	return value, nil
+}
+
+func handleCreateWebhook(wh schema.WebhookService, scope string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error creating webhook: %w", err)
+		}
+
+		req := new(webhookRequest)
+
+		if err := c.Bind(req); err != nil {
+			return fmt.Errorf("error binding request: %w", err)
+		}
+
+		if err := validation.ValidateJSONCtx(c.Request().Context(), req); err != nil {
+			return fmt.Errorf("error validating request: %w", err)
+		}
+
+		webhook, err := wh.CreateWebhook(c.Request().Context(), claims.UserUUID, schema.WebhookInput{
+			Scope:      scope,
+			URL:        req.URL,
+			EventTypes: req.EventTypes,
+		})
+		if err != nil {
+			return fmt.Errorf("error creating webhook: %w", err)
+		}
+
+		return negotiation.Respond(c, http.StatusCreated, webhook)
+	}
+}
+
+func handleListWebhooks(wh schema.WebhookService, scope string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error listing webhooks: %w", err)
+		}
+
+		webhooks, err := wh.ListWebhooks(c.Request().Context(), claims.UserUUID, scope)
+		if err != nil {
+			return fmt.Errorf("error listing webhooks: %w", err)
+		}
+
+		return negotiation.Respond(c, http.StatusOK, webhooks)
+	}
+}
+
+func handleDeleteWebhook(wh schema.WebhookService, scope string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error deleting webhook: %w", err)
+		}
+
+		webhookUUID, err := webhookUUIDParam(c)
+		if err != nil {
+			return fmt.Errorf("error deleting webhook: %w", err)
+		}
+
+		if err := wh.DeleteWebhook(c.Request().Context(), claims.UserUUID, scope, webhookUUID); err != nil {
+			return fmt.Errorf("error deleting webhook: %w", err)
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func handleListDeliveries(wh schema.WebhookService, scope string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error listing webhook deliveries: %w", err)
+		}
+
+		webhookUUID, err := webhookUUIDParam(c)
+		if err != nil {
+			return fmt.Errorf("error listing webhook deliveries: %w", err)
+		}
+
+		deliveries, err := wh.ListDeliveries(c.Request().Context(), claims.UserUUID, scope, webhookUUID)
+		if err != nil {
+			return fmt.Errorf("error listing webhook deliveries: %w", err)
+		}
+
+		return negotiation.Respond(c, http.StatusOK, deliveries)
+	}
+}
+
+func handleTestWebhook(wh schema.WebhookService, scope string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.ClaimsFromContext(c)
+		if err != nil {
+			return fmt.Errorf("error testing webhook: %w", err)
+		}
+
+		webhookUUID, err := webhookUUIDParam(c)
+		if err != nil {
+			return fmt.Errorf("error testing webhook: %w", err)
+		}
+
+		delivery, err := wh.TestWebhook(c.Request().Context(), claims.UserUUID, scope, webhookUUID)
+		if err != nil {
+			return fmt.Errorf("error testing webhook: %w", err)
+		}
+
+		return negotiation.Respond(c, http.StatusAccepted, delivery)
+	}
+}
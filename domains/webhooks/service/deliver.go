@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	"github.com/outcatcher/anwil/domains/core/services"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/outcatcher/anwil/domains/core/validation"
+	"github.com/outcatcher/anwil/domains/jobs"
+	jobsSchema "github.com/outcatcher/anwil/domains/jobs/schema"
+	"github.com/outcatcher/anwil/domains/webhooks/service/schema"
+	"github.com/outcatcher/anwil/domains/webhooks/storage"
+)
+
+const (
+	deliverJobKind = "webhooks.deliver"
+	// deliveryAttempts - with exponential backoff of job runner, the last attempt is made ~20 minutes after the first
+	deliveryAttempts = 8
+
+	// maxResponseBody - max number of response bytes read, response body is ignored
+	maxResponseBody = 64 << 10
+
+	userAgent = "Anwil-Webhook/1.0"
+)
+
+// Webhook request headers.
+const (
+	HeaderEvent     = "X-Anwil-Event"
+	HeaderDelivery  = "X-Anwil-Delivery"
+	HeaderTimestamp = "X-Anwil-Timestamp"
+	HeaderSignature = "X-Anwil-Signature"
+)
+
+// deliverPayload - payload of the job delivering event to the webhook.
+type deliverPayload struct {
+	DeliveryUUID string `json:"delivery_uuid"`
+}
+
+// addWebhookJobs registers webhooks service jobs.
+func addWebhookJobs(state svcSchema.ProvidingServices) svcSchema.AddJobsFunc {
+	return func(registry svcSchema.JobRegistry) error {
+		svc, err := services.GetServiceFromProvider[*service](state, schema.ServiceID)
+		if err != nil {
+			return fmt.Errorf("error adding webhook jobs: %w", err)
+		}
+
+		if err := registry.Handle(deliverJobKind, jobs.Typed(svc.deliver)); err != nil {
+			return fmt.Errorf("error adding webhook jobs: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// Sign returns signature of the webhook request: hex-encoded HMAC-SHA256 of "<timestamp>.<body>".
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// createDelivery stores pending delivery of the event and enqueues sending it.
+func (s *service) createDelivery(
+	ctx context.Context, webhook *storage.Webhook, eventType string, data any,
+) (*storage.Delivery, error) {
+	deliveryUUID := uuid.NewString()
+
+	body, err := json.Marshal(schema.Payload{
+		ID:        deliveryUUID,
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding webhook payload: %w", err)
+	}
+
+	delivery, err := s.storage.InsertDelivery(ctx, storage.Delivery{ //nolint:exhaustruct
+		UUID:        deliveryUUID,
+		WebhookUUID: webhook.UUID,
+		EventType:   eventType,
+		Payload:     string(body),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating webhook delivery: %w", err)
+	}
+
+	err = s.queue.Enqueue(
+		ctx, deliverJobKind, deliverPayload{DeliveryUUID: delivery.UUID}, jobsSchema.MaxAttempts(deliveryAttempts),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error enqueuing webhook delivery: %w", err)
+	}
+
+	return delivery, nil
+}
+
+// Dispatch sends event to all webhooks of the user subscribed to the event type.
+func (s *service) Dispatch(ctx context.Context, userUUID, eventType string, data any) error {
+	webhooks, err := s.storage.ListSubscribedWebhooks(ctx, userUUID, eventType)
+	if err != nil {
+		return fmt.Errorf("error dispatching webhook event: %w", err)
+	}
+
+	return s.dispatch(ctx, webhooks, eventType, data)
+}
+
+// DispatchGlobal sends event to all global webhooks of admins subscribed to the event type.
+func (s *service) DispatchGlobal(ctx context.Context, eventType string, data any) error {
+	webhooks, err := s.storage.ListGlobalWebhooks(ctx, eventType)
+	if err != nil {
+		return fmt.Errorf("error dispatching global webhook event: %w", err)
+	}
+
+	return s.dispatch(ctx, webhooks, eventType, data)
+}
+
+// dispatchEvent sends event published to the event bus to the webhooks of its scope.
+func (s *service) dispatchEvent(ctx context.Context, event schema.Event) error {
+	if event.Scope == schema.ScopeGlobal {
+		return s.DispatchGlobal(ctx, event.Type, event.Data)
+	}
+
+	return s.Dispatch(ctx, event.UserUUID, event.Type, event.Data)
+}
+
+// dispatch creates deliveries of the event to given webhooks.
+func (s *service) dispatch(ctx context.Context, webhooks []storage.Webhook, eventType string, data any) error {
+	for i := range webhooks {
+		if _, err := s.createDelivery(ctx, &webhooks[i], eventType, data); err != nil {
+			return fmt.Errorf("error dispatching webhook event: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// TestWebhook sends sample event to the webhook.
+func (s *service) TestWebhook(ctx context.Context, userUUID, scope, webhookUUID string) (*schema.Delivery, error) {
+	webhook, err := s.userWebhook(ctx, userUUID, scope, webhookUUID)
+	if err != nil {
+		return nil, fmt.Errorf("error testing webhook: %w", err)
+	}
+
+	delivery, err := s.createDelivery(ctx, webhook, schema.TypeTest, map[string]string{
+		"webhook_uuid": webhook.UUID,
+		"message":      "This is a test event from Anwil",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error testing webhook: %w", err)
+	}
+
+	return toDelivery(delivery), nil
+}
+
+// deliver sends pending delivery to the webhook, recording the attempt.
+//
+// Deliveries of removed webhooks are skipped.
+func (s *service) deliver(ctx context.Context, payload deliverPayload) error {
+	delivery, err := s.storage.GetDelivery(ctx, payload.DeliveryUUID)
+	if errors.Is(err, errbase.ErrNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("error delivering webhook: %w", err)
+	}
+
+	if delivery.Status != schema.StatusPending {
+		return nil
+	}
+
+	webhook, err := s.storage.GetWebhook(ctx, delivery.WebhookUUID)
+	if errors.Is(err, errbase.ErrNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("error delivering webhook: %w", err)
+	}
+
+	attempt := s.send(ctx, webhook, delivery)
+
+	if attempt.Status == schema.StatusPending && jobsSchema.IsLastAttempt(ctx) {
+		attempt.Status = schema.StatusFailed
+	}
+
+	if err := s.storage.RecordAttempt(ctx, delivery.UUID, attempt); err != nil {
+		return fmt.Errorf("error delivering webhook: %w", err)
+	}
+
+	switch attempt.Status {
+	case schema.StatusDelivered:
+		return nil
+	case schema.StatusFailed:
+		return fmt.Errorf("%w: webhook delivery %s failed: %s", jobsSchema.ErrPermanent, delivery.UUID, attempt.Error)
+	default:
+		return fmt.Errorf("%w: webhook delivery %s failed: %s", errbase.ErrUpstream, delivery.UUID, attempt.Error)
+	}
+}
+
+// send makes single delivery attempt.
+func (s *service) send(ctx context.Context, webhook *storage.Webhook, delivery *storage.Delivery) storage.Attempt {
+	attempt := storage.Attempt{Status: schema.StatusPending, ResponseStatus: sql.NullInt32{}, Error: ""}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, strings.NewReader(delivery.Payload))
+	if err != nil {
+		attempt.Status = schema.StatusFailed
+		attempt.Error = err.Error()
+
+		return attempt
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, delivery.UUID)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, "sha256="+Sign(webhook.Secret, timestamp, []byte(delivery.Payload)))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		attempt.Error = err.Error()
+
+		// URL resolving to internal address won't change on retry
+		if errors.Is(err, validation.ErrValidationFailed) {
+			attempt.Status = schema.StatusFailed
+		}
+
+		return attempt
+	}
+
+	defer func() {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			s.log.Println("error closing webhook response body:", closeErr)
+		}
+	}()
+
+	attempt.ResponseStatus = sql.NullInt32{Int32: int32(resp.StatusCode), Valid: true}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		attempt.Error = fmt.Sprintf("unexpected response status %d", resp.StatusCode)
+
+		return attempt
+	}
+
+	attempt.Status = schema.StatusDelivered
+
+	return attempt
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/outcatcher/anwil/domains/webhooks/service/schema"
+)
+
+// ExportUserData returns webhooks of the user for personal data export. Secrets are not exported.
+//
+// Webhooks are removed with the user, so there is no PrepareErasure.
+func (s *service) ExportUserData(ctx context.Context, userUUID string) (*svcSchema.UserDataExport, error) {
+	webhooks, err := s.ListWebhooks(ctx, userUUID, schema.ScopeUser)
+	if err != nil {
+		return nil, fmt.Errorf("error exporting webhooks: %w", err)
+	}
+
+	global, err := s.ListWebhooks(ctx, userUUID, schema.ScopeGlobal)
+	if err != nil {
+		return nil, fmt.Errorf("error exporting webhooks: %w", err)
+	}
+
+	webhooks = append(webhooks, global...)
+
+	return &svcSchema.UserDataExport{Data: webhooks, Files: nil}, nil
+}
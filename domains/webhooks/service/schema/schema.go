@@ -0,0 +1,171 @@
+/*
+Package schema contains service definition for Webhooks service
+*/
+package schema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/outcatcher/anwil/domains/core/services"
+	"github.com/outcatcher/anwil/domains/core/services/schema"
+	eventsSchema "github.com/outcatcher/anwil/domains/events/schema"
+)
+
+// ServiceID - ID for webhooks service.
+const ServiceID schema.ServiceID = "webhooks"
+
+// TypeTest - type of the sample event sent by WebhookService.TestWebhook.
+const TypeTest = "webhook.test"
+
+// Webhook scopes.
+const (
+	// ScopeUser - webhook receives notifications of its owner
+	ScopeUser = "user"
+	// ScopeGlobal - admin webhook receiving notifications of all users
+	ScopeGlobal = "global"
+)
+
+// Delivery statuses.
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+)
+
+// WebhookService - service handling outgoing webhooks of the user.
+//
+// Webhooks are managed within the scope, i.e. webhooks of ScopeGlobal are not visible with ScopeUser.
+type WebhookService interface {
+	// CreateWebhook registers new webhook. Returned webhook contains secret used for signing payloads.
+	CreateWebhook(ctx context.Context, userUUID string, input WebhookInput) (*Webhook, error)
+	// ListWebhooks returns webhooks of the user in the scope. Secrets are not returned.
+	ListWebhooks(ctx context.Context, userUUID, scope string) ([]Webhook, error)
+	// DeleteWebhook removes webhook of the user with its delivery log.
+	DeleteWebhook(ctx context.Context, userUUID, scope, webhookUUID string) error
+	// ListDeliveries returns latest deliveries of the webhook, newest first.
+	ListDeliveries(ctx context.Context, userUUID, scope, webhookUUID string) ([]Delivery, error)
+	// TestWebhook sends sample event to the webhook.
+	TestWebhook(ctx context.Context, userUUID, scope, webhookUUID string) (*Delivery, error)
+
+	Dispatcher
+}
+
+// Dispatcher sends events to webhooks.
+type Dispatcher interface {
+	// Dispatch sends event to all webhooks of the user subscribed to the event type.
+	Dispatch(ctx context.Context, userUUID, eventType string, data any) error
+	// DispatchGlobal sends event to all global webhooks of admins subscribed to the event type.
+	DispatchGlobal(ctx context.Context, eventType string, data any) error
+}
+
+// RequiresWebhookDispatcher defines service which can use webhook dispatcher attached.
+type RequiresWebhookDispatcher interface {
+	UseWebhookDispatcher(dispatcher Dispatcher)
+}
+
+// WebhookDispatcherInject adds webhook dispatcher publishing events to the event bus to the service.
+//
+// Events are sent by webhooks service subscribed to them.
+func WebhookDispatcherInject(consumer, provider any) error {
+	reqDispatcher, provBus, err := services.ValidateArgInterfaces[
+		RequiresWebhookDispatcher, eventsSchema.WithEventBus,
+	](consumer, provider)
+	if err != nil {
+		return fmt.Errorf("error injecting webhook dispatcher: %w", err)
+	}
+
+	reqDispatcher.UseWebhookDispatcher(busDispatcher{bus: provBus.EventBus()})
+
+	return nil
+}
+
+// Event - event to be sent to the webhooks. Events are published to the event bus.
+type Event struct {
+	// Scope is ScopeUser for webhooks of the user and ScopeGlobal for global webhooks of admins
+	Scope string `json:"scope"`
+	// UserUUID is UUID of the user owning webhooks of ScopeUser
+	UserUUID string `json:"user_uuid,omitempty"`
+	// Type is the event type webhooks are subscribed to
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// EventType returns type of the event sent to webhooks.
+func (Event) EventType() string {
+	return "webhooks.event"
+}
+
+// busDispatcher publishes webhook events to the event bus.
+type busDispatcher struct {
+	bus eventsSchema.Publisher
+}
+
+// Dispatch publishes event to be sent to all webhooks of the user subscribed to the event type.
+func (d busDispatcher) Dispatch(ctx context.Context, userUUID, eventType string, data any) error {
+	return d.publish(ctx, Event{Scope: ScopeUser, UserUUID: userUUID, Type: eventType, Data: nil}, data)
+}
+
+// DispatchGlobal publishes event to be sent to all global webhooks of admins subscribed to the event type.
+func (d busDispatcher) DispatchGlobal(ctx context.Context, eventType string, data any) error {
+	return d.publish(ctx, Event{Scope: ScopeGlobal, UserUUID: "", Type: eventType, Data: nil}, data)
+}
+
+func (d busDispatcher) publish(ctx context.Context, event Event, data any) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("error encoding webhook event data: %w", err)
+	}
+
+	event.Data = encoded
+
+	if err := d.bus.Publish(ctx, event); err != nil {
+		return fmt.Errorf("error dispatching webhook event: %w", err)
+	}
+
+	return nil
+}
+
+// WebhookInput - attributes of the new webhook.
+type WebhookInput struct {
+	// Scope is one of ScopeUser or ScopeGlobal
+	Scope string
+	URL   string
+	// EventTypes are notification types sent to the webhook
+	EventTypes []string
+}
+
+// Webhook - registered webhook.
+type Webhook struct {
+	UUID       string   `json:"uuid"`
+	Scope      string   `json:"scope"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	// Secret is returned on creation only
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Delivery - single event delivery to the webhook.
+type Delivery struct {
+	UUID      string `json:"uuid"`
+	EventType string `json:"event_type"`
+	Status    string `json:"status"`
+	Attempts  int    `json:"attempts"`
+	// ResponseStatus is HTTP status of the last attempt, empty if request failed
+	ResponseStatus *int       `json:"response_status,omitempty"`
+	Error          string     `json:"error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	FinishedAt     *time.Time `json:"finished_at,omitempty"`
+}
+
+// Payload - body of the webhook request.
+type Payload struct {
+	// ID is a delivery UUID, the same for all attempts
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
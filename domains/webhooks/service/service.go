@@ -0,0 +1,102 @@
+/*
+Package service contains webhooks service methods
+*/
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	configSchema "github.com/outcatcher/anwil/domains/core/config/schema"
+	logSchema "github.com/outcatcher/anwil/domains/core/logging/schema"
+	"github.com/outcatcher/anwil/domains/core/safehttp"
+	"github.com/outcatcher/anwil/domains/core/services"
+	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
+	"github.com/outcatcher/anwil/domains/events"
+	eventsSchema "github.com/outcatcher/anwil/domains/events/schema"
+	jobsSchema "github.com/outcatcher/anwil/domains/jobs/schema"
+	storageSchema "github.com/outcatcher/anwil/domains/storage/schema"
+	"github.com/outcatcher/anwil/domains/webhooks/handlers"
+	"github.com/outcatcher/anwil/domains/webhooks/service/schema"
+	webhookStorage "github.com/outcatcher/anwil/domains/webhooks/storage"
+)
+
+const defaultTimeout = 10 * time.Second
+
+// service - outgoing webhooks service.
+type service struct {
+	cfg     *configSchema.Configuration
+	storage webhookStorage.WebhookStorage
+
+	log   *log.Logger
+	queue jobsSchema.Queue
+	bus   eventsSchema.Bus
+
+	client *http.Client
+}
+
+// UseConfig attaches configuration to the service.
+func (s *service) UseConfig(configuration *configSchema.Configuration) {
+	s.cfg = configuration
+}
+
+// UseStorage attaches given DB storage to the service.
+func (s *service) UseStorage(db storageSchema.QueryExecutor) {
+	s.storage = webhookStorage.New(db)
+}
+
+// UseLogger attaches logger to the service.
+func (s *service) UseLogger(logger *log.Logger) {
+	s.log = logger
+}
+
+// UseJobQueue attaches background job queue to the service.
+func (s *service) UseJobQueue(queue jobsSchema.Queue) {
+	s.queue = queue
+}
+
+// UseEventBus attaches domain event bus to the service.
+func (s *service) UseEventBus(bus eventsSchema.Bus) {
+	s.bus = bus
+}
+
+func webhookServiceInit(_ context.Context, state any) (any, error) {
+	svc := new(service)
+
+	err := services.InjectServiceWith(
+		svc, state,
+		storageSchema.StorageInject,
+		logSchema.LoggerInject,
+		configSchema.ConfigInject,
+		jobsSchema.JobQueueInject,
+		eventsSchema.EventBusInject,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing webhooks service: %w", err)
+	}
+
+	events.Subscribe(svc.bus, eventsSchema.Sync, svc.dispatchEvent)
+
+	timeout := svc.cfg.Webhooks.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+
+	svc.client = safehttp.NewClient(timeout, svc.cfg.Webhooks.AllowPrivate)
+
+	return svc, nil
+}
+
+// NewWebhookService returns new webhooks service definition.
+func NewWebhookService() svcSchema.ServiceDefinition {
+	return svcSchema.ServiceDefinition{
+		ID:               schema.ServiceID,
+		Init:             webhookServiceInit,
+		DependsOn:        nil,
+		InitHandlersFunc: handlers.AddWebhookHandlers,
+		InitJobsFunc:     addWebhookJobs,
+	}
+}
@@ -0,0 +1,412 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	"github.com/outcatcher/anwil/domains/core/safehttp"
+	th "github.com/outcatcher/anwil/domains/core/testhelpers"
+	"github.com/outcatcher/anwil/domains/core/validation"
+	jobsSchema "github.com/outcatcher/anwil/domains/jobs/schema"
+	notificationsSchema "github.com/outcatcher/anwil/domains/notifications/schema"
+	"github.com/outcatcher/anwil/domains/webhooks/service/schema"
+	"github.com/outcatcher/anwil/domains/webhooks/storage"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockStorage struct {
+	mock.Mock
+}
+
+func (m *mockStorage) InsertWebhook(ctx context.Context, webhook storage.Webhook) (*storage.Webhook, error) {
+	args := m.Called(ctx, webhook)
+
+	return args.Get(0).(*storage.Webhook), args.Error(1) //nolint:forcetypeassert
+}
+
+func (m *mockStorage) GetWebhook(ctx context.Context, uuid string) (*storage.Webhook, error) {
+	args := m.Called(ctx, uuid)
+
+	return args.Get(0).(*storage.Webhook), args.Error(1) //nolint:forcetypeassert
+}
+
+func (m *mockStorage) ListWebhooks(ctx context.Context, wisherUUID, scope string) ([]storage.Webhook, error) {
+	args := m.Called(ctx, wisherUUID, scope)
+
+	return args.Get(0).([]storage.Webhook), args.Error(1) //nolint:forcetypeassert
+}
+
+func (m *mockStorage) ListSubscribedWebhooks(
+	ctx context.Context, wisherUUID, eventType string,
+) ([]storage.Webhook, error) {
+	args := m.Called(ctx, wisherUUID, eventType)
+
+	return args.Get(0).([]storage.Webhook), args.Error(1) //nolint:forcetypeassert
+}
+
+func (m *mockStorage) ListGlobalWebhooks(ctx context.Context, eventType string) ([]storage.Webhook, error) {
+	args := m.Called(ctx, eventType)
+
+	return args.Get(0).([]storage.Webhook), args.Error(1) //nolint:forcetypeassert
+}
+
+func (m *mockStorage) DeleteWebhook(ctx context.Context, wisherUUID, scope, uuid string) error {
+	return m.Called(ctx, wisherUUID, scope, uuid).Error(0)
+}
+
+func (m *mockStorage) InsertDelivery(ctx context.Context, delivery storage.Delivery) (*storage.Delivery, error) {
+	args := m.Called(ctx, delivery)
+
+	if inserted, ok := args.Get(0).(func(context.Context, storage.Delivery) *storage.Delivery); ok {
+		return inserted(ctx, delivery), args.Error(1)
+	}
+
+	return args.Get(0).(*storage.Delivery), args.Error(1) //nolint:forcetypeassert
+}
+
+func (m *mockStorage) GetDelivery(ctx context.Context, uuid string) (*storage.Delivery, error) {
+	args := m.Called(ctx, uuid)
+
+	return args.Get(0).(*storage.Delivery), args.Error(1) //nolint:forcetypeassert
+}
+
+func (m *mockStorage) ListDeliveries(ctx context.Context, webhookUUID string, limit int) ([]storage.Delivery, error) {
+	args := m.Called(ctx, webhookUUID, limit)
+
+	return args.Get(0).([]storage.Delivery), args.Error(1) //nolint:forcetypeassert
+}
+
+func (m *mockStorage) RecordAttempt(ctx context.Context, uuid string, attempt storage.Attempt) error {
+	return m.Called(ctx, uuid, attempt).Error(0)
+}
+
+func newTestService(store storage.WebhookStorage, allowPrivate bool) *service {
+	return &service{
+		storage: store,
+		log:     log.Default(),
+		client:  safehttp.NewClient(time.Second, allowPrivate),
+	}
+}
+
+func TestCreateWebhook(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	userUUID := th.RandomString("user-", 10)
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		store := new(mockStorage)
+		store.On("ListWebhooks", ctx, userUUID, schema.ScopeGlobal).Return([]storage.Webhook{}, nil)
+		store.
+			On("InsertWebhook", ctx, mock.MatchedBy(func(webhook storage.Webhook) bool {
+				return webhook.URL == "https://bot.example.com/hook" && len(webhook.Secret) == 2*secretSize &&
+					len(webhook.EventTypes) == 1 && webhook.Scope == schema.ScopeGlobal
+			})).
+			Return(&storage.Webhook{UUID: "1", Scope: schema.ScopeGlobal, Secret: "secret"}, nil)
+
+		webhook, err := newTestService(store, false).CreateWebhook(ctx, userUUID, schema.WebhookInput{
+			Scope:      schema.ScopeGlobal,
+			URL:        " https://bot.example.com/hook ",
+			EventTypes: []string{notificationsSchema.TypeExportReady, notificationsSchema.TypeExportReady},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "secret", webhook.Secret)
+		require.Equal(t, schema.ScopeGlobal, webhook.Scope)
+	})
+
+	t.Run("too many", func(t *testing.T) {
+		t.Parallel()
+
+		store := new(mockStorage)
+		store.On("ListWebhooks", ctx, userUUID, schema.ScopeUser).Return(make([]storage.Webhook, maxWebhooks), nil)
+
+		_, err := newTestService(store, false).CreateWebhook(ctx, userUUID, schema.WebhookInput{
+			Scope:      schema.ScopeUser,
+			URL:        "https://bot.example.com/hook",
+			EventTypes: []string{notificationsSchema.TypeExportReady},
+		})
+		require.ErrorIs(t, err, errbase.ErrConflict)
+	})
+
+	valid := schema.WebhookInput{
+		Scope:      schema.ScopeUser,
+		URL:        "https://bot.example.com/hook",
+		EventTypes: []string{notificationsSchema.TypeExportReady},
+	}
+
+	invalid := map[string]func(input *schema.WebhookInput){
+		"scope":           func(input *schema.WebhookInput) { input.Scope = "admin" },
+		"url":             func(input *schema.WebhookInput) { input.URL = "ftp://example.com" },
+		"no event types":  func(input *schema.WebhookInput) { input.EventTypes = nil },
+		"unknown type":    func(input *schema.WebhookInput) { input.EventTypes = []string{"unknown"} },
+		"test event type": func(input *schema.WebhookInput) { input.EventTypes = []string{schema.TypeTest} },
+		"personal global type": func(input *schema.WebhookInput) {
+			input.Scope = schema.ScopeGlobal
+			input.EventTypes = []string{notificationsSchema.TypeSantaInvite}
+		},
+	}
+
+	for name, modify := range invalid {
+		name, modify := name, modify
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			input := valid
+			modify(&input)
+
+			_, err := newTestService(new(mockStorage), false).CreateWebhook(ctx, userUUID, input)
+			require.ErrorIs(t, err, validation.ErrValidationFailed)
+		})
+	}
+}
+
+func TestListDeliveriesOfOtherUser(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	store := new(mockStorage)
+	store.
+		On("GetWebhook", ctx, "1").
+		Return(&storage.Webhook{UUID: "1", WisherUUID: "owner", Scope: schema.ScopeUser}, nil)
+
+	_, err := newTestService(store, false).ListDeliveries(ctx, "other", schema.ScopeUser, "1")
+	require.ErrorIs(t, err, errbase.ErrNotFound)
+
+	// user webhooks are not available in admin scope
+	_, err = newTestService(store, false).ListDeliveries(ctx, "owner", schema.ScopeGlobal, "1")
+	require.ErrorIs(t, err, errbase.ErrNotFound)
+
+	store.AssertNotCalled(t, "ListDeliveries")
+}
+
+func TestDispatch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	userUUID := th.RandomString("user-", 10)
+
+	store := new(mockStorage)
+	store.
+		On("ListSubscribedWebhooks", ctx, userUUID, notificationsSchema.TypeExportReady).
+		Return([]storage.Webhook{{UUID: "1"}, {UUID: "2"}}, nil)
+	store.
+		On("InsertDelivery", ctx, mock.AnythingOfType("storage.Delivery")).
+		Return(func(_ context.Context, delivery storage.Delivery) *storage.Delivery {
+			return &delivery
+		}, nil)
+
+	queue := new(th.MockQueue)
+	queue.On("Enqueue", ctx, deliverJobKind, mock.AnythingOfType("service.deliverPayload")).Return(nil)
+
+	svc := newTestService(store, false)
+	svc.queue = queue
+
+	require.NoError(t, svc.Dispatch(ctx, userUUID, notificationsSchema.TypeExportReady, map[string]string{"a": "b"}))
+
+	store.AssertNumberOfCalls(t, "InsertDelivery", 2)
+	queue.AssertNumberOfCalls(t, "Enqueue", 2)
+}
+
+func TestDispatchGlobal(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	store := new(mockStorage)
+	store.
+		On("ListGlobalWebhooks", ctx, notificationsSchema.TypeExportReady).
+		Return([]storage.Webhook{{UUID: "1", Scope: schema.ScopeGlobal}}, nil)
+	store.
+		On("InsertDelivery", ctx, mock.MatchedBy(func(delivery storage.Delivery) bool {
+			return delivery.WebhookUUID == "1"
+		})).
+		Return(func(_ context.Context, delivery storage.Delivery) *storage.Delivery {
+			return &delivery
+		}, nil)
+
+	queue := new(th.MockQueue)
+	queue.On("Enqueue", ctx, deliverJobKind, mock.AnythingOfType("service.deliverPayload")).Return(nil)
+
+	svc := newTestService(store, false)
+	svc.queue = queue
+
+	require.NoError(t, svc.DispatchGlobal(ctx, notificationsSchema.TypeExportReady, map[string]string{"a": "b"}))
+
+	store.AssertNotCalled(t, "ListSubscribedWebhooks")
+	queue.AssertNumberOfCalls(t, "Enqueue", 1)
+}
+
+func TestDeliver(t *testing.T) {
+	t.Parallel()
+
+	const secret = "secret"
+
+	newDelivery := func(webhookURL string) (*storage.Webhook, *storage.Delivery) {
+		webhook := &storage.Webhook{UUID: th.RandomString("hook-", 10), URL: webhookURL, Secret: secret}
+		delivery := &storage.Delivery{
+			UUID:        th.RandomString("delivery-", 10),
+			WebhookUUID: webhook.UUID,
+			EventType:   schema.TypeTest,
+			Payload:     `{"id":"1"}`,
+			Status:      schema.StatusPending,
+		}
+
+		return webhook, delivery
+	}
+
+	newStore := func(
+		ctx context.Context, webhook *storage.Webhook, delivery *storage.Delivery, status string,
+	) *mockStorage {
+		store := new(mockStorage)
+		store.On("GetDelivery", ctx, delivery.UUID).Return(delivery, nil)
+		store.On("GetWebhook", ctx, webhook.UUID).Return(webhook, nil)
+		store.
+			On("RecordAttempt", ctx, delivery.UUID, mock.MatchedBy(func(attempt storage.Attempt) bool {
+				return attempt.Status == status
+			})).
+			Return(nil)
+
+		return store
+	}
+
+	t.Run("delivered", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := jobsSchema.WithAttempt(context.Background(), 1, deliveryAttempts)
+
+		var received *http.Request
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+
+			require.Equal(t, `{"id":"1"}`, string(body))
+			require.Equal(t,
+				"sha256="+Sign(secret, r.Header.Get(HeaderTimestamp), body), r.Header.Get(HeaderSignature),
+			)
+
+			received = r
+
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		t.Cleanup(server.Close)
+
+		webhook, delivery := newDelivery(server.URL)
+		store := newStore(ctx, webhook, delivery, schema.StatusDelivered)
+
+		require.NoError(t, newTestService(store, true).deliver(ctx, deliverPayload{DeliveryUUID: delivery.UUID}))
+
+		require.NotNil(t, received)
+		require.Equal(t, schema.TypeTest, received.Header.Get(HeaderEvent))
+		require.Equal(t, delivery.UUID, received.Header.Get(HeaderDelivery))
+	})
+
+	t.Run("retried", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := jobsSchema.WithAttempt(context.Background(), 1, deliveryAttempts)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		t.Cleanup(server.Close)
+
+		webhook, delivery := newDelivery(server.URL)
+		store := newStore(ctx, webhook, delivery, schema.StatusPending)
+
+		err := newTestService(store, true).deliver(ctx, deliverPayload{DeliveryUUID: delivery.UUID})
+		require.ErrorIs(t, err, errbase.ErrUpstream)
+		require.NotErrorIs(t, err, jobsSchema.ErrPermanent)
+	})
+
+	t.Run("last attempt", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := jobsSchema.WithAttempt(context.Background(), deliveryAttempts, deliveryAttempts)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		t.Cleanup(server.Close)
+
+		webhook, delivery := newDelivery(server.URL)
+		store := newStore(ctx, webhook, delivery, schema.StatusFailed)
+
+		err := newTestService(store, true).deliver(ctx, deliverPayload{DeliveryUUID: delivery.UUID})
+		require.ErrorIs(t, err, jobsSchema.ErrPermanent)
+	})
+
+	t.Run("private address", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := jobsSchema.WithAttempt(context.Background(), 1, deliveryAttempts)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Fail(t, "request to private address is made")
+		}))
+		t.Cleanup(server.Close)
+
+		webhook, delivery := newDelivery(server.URL)
+		store := newStore(ctx, webhook, delivery, schema.StatusFailed)
+
+		err := newTestService(store, false).deliver(ctx, deliverPayload{DeliveryUUID: delivery.UUID})
+		require.ErrorIs(t, err, jobsSchema.ErrPermanent)
+	})
+
+	t.Run("removed webhook", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+
+		store := new(mockStorage)
+		store.On("GetDelivery", ctx, "removed").Return((*storage.Delivery)(nil), errbase.ErrNotFound)
+
+		require.NoError(t, newTestService(store, false).deliver(ctx, deliverPayload{DeliveryUUID: "removed"}))
+	})
+}
+
+func TestDispatchEvent(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	userUUID := th.RandomString("user-", 10)
+	data := json.RawMessage(`{"a":"b"}`)
+
+	store := new(mockStorage)
+	store.
+		On("ListSubscribedWebhooks", ctx, userUUID, notificationsSchema.TypeExportReady).
+		Return([]storage.Webhook{}, nil)
+	store.
+		On("ListGlobalWebhooks", ctx, notificationsSchema.TypeExportReady).
+		Return([]storage.Webhook{}, nil)
+
+	svc := newTestService(store, false)
+
+	require.NoError(t, svc.dispatchEvent(ctx, schema.Event{
+		Scope:    schema.ScopeUser,
+		UserUUID: userUUID,
+		Type:     notificationsSchema.TypeExportReady,
+		Data:     data,
+	}))
+	store.AssertNumberOfCalls(t, "ListSubscribedWebhooks", 1)
+	store.AssertNotCalled(t, "ListGlobalWebhooks")
+
+	require.NoError(t, svc.dispatchEvent(ctx, schema.Event{
+		Scope:    schema.ScopeGlobal,
+		UserUUID: "",
+		Type:     notificationsSchema.TypeExportReady,
+		Data:     data,
+	}))
+	store.AssertNumberOfCalls(t, "ListGlobalWebhooks", 1)
+}
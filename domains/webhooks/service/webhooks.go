@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	"github.com/outcatcher/anwil/domains/core/safehttp"
+	"github.com/outcatcher/anwil/domains/core/validation"
+	notificationsSchema "github.com/outcatcher/anwil/domains/notifications/schema"
+	"github.com/outcatcher/anwil/domains/webhooks/service/schema"
+	"github.com/outcatcher/anwil/domains/webhooks/storage"
+)
+
+const (
+	// maxWebhooks - max number of webhooks of single user in each scope
+	maxWebhooks = 10
+	// deliveriesLimit - number of returned latest deliveries
+	deliveriesLimit = 50
+
+	secretSize = 32
+)
+
+func toWebhook(webhook *storage.Webhook) *schema.Webhook {
+	return &schema.Webhook{
+		UUID:       webhook.UUID,
+		Scope:      webhook.Scope,
+		URL:        webhook.URL,
+		EventTypes: webhook.EventTypes,
+		Secret:     "",
+		CreatedAt:  webhook.CreatedAt,
+	}
+}
+
+func toDelivery(delivery *storage.Delivery) *schema.Delivery {
+	result := &schema.Delivery{
+		UUID:           delivery.UUID,
+		EventType:      delivery.EventType,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		ResponseStatus: nil,
+		Error:          delivery.Error,
+		CreatedAt:      delivery.CreatedAt,
+		FinishedAt:     nil,
+	}
+
+	if delivery.ResponseStatus.Valid {
+		status := int(delivery.ResponseStatus.Int32)
+		result.ResponseStatus = &status
+	}
+
+	if delivery.FinishedAt.Valid {
+		finishedAt := delivery.FinishedAt.Time
+		result.FinishedAt = &finishedAt
+	}
+
+	return result
+}
+
+// validateEventTypes checks that all event types are known notification types available in the scope,
+// removing duplicates.
+func validateEventTypes(scope string, eventTypes []string) ([]string, error) {
+	if len(eventTypes) == 0 {
+		return nil, fmt.Errorf("%w: at least one event type is required", validation.ErrValidationFailed)
+	}
+
+	result := make([]string, 0, len(eventTypes))
+	seen := make(map[string]bool, len(eventTypes))
+
+	for _, eventType := range eventTypes {
+		if _, ok := notificationsSchema.Types[eventType]; !ok {
+			return nil, fmt.Errorf("%w: unknown event type %s", validation.ErrValidationFailed, eventType)
+		}
+
+		if scope == schema.ScopeGlobal && !notificationsSchema.GlobalTypes[eventType] {
+			return nil, fmt.Errorf(
+				"%w: event type %s is not available for global webhooks", validation.ErrValidationFailed, eventType,
+			)
+		}
+
+		if seen[eventType] {
+			continue
+		}
+
+		seen[eventType] = true
+
+		result = append(result, eventType)
+	}
+
+	return result, nil
+}
+
+// validateScope checks that scope is one of known webhook scopes.
+func validateScope(scope string) error {
+	if scope != schema.ScopeUser && scope != schema.ScopeGlobal {
+		return fmt.Errorf("%w: unknown webhook scope %s", validation.ErrValidationFailed, scope)
+	}
+
+	return nil
+}
+
+// CreateWebhook registers new webhook. Returned webhook contains secret used for signing payloads.
+//
+// Checking that only admins create global webhooks is up to the caller.
+func (s *service) CreateWebhook(ctx context.Context, userUUID string, input schema.WebhookInput) (*schema.Webhook, error) {
+	if err := validateScope(input.Scope); err != nil {
+		return nil, fmt.Errorf("error creating webhook: %w", err)
+	}
+
+	target, err := safehttp.ParseURL(input.URL)
+	if err != nil {
+		return nil, fmt.Errorf("error creating webhook: %w", err)
+	}
+
+	eventTypes, err := validateEventTypes(input.Scope, input.EventTypes)
+	if err != nil {
+		return nil, fmt.Errorf("error creating webhook: %w", err)
+	}
+
+	existing, err := s.storage.ListWebhooks(ctx, userUUID, input.Scope)
+	if err != nil {
+		return nil, fmt.Errorf("error creating webhook: %w", err)
+	}
+
+	if len(existing) >= maxWebhooks {
+		return nil, fmt.Errorf("%w: no more than %d webhooks are allowed", errbase.ErrConflict, maxWebhooks)
+	}
+
+	secret := make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("error generating webhook secret: %w", err)
+	}
+
+	created, err := s.storage.InsertWebhook(ctx, storage.Webhook{ //nolint:exhaustruct
+		WisherUUID: userUUID,
+		Scope:      input.Scope,
+		URL:        target.String(),
+		Secret:     hex.EncodeToString(secret),
+		EventTypes: eventTypes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating webhook: %w", err)
+	}
+
+	webhook := toWebhook(created)
+	webhook.Secret = created.Secret
+
+	return webhook, nil
+}
+
+// ListWebhooks returns webhooks of the user in the scope. Secrets are not returned.
+func (s *service) ListWebhooks(ctx context.Context, userUUID, scope string) ([]schema.Webhook, error) {
+	stored, err := s.storage.ListWebhooks(ctx, userUUID, scope)
+	if err != nil {
+		return nil, fmt.Errorf("error listing webhooks: %w", err)
+	}
+
+	webhooks := make([]schema.Webhook, len(stored))
+
+	for i := range stored {
+		webhooks[i] = *toWebhook(&stored[i])
+	}
+
+	return webhooks, nil
+}
+
+// DeleteWebhook removes webhook of the user with its delivery log.
+func (s *service) DeleteWebhook(ctx context.Context, userUUID, scope, webhookUUID string) error {
+	if err := s.storage.DeleteWebhook(ctx, userUUID, scope, webhookUUID); err != nil {
+		return fmt.Errorf("error deleting webhook: %w", err)
+	}
+
+	return nil
+}
+
+// userWebhook returns webhook if it belongs to the user and the scope.
+func (s *service) userWebhook(ctx context.Context, userUUID, scope, webhookUUID string) (*storage.Webhook, error) {
+	webhook, err := s.storage.GetWebhook(ctx, webhookUUID)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	// webhooks of other users and scopes are indistinguishable from missing ones
+	if webhook.WisherUUID != userUUID || webhook.Scope != scope {
+		return nil, fmt.Errorf("webhook %s: %w", webhookUUID, errbase.ErrNotFound)
+	}
+
+	return webhook, nil
+}
+
+// ListDeliveries returns latest deliveries of the webhook, newest first.
+func (s *service) ListDeliveries(
+	ctx context.Context, userUUID, scope, webhookUUID string,
+) ([]schema.Delivery, error) {
+	if _, err := s.userWebhook(ctx, userUUID, scope, webhookUUID); err != nil {
+		return nil, fmt.Errorf("error listing webhook deliveries: %w", err)
+	}
+
+	stored, err := s.storage.ListDeliveries(ctx, webhookUUID, deliveriesLimit)
+	if err != nil {
+		return nil, fmt.Errorf("error listing webhook deliveries: %w", err)
+	}
+
+	deliveries := make([]schema.Delivery, len(stored))
+
+	for i := range stored {
+		deliveries[i] = *toDelivery(&stored[i])
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Webhook - entity of `webhooks` table.
+type Webhook struct {
+	UUID       string         `db:"uuid"`
+	WisherUUID string         `db:"wisher_uuid"`
+	Scope      string         `db:"scope"`
+	URL        string         `db:"url"`
+	Secret     string         `db:"secret"`
+	EventTypes pq.StringArray `db:"event_types"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
+
+// Delivery - entity of `webhook_deliveries` table.
+type Delivery struct {
+	UUID        string `db:"uuid"`
+	WebhookUUID string `db:"webhook_uuid"`
+	EventType   string `db:"event_type"`
+	// Payload is JSON-encoded request body
+	Payload        string        `db:"payload"`
+	Status         string        `db:"status"`
+	Attempts       int           `db:"attempts"`
+	ResponseStatus sql.NullInt32 `db:"response_status"`
+	Error          string        `db:"error"`
+	CreatedAt      time.Time     `db:"created_at"`
+	FinishedAt     sql.NullTime  `db:"finished_at"`
+}
+
+// Attempt - result of the delivery attempt.
+type Attempt struct {
+	// Status is a delivery status after the attempt
+	Status         string
+	ResponseStatus sql.NullInt32
+	Error          string
+}
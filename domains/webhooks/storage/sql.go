@@ -0,0 +1,207 @@
+/*
+Package storage contains db-related operations with outgoing webhooks.
+*/
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	storageSchema "github.com/outcatcher/anwil/domains/storage/schema"
+)
+
+// webhookStorage - storage of webhooks.
+type webhookStorage struct {
+	db storageSchema.QueryExecutor
+}
+
+// New creates a new WebhookStorage instance.
+func New(db storageSchema.QueryExecutor) WebhookStorage {
+	return &webhookStorage{db: db}
+}
+
+// InsertWebhook creates new webhook.
+func (w *webhookStorage) InsertWebhook(ctx context.Context, webhook Webhook) (*Webhook, error) {
+	inserted := new(Webhook)
+
+	err := w.db.GetContext(
+		ctx,
+		inserted,
+		`INSERT INTO webhooks (wisher_uuid, scope, url, secret, event_types) VALUES ($1, $2, $3, $4, $5) RETURNING *;`,
+		webhook.WisherUUID, webhook.Scope, webhook.URL, webhook.Secret, webhook.EventTypes,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting webhook: %w", err)
+	}
+
+	return inserted, nil
+}
+
+// GetWebhook returns single webhook by UUID.
+func (w *webhookStorage) GetWebhook(ctx context.Context, uuid string) (*Webhook, error) {
+	webhook := new(Webhook)
+
+	err := w.db.GetContext(ctx, webhook, `SELECT * FROM webhooks WHERE uuid = $1;`, uuid)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("no webhook found: %w", errbase.ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error selecting webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+// ListWebhooks returns all webhooks of the user in the scope, oldest first.
+func (w *webhookStorage) ListWebhooks(ctx context.Context, wisherUUID, scope string) ([]Webhook, error) {
+	var webhooks []Webhook
+
+	err := sqlx.SelectContext(
+		ctx, w.db, &webhooks,
+		`SELECT * FROM webhooks WHERE wisher_uuid = $1 AND scope = $2 ORDER BY created_at;`,
+		wisherUUID, scope,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+// ListSubscribedWebhooks returns user-scoped webhooks of the user subscribed to the event type.
+func (w *webhookStorage) ListSubscribedWebhooks(ctx context.Context, wisherUUID, eventType string) ([]Webhook, error) {
+	var webhooks []Webhook
+
+	err := sqlx.SelectContext(
+		ctx, w.db, &webhooks,
+		`SELECT * FROM webhooks WHERE wisher_uuid = $1 AND scope = 'user' AND $2 = ANY (event_types);`,
+		wisherUUID, eventType,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting subscribed webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+// ListGlobalWebhooks returns global webhooks subscribed to the event type.
+//
+// Webhooks of users who are not admins anymore are skipped.
+func (w *webhookStorage) ListGlobalWebhooks(ctx context.Context, eventType string) ([]Webhook, error) {
+	var webhooks []Webhook
+
+	err := sqlx.SelectContext(
+		ctx, w.db, &webhooks,
+		`SELECT webhooks.*
+		 FROM webhooks
+		          JOIN wishers ON wishers.uuid = webhooks.wisher_uuid
+		 WHERE webhooks.scope = 'global'
+		   AND wishers.role = 'admin'
+		   AND $1 = ANY (webhooks.event_types);`,
+		eventType,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting global webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+// DeleteWebhook removes webhook of the user in the scope.
+func (w *webhookStorage) DeleteWebhook(ctx context.Context, wisherUUID, scope, uuid string) error {
+	result, err := w.db.ExecContext(
+		ctx, `DELETE FROM webhooks WHERE uuid = $1 AND wisher_uuid = $2 AND scope = $3;`, uuid, wisherUUID, scope,
+	)
+	if err != nil {
+		return fmt.Errorf("error deleting webhook: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error deleting webhook: %w", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("webhook %s: %w", uuid, errbase.ErrNotFound)
+	}
+
+	return nil
+}
+
+// InsertDelivery creates new pending delivery with given UUID.
+func (w *webhookStorage) InsertDelivery(ctx context.Context, delivery Delivery) (*Delivery, error) {
+	inserted := new(Delivery)
+
+	err := w.db.GetContext(
+		ctx,
+		inserted,
+		`INSERT INTO webhook_deliveries (uuid, webhook_uuid, event_type, payload)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING *;`,
+		delivery.UUID, delivery.WebhookUUID, delivery.EventType, delivery.Payload,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error inserting webhook delivery: %w", err)
+	}
+
+	return inserted, nil
+}
+
+// GetDelivery returns single delivery by UUID.
+func (w *webhookStorage) GetDelivery(ctx context.Context, uuid string) (*Delivery, error) {
+	delivery := new(Delivery)
+
+	err := w.db.GetContext(ctx, delivery, `SELECT * FROM webhook_deliveries WHERE uuid = $1;`, uuid)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("no webhook delivery found: %w", errbase.ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error selecting webhook delivery: %w", err)
+	}
+
+	return delivery, nil
+}
+
+// ListDeliveries returns latest deliveries of the webhook, newest first.
+func (w *webhookStorage) ListDeliveries(ctx context.Context, webhookUUID string, limit int) ([]Delivery, error) {
+	var deliveries []Delivery
+
+	err := sqlx.SelectContext(
+		ctx, w.db, &deliveries,
+		`SELECT * FROM webhook_deliveries WHERE webhook_uuid = $1 ORDER BY created_at DESC LIMIT $2;`,
+		webhookUUID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error selecting webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// RecordAttempt increments number of delivery attempts, saving the attempt result.
+//
+// Delivery is marked finished if the status is not pending anymore.
+func (w *webhookStorage) RecordAttempt(ctx context.Context, uuid string, attempt Attempt) error {
+	_, err := w.db.ExecContext(
+		ctx,
+		`UPDATE webhook_deliveries
+		 SET attempts        = attempts + 1,
+		     status          = $2,
+		     response_status = $3,
+		     error           = $4,
+		     finished_at     = CASE WHEN $2 = 'pending' THEN NULL ELSE now() END
+		 WHERE uuid = $1;`,
+		uuid, attempt.Status, attempt.ResponseStatus, attempt.Error,
+	)
+	if err != nil {
+		return fmt.Errorf("error recording webhook delivery attempt: %w", err)
+	}
+
+	return nil
+}
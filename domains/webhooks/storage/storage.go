@@ -0,0 +1,22 @@
+package storage
+
+import "context"
+
+// WebhookStorage - storage of webhooks and their deliveries.
+type WebhookStorage interface {
+	InsertWebhook(ctx context.Context, webhook Webhook) (*Webhook, error)
+	GetWebhook(ctx context.Context, uuid string) (*Webhook, error)
+	ListWebhooks(ctx context.Context, wisherUUID, scope string) ([]Webhook, error)
+	// ListSubscribedWebhooks returns user-scoped webhooks of the user subscribed to the event type.
+	ListSubscribedWebhooks(ctx context.Context, wisherUUID, eventType string) ([]Webhook, error)
+	// ListGlobalWebhooks returns global webhooks subscribed to the event type. Webhooks of non-admins are skipped.
+	ListGlobalWebhooks(ctx context.Context, eventType string) ([]Webhook, error)
+	DeleteWebhook(ctx context.Context, wisherUUID, scope, uuid string) error
+
+	InsertDelivery(ctx context.Context, delivery Delivery) (*Delivery, error)
+	GetDelivery(ctx context.Context, uuid string) (*Delivery, error)
+	// ListDeliveries returns latest deliveries of the webhook, newest first.
+	ListDeliveries(ctx context.Context, webhookUUID string, limit int) ([]Delivery, error)
+	// RecordAttempt increments number of delivery attempts, saving the attempt result.
+	RecordAttempt(ctx context.Context, uuid string, attempt Attempt) error
+}
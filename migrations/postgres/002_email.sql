@@ -0,0 +1,26 @@
+-- +goose Up
+
+ALTER TABLE wishers
+    ADD COLUMN "email"          VARCHAR UNIQUE,
+    ADD COLUMN "email_verified" BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TYPE "token_purpose" AS ENUM ('email_verification', 'password_reset');
+
+CREATE TABLE wisher_tokens
+(
+    "uuid"        UUID PRIMARY KEY,
+    "wisher_uuid" UUID          NOT NULL REFERENCES wishers ("uuid") ON DELETE CASCADE,
+    "purpose"     token_purpose NOT NULL,
+    "expires_at"  TIMESTAMPTZ   NOT NULL,
+    "used_at"     TIMESTAMPTZ
+);
+
+-- +goose Down
+
+DROP TABLE wisher_tokens;
+
+DROP TYPE "token_purpose";
+
+ALTER TABLE wishers
+    DROP COLUMN "email",
+    DROP COLUMN "email_verified";
@@ -0,0 +1,9 @@
+-- +goose Up
+
+ALTER TABLE wishers
+    ADD COLUMN "avatar" VARCHAR NOT NULL DEFAULT '';
+
+-- +goose Down
+
+ALTER TABLE wishers
+    DROP COLUMN "avatar";
@@ -0,0 +1,22 @@
+-- +goose Up
+
+CREATE TYPE "export_status" AS ENUM ('pending', 'ready', 'failed');
+
+CREATE TABLE user_exports
+(
+    "uuid"        UUID PRIMARY KEY       DEFAULT gen_random_uuid(),
+    "wisher_uuid" UUID          NOT NULL REFERENCES wishers ("uuid") ON DELETE CASCADE,
+    "status"      export_status NOT NULL DEFAULT 'pending',
+    "created_at"  TIMESTAMPTZ   NOT NULL DEFAULT now(),
+    "finished_at" TIMESTAMPTZ,
+    "expires_at"  TIMESTAMPTZ,
+    "error"       VARCHAR       NOT NULL DEFAULT ''
+);
+
+CREATE INDEX user_exports_wisher_uuid_idx ON user_exports ("wisher_uuid");
+
+-- +goose Down
+
+DROP TABLE user_exports;
+
+DROP TYPE "export_status";
@@ -0,0 +1,27 @@
+-- +goose Up
+
+CREATE TYPE "job_status" AS ENUM ('pending', 'running', 'done', 'failed');
+
+CREATE TABLE jobs
+(
+    "uuid"         UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    "kind"         VARCHAR     NOT NULL,
+    "payload"      JSONB       NOT NULL DEFAULT 'null',
+    "status"       job_status  NOT NULL DEFAULT 'pending',
+    "attempts"     INTEGER     NOT NULL DEFAULT 0,
+    "max_attempts" INTEGER     NOT NULL,
+    "run_at"       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    "locked_at"    TIMESTAMPTZ,
+    "last_error"   VARCHAR     NOT NULL DEFAULT '',
+    "unique_key"   VARCHAR UNIQUE,
+    "created_at"   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    "finished_at"  TIMESTAMPTZ
+);
+
+CREATE INDEX jobs_pending_idx ON jobs ("run_at") WHERE status = 'pending';
+
+-- +goose Down
+
+DROP TABLE jobs;
+
+DROP TYPE "job_status";
@@ -0,0 +1,10 @@
+-- +goose Up
+
+-- key of uploaded avatar in blob storage, `avatar` holds external URL otherwise
+ALTER TABLE wishers
+    ADD COLUMN "avatar_key" VARCHAR NOT NULL DEFAULT '';
+
+-- +goose Down
+
+ALTER TABLE wishers
+    DROP COLUMN "avatar_key";
@@ -0,0 +1,20 @@
+-- +goose Up
+
+-- rates relative to the base currency of the rates provider
+CREATE TABLE exchange_rates
+(
+    "currency"   VARCHAR(3) PRIMARY KEY,
+    "rate"       NUMERIC     NOT NULL CHECK ( "rate" > 0 ),
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- preferred currency of the user, configured default currency is used if empty
+ALTER TABLE wishers
+    ADD COLUMN "currency" VARCHAR(3) NOT NULL DEFAULT '';
+
+-- +goose Down
+
+ALTER TABLE wishers
+    DROP COLUMN "currency";
+
+DROP TABLE exchange_rates;
@@ -0,0 +1,31 @@
+-- +goose Up
+
+CREATE TABLE notifications
+(
+    "uuid"        UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    "wisher_uuid" UUID        NOT NULL REFERENCES wishers ("uuid") ON DELETE CASCADE,
+    "type"        VARCHAR     NOT NULL,
+    "title"       VARCHAR     NOT NULL,
+    "body"        VARCHAR     NOT NULL DEFAULT '',
+    "link"        VARCHAR     NOT NULL DEFAULT '',
+    "data"        JSONB       NOT NULL DEFAULT '{}',
+    "created_at"  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    "read_at"     TIMESTAMPTZ
+);
+
+CREATE INDEX notifications_wisher_uuid_created_at_idx ON notifications ("wisher_uuid", "created_at" DESC);
+
+-- notification types without row use default channels
+CREATE TABLE notification_preferences
+(
+    "wisher_uuid" UUID      NOT NULL REFERENCES wishers ("uuid") ON DELETE CASCADE,
+    "type"        VARCHAR   NOT NULL,
+    "channels"    VARCHAR[] NOT NULL,
+    PRIMARY KEY ("wisher_uuid", "type")
+);
+
+-- +goose Down
+
+DROP TABLE notification_preferences;
+
+DROP TABLE notifications;
@@ -0,0 +1,39 @@
+-- +goose Up
+
+CREATE TABLE webhooks
+(
+    "uuid"        UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    "wisher_uuid" UUID        NOT NULL REFERENCES wishers ("uuid") ON DELETE CASCADE,
+    "url"         VARCHAR     NOT NULL,
+    "secret"      VARCHAR     NOT NULL,
+    "event_types" VARCHAR[]   NOT NULL,
+    "created_at"  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX webhooks_wisher_uuid_idx ON webhooks ("wisher_uuid");
+
+CREATE TYPE webhook_delivery_status AS ENUM ('pending', 'delivered', 'failed');
+
+CREATE TABLE webhook_deliveries
+(
+    "uuid"            UUID PRIMARY KEY                 DEFAULT gen_random_uuid(),
+    "webhook_uuid"    UUID                    NOT NULL REFERENCES webhooks ("uuid") ON DELETE CASCADE,
+    "event_type"      VARCHAR                 NOT NULL,
+    "payload"         JSONB                   NOT NULL,
+    "status"          webhook_delivery_status NOT NULL DEFAULT 'pending',
+    "attempts"        INT                     NOT NULL DEFAULT 0,
+    "response_status" INT,
+    "error"           VARCHAR                 NOT NULL DEFAULT '',
+    "created_at"      TIMESTAMPTZ             NOT NULL DEFAULT now(),
+    "finished_at"     TIMESTAMPTZ
+);
+
+CREATE INDEX webhook_deliveries_webhook_uuid_created_at_idx ON webhook_deliveries ("webhook_uuid", "created_at" DESC);
+
+-- +goose Down
+
+DROP TABLE webhook_deliveries;
+
+DROP TYPE webhook_delivery_status;
+
+DROP TABLE webhooks;
@@ -0,0 +1,42 @@
+-- +goose Up
+
+CREATE TABLE audit_log
+(
+    "uuid"        UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    "action"      VARCHAR     NOT NULL,
+    -- actor and target are not foreign keys, so entries outlive deleted users
+    "actor_uuid"  UUID,
+    "target_uuid" UUID,
+    "ip"          VARCHAR     NOT NULL DEFAULT '',
+    "user_agent"  VARCHAR     NOT NULL DEFAULT '',
+    "request_id"  VARCHAR     NOT NULL DEFAULT '',
+    "before"      JSONB       NOT NULL DEFAULT '{}',
+    "after"       JSONB       NOT NULL DEFAULT '{}',
+    "created_at"  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX audit_log_created_at_idx ON audit_log ("created_at" DESC);
+CREATE INDEX audit_log_actor_uuid_created_at_idx ON audit_log ("actor_uuid", "created_at" DESC);
+CREATE INDEX audit_log_target_uuid_created_at_idx ON audit_log ("target_uuid", "created_at" DESC);
+
+-- audit log is append-only: entries are never changed, only pruned by retention
+-- +goose StatementBegin
+CREATE FUNCTION audit_log_deny_update() RETURNS trigger AS
+$$
+BEGIN
+    RAISE EXCEPTION 'audit log entries can not be changed';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE
+    ON audit_log
+    FOR EACH ROW
+EXECUTE FUNCTION audit_log_deny_update();
+
+-- +goose Down
+
+DROP TABLE audit_log;
+
+DROP FUNCTION audit_log_deny_update();
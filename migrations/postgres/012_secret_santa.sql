@@ -0,0 +1,54 @@
+-- +goose Up
+
+CREATE TABLE santa_groups
+(
+    "uuid"       UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    "owner_uuid" UUID        NOT NULL REFERENCES wishers ("uuid") ON DELETE CASCADE,
+    "name"       VARCHAR     NOT NULL,
+    "drawn_at"   TIMESTAMPTZ,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TYPE santa_member_status AS ENUM ('invited', 'joined');
+
+CREATE TABLE santa_members
+(
+    "group_uuid"  UUID                NOT NULL REFERENCES santa_groups ("uuid") ON DELETE CASCADE,
+    "wisher_uuid" UUID                NOT NULL REFERENCES wishers ("uuid") ON DELETE CASCADE,
+    "status"      santa_member_status NOT NULL DEFAULT 'invited',
+    "created_at"  TIMESTAMPTZ         NOT NULL DEFAULT now(),
+    PRIMARY KEY ("group_uuid", "wisher_uuid")
+);
+
+CREATE INDEX santa_members_wisher_uuid_idx ON santa_members ("wisher_uuid");
+
+-- exclusions are directed: giver can't be assigned to the receiver
+CREATE TABLE santa_exclusions
+(
+    "group_uuid"    UUID NOT NULL REFERENCES santa_groups ("uuid") ON DELETE CASCADE,
+    "giver_uuid"    UUID NOT NULL REFERENCES wishers ("uuid") ON DELETE CASCADE,
+    "receiver_uuid" UUID NOT NULL REFERENCES wishers ("uuid") ON DELETE CASCADE,
+    PRIMARY KEY ("group_uuid", "giver_uuid", "receiver_uuid"),
+    CHECK ("giver_uuid" <> "receiver_uuid")
+);
+
+CREATE TABLE santa_assignments
+(
+    "group_uuid"    UUID NOT NULL REFERENCES santa_groups ("uuid") ON DELETE CASCADE,
+    "giver_uuid"    UUID NOT NULL REFERENCES wishers ("uuid") ON DELETE CASCADE,
+    "receiver_uuid" UUID NOT NULL REFERENCES wishers ("uuid") ON DELETE CASCADE,
+    PRIMARY KEY ("group_uuid", "giver_uuid"),
+    UNIQUE ("group_uuid", "receiver_uuid")
+);
+
+-- +goose Down
+
+DROP TABLE santa_assignments;
+
+DROP TABLE santa_exclusions;
+
+DROP TABLE santa_members;
+
+DROP TYPE santa_member_status;
+
+DROP TABLE santa_groups;
@@ -0,0 +1,13 @@
+-- +goose Up
+
+CREATE TYPE webhook_scope AS ENUM ('user', 'global');
+
+ALTER TABLE webhooks
+    ADD COLUMN "scope" webhook_scope NOT NULL DEFAULT 'user';
+
+-- +goose Down
+
+ALTER TABLE webhooks
+    DROP COLUMN "scope";
+
+DROP TYPE webhook_scope;
@@ -0,0 +1,21 @@
+-- +goose Up
+
+-- events published in transactions are delivered by background jobs
+DROP TABLE event_outbox;
+
+-- +goose Down
+
+CREATE TABLE event_outbox
+(
+    "uuid"         UUID PRIMARY KEY     DEFAULT gen_random_uuid(),
+    "event_type"   VARCHAR     NOT NULL,
+    "payload"      JSONB       NOT NULL,
+    "attempts"     INTEGER     NOT NULL DEFAULT 0,
+    "retry_at"     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    "locked_at"    TIMESTAMPTZ,
+    "last_error"   VARCHAR     NOT NULL DEFAULT '',
+    "created_at"   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    "published_at" TIMESTAMPTZ
+);
+
+CREATE INDEX event_outbox_pending_idx ON event_outbox ("retry_at") WHERE published_at IS NULL;
@@ -0,0 +1,94 @@
+//go:build integration
+
+package testing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/stretchr/testify/require"
+)
+
+func (s *AnwilSuite) TestAuditLog() {
+	t := s.T()
+
+	t.Parallel()
+
+	userData, token := s.newUser(t)
+
+	resp := s.request(http.MethodGet, parseRequestURL(t, "/api/v1/me"), nil, addAuthHeader(token, nil))
+	require.EqualValues(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var profile struct {
+		UUID string `json:"uuid"`
+		Role string `json:"role"`
+	}
+
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &profile))
+	require.Equal(t, "wisher", profile.Role)
+
+	resp = s.requestJSON(
+		http.MethodPost,
+		parseRequestURL(t, "/api/v1/login"),
+		mapBody{"username": userData["username"], "password": "definitely-wrong"},
+		map[string]string{"User-Agent": "audit-test"},
+	)
+	require.EqualValues(t, http.StatusUnauthorized, resp.Code, resp.Body.String())
+
+	auditURL := parseRequestURL(t, "/api/v1/admin/audit?target="+profile.UUID)
+
+	resp = s.request(http.MethodGet, auditURL, nil, addAuthHeader(token, nil))
+	require.EqualValues(t, http.StatusForbidden, resp.Code, resp.Body.String())
+
+	_, err := s.db.ExecContext(context.Background(), `UPDATE wishers SET role = 'admin' WHERE uuid = $1;`, profile.UUID)
+	require.NoError(t, err)
+
+	resp = s.request(http.MethodGet, auditURL, nil, addAuthHeader(token, nil))
+	require.EqualValues(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var entries auditPage
+
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &entries))
+	require.Len(t, entries.Items, 3)
+	require.Empty(t, entries.Next)
+
+	// newest first
+	require.Equal(t, "user.login_failed", entries.Items[0]["action"])
+	require.Equal(t, "audit-test", entries.Items[0]["user_agent"])
+	require.NotEmpty(t, entries.Items[0]["ip"])
+	require.NotEmpty(t, entries.Items[0]["request_id"])
+	require.Equal(t, "user.login", entries.Items[1]["action"])
+	require.Equal(t, "user.register", entries.Items[2]["action"])
+
+	resp = s.request(http.MethodGet, parseRequestURL(t, auditURL.String()+"&limit=2"), nil, addAuthHeader(token, nil))
+	require.EqualValues(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var firstPage auditPage
+
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &firstPage))
+	require.Equal(t, entries.Items[:2], firstPage.Items)
+	require.Empty(t, firstPage.Prev)
+	require.NotEmpty(t, firstPage.Next)
+
+	resp = s.request(http.MethodGet, parseRequestURL(t, firstPage.Next), nil, addAuthHeader(token, nil))
+	require.EqualValues(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var lastPage auditPage
+
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &lastPage))
+	require.Equal(t, entries.Items[2:], lastPage.Items)
+	require.Empty(t, lastPage.Next)
+	require.NotEmpty(t, lastPage.Prev)
+
+	resp = s.request(
+		http.MethodGet, parseRequestURL(t, "/api/v1/admin/audit?actor=not-uuid"), nil, addAuthHeader(token, nil),
+	)
+	require.EqualValues(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+}
+
+type auditPage struct {
+	Items []mapBody `json:"items"`
+	Next  string    `json:"next"`
+	Prev  string    `json:"prev"`
+}
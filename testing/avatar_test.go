@@ -0,0 +1,83 @@
+//go:build integration
+
+package testing
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// multipartFile returns multipart form with single file field and its content type.
+func multipartFile(t *testing.T, field string, data []byte) (*bytes.Buffer, string) {
+	t.Helper()
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+
+	part, err := writer.CreateFormFile(field, "upload")
+	require.NoError(t, err)
+
+	_, err = part.Write(data)
+	require.NoError(t, err)
+
+	require.NoError(t, writer.Close())
+
+	return body, writer.FormDataContentType()
+}
+
+func (s *AnwilSuite) uploadAvatar(t *testing.T, token string, data []byte) *http.Response {
+	t.Helper()
+
+	body, contentType := multipartFile(t, "file", data)
+
+	return s.request(
+		http.MethodPut,
+		parseRequestURL(t, "/api/v1/me/avatar"),
+		body,
+		addAuthHeader(token, map[string]string{echo.HeaderContentType: contentType}),
+	).Result()
+}
+
+func (s *AnwilSuite) TestMeAvatar() {
+	t := s.T()
+
+	t.Parallel()
+
+	_, token := s.newUser(t)
+
+	imageData := new(bytes.Buffer)
+	require.NoError(t, png.Encode(imageData, image.NewRGBA(image.Rect(0, 0, 600, 300))))
+
+	resp := s.uploadAvatar(t, token, imageData.Bytes())
+	require.EqualValues(t, http.StatusOK, resp.StatusCode)
+
+	var me mapBody
+
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&me))
+	require.NoError(t, resp.Body.Close())
+
+	for _, field := range []string{"avatar", "avatar_thumbnail"} {
+		imageURL, err := url.Parse(me[field].(string)) //nolint:forcetypeassert
+		require.NoError(t, err)
+
+		blobResp := s.request(http.MethodGet, imageURL, nil, nil)
+		require.EqualValues(t, http.StatusOK, blobResp.Code, blobResp.Body.String())
+		require.Equal(t, "image/png", blobResp.Header().Get(echo.HeaderContentType))
+
+		_, err = png.Decode(blobResp.Body)
+		require.NoError(t, err)
+	}
+
+	resp = s.uploadAvatar(t, token, []byte("<html>not an image</html>"))
+	require.EqualValues(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+}
@@ -0,0 +1,81 @@
+//go:build integration
+
+package testing
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const ratesWaitTimeout = 10 * time.Second
+
+// waitForRates waits for exchange rates to be loaded by startup job.
+func (s *AnwilSuite) waitForRates(t *testing.T) []mapBody {
+	t.Helper()
+
+	var currencies []mapBody
+
+	require.Eventually(t, func() bool {
+		resp := s.request(http.MethodGet, parseRequestURL(t, "/api/v1/currencies"), nil, nil)
+		require.EqualValues(t, http.StatusOK, resp.Code, resp.Body.String())
+
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &currencies))
+
+		return len(currencies) > 0
+	}, ratesWaitTimeout, exportWaitPeriod)
+
+	return currencies
+}
+
+func (s *AnwilSuite) TestCurrencies() {
+	t := s.T()
+
+	t.Parallel()
+
+	currencies := s.waitForRates(t)
+
+	codes := make([]any, len(currencies))
+	for i, currency := range currencies {
+		codes[i] = currency["code"]
+	}
+
+	require.ElementsMatch(t, []any{"EUR", "JPY", "RUB", "USD"}, codes)
+}
+
+func (s *AnwilSuite) TestCurrencyConvert() {
+	t := s.T()
+
+	t.Parallel()
+
+	s.waitForRates(t)
+
+	_, token := s.newUser(t)
+
+	// default currency is used if not set
+	resp := s.request(
+		http.MethodGet, parseRequestURL(t, "/api/v1/currencies/convert?amount=108&from=USD"), nil, addAuthHeader(token, nil),
+	)
+	require.EqualValues(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.JSONEq(t, `{"amount": 100, "currency": "EUR"}`, resp.Body.String())
+
+	resp = s.requestJSON(http.MethodPatch, parseRequestURL(t, "/api/v1/me"), mapBody{"currency": "JPY"}, addAuthHeader(token, nil))
+	require.EqualValues(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	resp = s.requestJSON(
+		http.MethodPost,
+		parseRequestURL(t, "/api/v1/currencies/total"),
+		mapBody{"amounts": []mapBody{{"amount": 100, "currency": "EUR"}, {"amount": 108, "currency": "USD"}}},
+		addAuthHeader(token, nil),
+	)
+	require.EqualValues(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.JSONEq(t, `{"amount": 320, "currency": "JPY"}`, resp.Body.String())
+
+	resp = s.request(
+		http.MethodGet, parseRequestURL(t, "/api/v1/currencies/convert?amount=1&from=GBP"), nil, addAuthHeader(token, nil),
+	)
+	require.EqualValues(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+}
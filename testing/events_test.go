@@ -0,0 +1,72 @@
+//go:build integration
+
+package testing
+
+import (
+	"context"
+	"time"
+
+	th "github.com/outcatcher/anwil/domains/core/testhelpers"
+	"github.com/outcatcher/anwil/domains/events"
+	"github.com/outcatcher/anwil/domains/events/schema"
+	"github.com/stretchr/testify/require"
+)
+
+const eventWaitTimeout = 5 * time.Second
+
+type outboxTestEvent struct {
+	Value string `json:"value"`
+}
+
+func (outboxTestEvent) EventType() string {
+	return "testing.outbox"
+}
+
+func (s *AnwilSuite) TestEventOutbox() {
+	t := s.T()
+
+	t.Parallel()
+
+	ctx := context.Background()
+
+	received := make(chan string, 2)
+
+	events.Subscribe(s.events, schema.Sync, func(_ context.Context, event outboxTestEvent) error {
+		received <- event.Value
+
+		return nil
+	})
+
+	rolledBack := th.RandomString("rolled-back-", 10)
+	committed := th.RandomString("committed-", 10)
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	require.NoError(t, err)
+	require.NoError(t, s.events.PublishTx(ctx, tx, outboxTestEvent{Value: rolledBack}))
+	require.NoError(t, tx.Rollback())
+
+	tx, err = s.db.BeginTxx(ctx, nil)
+	require.NoError(t, err)
+	require.NoError(t, s.events.PublishTx(ctx, tx, outboxTestEvent{Value: committed}))
+
+	select {
+	case value := <-received:
+		require.FailNow(t, "event is delivered before commit", value)
+	case <-time.After(500 * time.Millisecond): // a few job polls
+	}
+
+	require.NoError(t, tx.Commit())
+
+	select {
+	case value := <-received:
+		require.Equal(t, committed, value)
+	case <-time.After(eventWaitTimeout):
+		require.FailNow(t, "committed event is not delivered")
+	}
+
+	select {
+	case value := <-received:
+		require.FailNow(t, "event is delivered twice or after rollback", value)
+	case <-time.After(500 * time.Millisecond):
+	}
+}
@@ -0,0 +1,97 @@
+//go:build integration
+
+package testing
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	exportWaitTimeout = 10 * time.Second
+	exportWaitPeriod  = 100 * time.Millisecond
+)
+
+// waitForExport polls export until it's not pending any more.
+func (s *AnwilSuite) waitForExport(t *testing.T, token, exportUUID string) mapBody {
+	t.Helper()
+
+	var export mapBody
+
+	require.Eventually(t, func() bool {
+		resp := s.request(
+			http.MethodGet, parseRequestURL(t, "/api/v1/me/exports/"+exportUUID), nil, addAuthHeader(token, nil),
+		)
+		require.EqualValues(t, http.StatusOK, resp.Code, resp.Body.String())
+
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &export))
+
+		return export["status"] != "pending"
+	}, exportWaitTimeout, exportWaitPeriod)
+
+	return export
+}
+
+func (s *AnwilSuite) TestExport() {
+	t := s.T()
+
+	t.Parallel()
+
+	userData, token := s.newUser(t)
+
+	resp := s.requestJSON(http.MethodPost, parseRequestURL(t, "/api/v1/me/exports"), nil, addAuthHeader(token, nil))
+	require.EqualValues(t, http.StatusAccepted, resp.Code, resp.Body.String())
+
+	var requested mapBody
+
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &requested))
+
+	exportUUID, ok := requested["uuid"].(string)
+	require.True(t, ok)
+
+	export := s.waitForExport(t, token, exportUUID)
+	require.Equal(t, "ready", export["status"])
+
+	downloadURL, err := url.Parse(export["download_url"].(string)) //nolint:forcetypeassert
+	require.NoError(t, err)
+
+	// other users can't see the export
+	_, otherToken := s.newUser(t)
+
+	resp = s.request(
+		http.MethodGet, parseRequestURL(t, "/api/v1/me/exports/"+exportUUID), nil, addAuthHeader(otherToken, nil),
+	)
+	require.EqualValues(t, http.StatusNotFound, resp.Code, resp.Body.String())
+
+	// download doesn't require authorization
+	resp = s.request(http.MethodGet, downloadURL, nil, nil)
+	require.EqualValues(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	archive, err := zip.NewReader(bytes.NewReader(resp.Body.Bytes()), int64(resp.Body.Len()))
+	require.NoError(t, err)
+
+	usersFile, err := archive.Open("users.json")
+	require.NoError(t, err)
+
+	var exportedUser mapBody
+
+	require.NoError(t, json.NewDecoder(usersFile).Decode(&exportedUser))
+	require.NoError(t, usersFile.Close())
+
+	require.Equal(t, userData["username"], exportedUser["username"])
+
+	// tampered links are rejected
+	query := downloadURL.Query()
+	query.Set("expires", "99999999999")
+	downloadURL.RawQuery = query.Encode()
+
+	resp = s.request(http.MethodGet, downloadURL, nil, nil)
+	require.EqualValues(t, http.StatusForbidden, resp.Code, resp.Body.String())
+}
@@ -0,0 +1,140 @@
+//go:build integration
+
+package testing
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	th "github.com/outcatcher/anwil/domains/core/testhelpers"
+	"github.com/stretchr/testify/require"
+)
+
+// newUser registers new random user returning user data and token.
+func (s *AnwilSuite) newUser(t *testing.T) (mapBody, string) {
+	t.Helper()
+
+	userData := randomUserData()
+
+	resp := s.requestJSON(http.MethodPost, parseRequestURL(t, "/api/v1/wisher"), userData, nil)
+	require.EqualValues(t, http.StatusCreated, resp.Code, resp.Body.String())
+
+	return userData, s.loginAs(t, userData["username"], userData["password"])
+}
+
+func (s *AnwilSuite) loginAs(t *testing.T, username, password any) string {
+	t.Helper()
+
+	resp := s.requestJSON(
+		http.MethodPost,
+		parseRequestURL(t, "/api/v1/login"),
+		mapBody{"username": username, "password": password},
+		nil,
+	)
+	require.EqualValues(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var loginResponse struct {
+		Token string `json:"token"`
+	}
+
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &loginResponse))
+
+	return loginResponse.Token
+}
+
+func (s *AnwilSuite) TestMe() {
+	t := s.T()
+
+	t.Parallel()
+
+	userData, token := s.newUser(t)
+
+	resp := s.request(http.MethodGet, parseRequestURL(t, "/api/v1/me"), nil, addAuthHeader(token, nil))
+	require.EqualValues(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var me mapBody
+
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &me))
+	require.Equal(t, userData["username"], me["username"])
+	require.Equal(t, userData["full_name"], me["full_name"])
+	require.NotEmpty(t, me["uuid"])
+	require.NotContains(t, me, "password")
+
+	newName := th.RandomString("Name ", 5)
+
+	resp = s.requestJSON(
+		http.MethodPatch,
+		parseRequestURL(t, "/api/v1/me"),
+		mapBody{"full_name": newName, "avatar": "https://example.com/avatar.png"},
+		addAuthHeader(token, nil),
+	)
+	require.EqualValues(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &me))
+	require.Equal(t, newName, me["full_name"])
+	require.Equal(t, "https://example.com/avatar.png", me["avatar"])
+}
+
+func (s *AnwilSuite) TestMe_401() {
+	t := s.T()
+
+	t.Parallel()
+
+	resp := s.request(http.MethodGet, parseRequestURL(t, "/api/v1/me"), nil, nil)
+	require.EqualValues(t, http.StatusUnauthorized, resp.Code, resp.Body.String())
+}
+
+func (s *AnwilSuite) TestMeChangePassword() {
+	t := s.T()
+
+	t.Parallel()
+
+	userData, token := s.newUser(t)
+	newPassword := th.RandomString("new-pwd-", 20)
+
+	resp := s.requestJSON(
+		http.MethodPost,
+		parseRequestURL(t, "/api/v1/me/password"),
+		mapBody{"current_password": "invalid", "new_password": newPassword},
+		addAuthHeader(token, nil),
+	)
+	require.EqualValues(t, http.StatusForbidden, resp.Code, resp.Body.String())
+
+	resp = s.requestJSON(
+		http.MethodPost,
+		parseRequestURL(t, "/api/v1/me/password"),
+		mapBody{"current_password": userData["password"], "new_password": "qwerty"},
+		addAuthHeader(token, nil),
+	)
+	require.EqualValues(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+
+	resp = s.requestJSON(
+		http.MethodPost,
+		parseRequestURL(t, "/api/v1/me/password"),
+		mapBody{"current_password": userData["password"], "new_password": newPassword},
+		addAuthHeader(token, nil),
+	)
+	require.EqualValues(t, http.StatusNoContent, resp.Code, resp.Body.String())
+
+	s.loginAs(t, userData["username"], newPassword)
+}
+
+func (s *AnwilSuite) TestMeDelete() {
+	t := s.T()
+
+	t.Parallel()
+
+	userData, token := s.newUser(t)
+
+	resp := s.request(http.MethodDelete, parseRequestURL(t, "/api/v1/me"), nil, addAuthHeader(token, nil))
+	require.EqualValues(t, http.StatusNoContent, resp.Code, resp.Body.String())
+
+	resp = s.requestJSON(
+		http.MethodPost,
+		parseRequestURL(t, "/api/v1/login"),
+		mapBody{"username": userData["username"], "password": userData["password"]},
+		nil,
+	)
+	require.EqualValues(t, http.StatusNotFound, resp.Code, resp.Body.String())
+}
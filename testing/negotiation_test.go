@@ -0,0 +1,88 @@
+//go:build integration
+
+package testing
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func (s *AnwilSuite) TestLoginForm() {
+	t := s.T()
+	t.Parallel()
+
+	form := url.Values{"username": {debugUsername}, "password": {debugPassword}}
+
+	resp := s.request(
+		http.MethodPost,
+		parseRequestURL(t, "/api/v1/login"),
+		strings.NewReader(form.Encode()),
+		map[string]string{echo.HeaderContentType: echo.MIMEApplicationForm},
+	)
+	require.EqualValues(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var loginResponse mapBody
+
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &loginResponse))
+	require.NotEmpty(t, loginResponse["token"])
+}
+
+func (s *AnwilSuite) TestLoginMsgpack() {
+	t := s.T()
+	t.Parallel()
+
+	body, err := msgpack.Marshal(map[string]string{"username": debugUsername, "password": debugPassword})
+	require.NoError(t, err)
+
+	resp := s.request(
+		http.MethodPost,
+		parseRequestURL(t, "/api/v1/login"),
+		bytes.NewReader(body),
+		map[string]string{
+			echo.HeaderContentType: echo.MIMEApplicationMsgpack,
+			echo.HeaderAccept:      echo.MIMEApplicationMsgpack,
+		},
+	)
+	require.EqualValues(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.Equal(t, echo.MIMEApplicationMsgpack, resp.Header().Get(echo.HeaderContentType))
+
+	var loginResponse map[string]any
+
+	require.NoError(t, msgpack.Unmarshal(resp.Body.Bytes(), &loginResponse))
+	require.NotEmpty(t, loginResponse["token"])
+}
+
+func (s *AnwilSuite) TestLoginUnsupportedContentType() {
+	t := s.T()
+	t.Parallel()
+
+	resp := s.request(
+		http.MethodPost,
+		parseRequestURL(t, "/api/v1/login"),
+		strings.NewReader("username"),
+		map[string]string{echo.HeaderContentType: "text/plain"},
+	)
+	require.EqualValues(t, http.StatusUnsupportedMediaType, resp.Code, resp.Body.String())
+}
+
+func (s *AnwilSuite) TestMeNotAcceptable() {
+	t := s.T()
+	t.Parallel()
+
+	token := s.login()
+
+	resp := s.request(
+		http.MethodGet,
+		parseRequestURL(t, "/api/v1/me"),
+		nil,
+		addAuthHeader(token, map[string]string{echo.HeaderAccept: "text/csv"}),
+	)
+	require.EqualValues(t, http.StatusNotAcceptable, resp.Code, resp.Body.String())
+}
@@ -0,0 +1,108 @@
+//go:build integration
+
+package testing
+
+import (
+	"encoding/json"
+	"net/http"
+
+	th "github.com/outcatcher/anwil/domains/core/testhelpers"
+	"github.com/stretchr/testify/require"
+)
+
+func (s *AnwilSuite) TestNotifications() {
+	t := s.T()
+
+	t.Parallel()
+
+	userData, token := s.newUser(t)
+
+	resp := s.requestJSON(
+		http.MethodPost,
+		parseRequestURL(t, "/api/v1/me/password"),
+		mapBody{"current_password": userData["password"], "new_password": th.RandomString("new-pwd-", 20)},
+		addAuthHeader(token, nil),
+	)
+	require.EqualValues(t, http.StatusNoContent, resp.Code, resp.Body.String())
+
+	var list struct {
+		Notifications []mapBody `json:"notifications"`
+		Unread        int       `json:"unread"`
+	}
+
+	resp = s.request(
+		http.MethodGet, parseRequestURL(t, "/api/v1/me/notifications?unread=true"), nil, addAuthHeader(token, nil),
+	)
+	require.EqualValues(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+	require.Equal(t, 1, list.Unread)
+	require.Len(t, list.Notifications, 1)
+	require.Equal(t, "account.password_changed", list.Notifications[0]["type"])
+
+	notificationUUID, _ := list.Notifications[0]["uuid"].(string)
+
+	resp = s.request(
+		http.MethodPost,
+		parseRequestURL(t, "/api/v1/me/notifications/"+notificationUUID+"/read"),
+		nil,
+		addAuthHeader(token, nil),
+	)
+	require.EqualValues(t, http.StatusNoContent, resp.Code, resp.Body.String())
+
+	resp = s.request(http.MethodGet, parseRequestURL(t, "/api/v1/me/notifications"), nil, addAuthHeader(token, nil))
+	require.EqualValues(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+	require.Equal(t, 0, list.Unread)
+	require.Len(t, list.Notifications, 1)
+	require.NotEmpty(t, list.Notifications[0]["read_at"])
+}
+
+func (s *AnwilSuite) TestNotificationPreferences() {
+	t := s.T()
+
+	t.Parallel()
+
+	_, token := s.newUser(t)
+
+	resp := s.requestJSON(
+		http.MethodPut,
+		parseRequestURL(t, "/api/v1/me/notification-preferences/export.ready"),
+		mapBody{"channels": []string{"pigeon"}},
+		addAuthHeader(token, nil),
+	)
+	require.EqualValues(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+
+	resp = s.requestJSON(
+		http.MethodPut,
+		parseRequestURL(t, "/api/v1/me/notification-preferences/unknown"),
+		mapBody{"channels": []string{"email"}},
+		addAuthHeader(token, nil),
+	)
+	require.EqualValues(t, http.StatusNotFound, resp.Code, resp.Body.String())
+
+	resp = s.requestJSON(
+		http.MethodPut,
+		parseRequestURL(t, "/api/v1/me/notification-preferences/export.ready"),
+		mapBody{"channels": []string{"webhook"}},
+		addAuthHeader(token, nil),
+	)
+	require.EqualValues(t, http.StatusNoContent, resp.Code, resp.Body.String())
+
+	resp = s.request(
+		http.MethodGet, parseRequestURL(t, "/api/v1/me/notification-preferences"), nil, addAuthHeader(token, nil),
+	)
+	require.EqualValues(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var preferences []struct {
+		Type     string   `json:"type"`
+		Channels []string `json:"channels"`
+	}
+
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &preferences))
+
+	for _, preference := range preferences {
+		if preference.Type == "export.ready" {
+			require.Equal(t, []string{"webhook"}, preference.Channels)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+//go:build integration
+
+package testing
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	th "github.com/outcatcher/anwil/domains/core/testhelpers"
+	"github.com/outcatcher/anwil/domains/mail"
+	mailSchema "github.com/outcatcher/anwil/domains/mail/schema"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	mailWaitTimeout = 10 * time.Second
+	mailWaitPeriod  = 100 * time.Millisecond
+)
+
+var tokenRe = regexp.MustCompile(`[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)
+
+// lastMailToken waits until at least count messages are sent to the recipient
+// and returns token from the last one. Messages are sent in background.
+func (s *AnwilSuite) lastMailToken(t *testing.T, recipient string, count int) string {
+	t.Helper()
+
+	var messages []mailSchema.Message
+
+	require.Eventually(t, func() bool {
+		var err error
+
+		messages, err = mail.ReadMessages(s.mailDir, recipient)
+		require.NoError(t, err)
+
+		return len(messages) >= count
+	}, mailWaitTimeout, mailWaitPeriod)
+
+	token := tokenRe.FindString(messages[len(messages)-1].Body)
+	require.NotEmpty(t, token)
+
+	return token
+}
+
+func (s *AnwilSuite) TestPasswordRecovery() {
+	t := s.T()
+
+	t.Parallel()
+
+	email := th.RandomString("mail-", 10) + "@example.com"
+	userData := randomUserData()
+	userData["email"] = email
+
+	resp := s.requestJSON(http.MethodPost, parseRequestURL(t, "/api/v1/wisher"), userData, nil)
+	require.EqualValues(t, http.StatusCreated, resp.Code, resp.Body.String())
+
+	// reset is not possible before email is verified
+	resp = s.requestJSON(http.MethodPost, parseRequestURL(t, "/api/v1/password-reset"), mapBody{"email": email}, nil)
+	require.EqualValues(t, http.StatusAccepted, resp.Code, resp.Body.String())
+
+	verificationToken := s.lastMailToken(t, email, 1)
+
+	resp = s.request(
+		http.MethodGet, parseRequestURL(t, "/api/v1/wisher/verify-email?token="+verificationToken), nil, nil,
+	)
+	require.EqualValues(t, http.StatusNoContent, resp.Code, resp.Body.String())
+
+	// tokens are single-use
+	resp = s.request(
+		http.MethodGet, parseRequestURL(t, "/api/v1/wisher/verify-email?token="+verificationToken), nil, nil,
+	)
+	require.EqualValues(t, http.StatusUnauthorized, resp.Code, resp.Body.String())
+
+	resp = s.requestJSON(http.MethodPost, parseRequestURL(t, "/api/v1/password-reset"), mapBody{"email": email}, nil)
+	require.EqualValues(t, http.StatusAccepted, resp.Code, resp.Body.String())
+
+	resetToken := s.lastMailToken(t, email, 2)
+	require.NotEqual(t, verificationToken, resetToken)
+
+	newPassword := th.RandomString("new-pwd-", 20)
+
+	resp = s.requestJSON(
+		http.MethodPost,
+		parseRequestURL(t, "/api/v1/password-reset/confirm"),
+		mapBody{"token": resetToken, "password": newPassword},
+		nil,
+	)
+	require.EqualValues(t, http.StatusNoContent, resp.Code, resp.Body.String())
+
+	resp = s.requestJSON(
+		http.MethodPost,
+		parseRequestURL(t, "/api/v1/login"),
+		mapBody{"username": userData["username"], "password": newPassword},
+		nil,
+	)
+	require.EqualValues(t, http.StatusOK, resp.Code, resp.Body.String())
+}
+
+func (s *AnwilSuite) TestPasswordReset_invalidToken() {
+	t := s.T()
+
+	t.Parallel()
+
+	resp := s.requestJSON(
+		http.MethodPost,
+		parseRequestURL(t, "/api/v1/password-reset/confirm"),
+		mapBody{"token": "not.a.token", "password": th.RandomString("pwd-", 20)},
+		nil,
+	)
+	require.EqualValues(t, http.StatusUnauthorized, resp.Code, resp.Body.String())
+}
@@ -0,0 +1,186 @@
+//go:build integration
+
+package testing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/outcatcher/anwil/domains/core/errbase"
+	santaStorage "github.com/outcatcher/anwil/domains/santa/storage"
+	"github.com/stretchr/testify/require"
+)
+
+// santaMember - user taking part in Secret Santa tests.
+type santaMember struct {
+	UUID     string
+	Username string
+	Token    string
+}
+
+func (s *AnwilSuite) newSantaMember(t *testing.T) santaMember {
+	t.Helper()
+
+	userData, token := s.newUser(t)
+
+	resp := s.request(http.MethodGet, parseRequestURL(t, "/api/v1/me"), nil, addAuthHeader(token, nil))
+	require.EqualValues(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var profile struct {
+		UUID string `json:"uuid"`
+	}
+
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &profile))
+
+	return santaMember{UUID: profile.UUID, Username: userData["username"].(string), Token: token} //nolint:forcetypeassert
+}
+
+func (s *AnwilSuite) TestSecretSanta() {
+	t := s.T()
+
+	t.Parallel()
+
+	owner := s.newSantaMember(t)
+	husband := s.newSantaMember(t)
+	wife := s.newSantaMember(t)
+	colleague := s.newSantaMember(t)
+	latecomer := s.newSantaMember(t)
+
+	resp := s.requestJSON(
+		http.MethodPost, parseRequestURL(t, "/api/v1/santa/groups"), mapBody{"name": "Office"},
+		addAuthHeader(owner.Token, nil),
+	)
+	require.EqualValues(t, http.StatusCreated, resp.Code, resp.Body.String())
+
+	var group struct {
+		UUID string `json:"uuid"`
+	}
+
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &group))
+
+	groupPath := "/api/v1/santa/groups/" + group.UUID
+
+	for _, member := range []santaMember{husband, wife, colleague, latecomer} {
+		resp = s.requestJSON(
+			http.MethodPost, parseRequestURL(t, groupPath+"/members"), mapBody{"username": member.Username},
+			addAuthHeader(owner.Token, nil),
+		)
+		require.EqualValues(t, http.StatusCreated, resp.Code, resp.Body.String())
+	}
+
+	resp = s.request(http.MethodGet, parseRequestURL(t, "/api/v1/santa/invites"), nil, addAuthHeader(husband.Token, nil))
+	require.EqualValues(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.Contains(t, resp.Body.String(), group.UUID)
+
+	for _, member := range []santaMember{husband, wife, colleague} {
+		resp = s.request(http.MethodPost, parseRequestURL(t, groupPath+"/join"), nil, addAuthHeader(member.Token, nil))
+		require.EqualValues(t, http.StatusNoContent, resp.Code, resp.Body.String())
+	}
+
+	resp = s.request(http.MethodPost, parseRequestURL(t, groupPath+"/draw"), nil, addAuthHeader(husband.Token, nil))
+	require.EqualValues(t, http.StatusForbidden, resp.Code, resp.Body.String())
+
+	resp = s.requestJSON(
+		http.MethodPost, parseRequestURL(t, groupPath+"/exclusions"),
+		mapBody{"giver_uuid": husband.UUID, "receiver_uuid": wife.UUID, "mutual": true},
+		addAuthHeader(owner.Token, nil),
+	)
+	require.EqualValues(t, http.StatusCreated, resp.Code, resp.Body.String())
+
+	resp = s.request(http.MethodGet, parseRequestURL(t, groupPath+"/assignment"), nil, addAuthHeader(wife.Token, nil))
+	require.EqualValues(t, http.StatusNotFound, resp.Code, resp.Body.String())
+
+	resp = s.request(http.MethodPost, parseRequestURL(t, groupPath+"/draw"), nil, addAuthHeader(owner.Token, nil))
+	require.EqualValues(t, http.StatusNoContent, resp.Code, resp.Body.String())
+
+	resp = s.request(http.MethodPost, parseRequestURL(t, groupPath+"/draw"), nil, addAuthHeader(owner.Token, nil))
+	require.EqualValues(t, http.StatusConflict, resp.Code, resp.Body.String())
+
+	// pending invites are dropped on draw
+	resp = s.request(http.MethodGet, parseRequestURL(t, groupPath), nil, addAuthHeader(latecomer.Token, nil))
+	require.EqualValues(t, http.StatusNotFound, resp.Code, resp.Body.String())
+
+	assignments := make(map[string]string)
+
+	for _, member := range []santaMember{owner, husband, wife, colleague} {
+		resp = s.request(http.MethodGet, parseRequestURL(t, groupPath+"/assignment"), nil, addAuthHeader(member.Token, nil))
+		require.EqualValues(t, http.StatusOK, resp.Code, resp.Body.String())
+
+		var receiver struct {
+			UUID     string `json:"uuid"`
+			Username string `json:"username"`
+		}
+
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &receiver))
+		require.NotEmpty(t, receiver.Username)
+
+		assignments[member.UUID] = receiver.UUID
+	}
+
+	received := make(map[string]bool)
+
+	for giver, receiver := range assignments {
+		require.NotEqual(t, giver, receiver)
+		require.False(t, received[receiver], "member receives twice")
+
+		received[receiver] = true
+	}
+
+	require.Len(t, received, 4)
+	require.NotEqual(t, wife.UUID, assignments[husband.UUID])
+	require.NotEqual(t, husband.UUID, assignments[wife.UUID])
+}
+
+func (s *AnwilSuite) TestSecretSantaMembersChange() {
+	t := s.T()
+
+	t.Parallel()
+
+	ctx := context.Background()
+
+	owner := s.newSantaMember(t)
+	member := s.newSantaMember(t)
+	latecomer := s.newSantaMember(t)
+
+	resp := s.requestJSON(
+		http.MethodPost, parseRequestURL(t, "/api/v1/santa/groups"), mapBody{"name": "Family"},
+		addAuthHeader(owner.Token, nil),
+	)
+	require.EqualValues(t, http.StatusCreated, resp.Code, resp.Body.String())
+
+	var group struct {
+		UUID string `json:"uuid"`
+	}
+
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &group))
+
+	for _, invited := range []santaMember{member, latecomer} {
+		resp = s.requestJSON(
+			http.MethodPost, parseRequestURL(t, "/api/v1/santa/groups/"+group.UUID+"/members"),
+			mapBody{"username": invited.Username}, addAuthHeader(owner.Token, nil),
+		)
+		require.EqualValues(t, http.StatusCreated, resp.Code, resp.Body.String())
+	}
+
+	store := santaStorage.New(s.db)
+
+	require.NoError(t, store.UpdateMemberStatus(ctx, group.UUID, member.UUID, santaStorage.StatusJoined))
+
+	// names drawn for owner and member only
+	assignments := []santaStorage.Pair{
+		{GroupUUID: group.UUID, GiverUUID: owner.UUID, ReceiverUUID: member.UUID},
+		{GroupUUID: group.UUID, GiverUUID: member.UUID, ReceiverUUID: owner.UUID},
+	}
+
+	// latecomer joins after the members are listed for the draw
+	require.NoError(t, store.UpdateMemberStatus(ctx, group.UUID, latecomer.UUID, santaStorage.StatusJoined))
+	require.ErrorIs(t, store.SaveDraw(ctx, group.UUID, assignments), errbase.ErrConflict)
+
+	require.NoError(t, store.DeleteMember(ctx, group.UUID, latecomer.UUID))
+	require.NoError(t, store.SaveDraw(ctx, group.UUID, assignments))
+
+	// members can't leave after the draw
+	require.ErrorIs(t, store.DeleteMember(ctx, group.UUID, member.UUID), errbase.ErrConflict)
+}
@@ -0,0 +1,85 @@
+//go:build integration
+
+package testing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	th "github.com/outcatcher/anwil/domains/core/testhelpers"
+	"github.com/stretchr/testify/require"
+)
+
+const streamTimeout = 3 * time.Second
+
+// streamRecorder - response recorder signaling the first write, i.e. established event stream.
+type streamRecorder struct {
+	*httptest.ResponseRecorder
+
+	once      sync.Once
+	connected chan struct{}
+}
+
+func (r *streamRecorder) Write(data []byte) (int, error) {
+	defer r.once.Do(func() { close(r.connected) })
+
+	return r.ResponseRecorder.Write(data) //nolint:wrapcheck
+}
+
+func (s *AnwilSuite) TestEventStream() {
+	t := s.T()
+
+	t.Parallel()
+
+	userData, token := s.newUser(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), streamTimeout)
+	defer cancel()
+
+	recorder := &streamRecorder{ResponseRecorder: httptest.NewRecorder(), connected: make(chan struct{})}
+
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/me/events", nil).WithContext(ctx)
+	request.Header.Set("Authorization", "Bearer "+token)
+
+	finished := make(chan struct{})
+
+	go func() {
+		defer close(finished)
+
+		s.apiHandler(recorder, request)
+	}()
+
+	select {
+	case <-recorder.connected:
+	case <-finished:
+		require.Fail(t, "stream closed", recorder.Body.String())
+	}
+
+	// password change publishes notification
+	resp := s.requestJSON(
+		http.MethodPost,
+		parseRequestURL(t, "/api/v1/me/password"),
+		mapBody{"current_password": userData["password"], "new_password": th.RandomString("new-pwd-", 20)},
+		addAuthHeader(token, nil),
+	)
+	require.EqualValues(t, http.StatusNoContent, resp.Code, resp.Body.String())
+
+	<-finished
+
+	require.EqualValues(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "text/event-stream", recorder.Header().Get("Content-Type"))
+	require.Contains(t, recorder.Body.String(), "event: notification\ndata: ")
+	require.Contains(t, recorder.Body.String(), "account.password_changed")
+}
+
+func (s *AnwilSuite) TestEventStream_401() {
+	t := s.T()
+
+	t.Parallel()
+
+	resp := s.request(http.MethodGet, parseRequestURL(t, "/api/v1/me/events"), nil, nil)
+	require.EqualValues(t, http.StatusUnauthorized, resp.Code, resp.Body.String())
+}
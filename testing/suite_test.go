@@ -22,6 +22,7 @@ import (
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/client"
 	"github.com/docker/go-connections/nat"
+	"github.com/jmoiron/sqlx"
 	"github.com/labstack/echo/v4"
 	"github.com/outcatcher/anwil/domains/api"
 	"github.com/outcatcher/anwil/domains/core/config"
@@ -30,7 +31,10 @@ import (
 	"github.com/outcatcher/anwil/domains/core/services"
 	svcSchema "github.com/outcatcher/anwil/domains/core/services/schema"
 	th "github.com/outcatcher/anwil/domains/core/testhelpers"
+	"github.com/outcatcher/anwil/domains/events"
+	"github.com/outcatcher/anwil/domains/jobs"
 	"github.com/outcatcher/anwil/domains/storage"
+	"github.com/outcatcher/anwil/domains/stream"
 	usersSchema "github.com/outcatcher/anwil/domains/users/service/schema"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -41,6 +45,8 @@ const (
 	containerStartPeriod    = 500 * time.Millisecond
 	containerStartUpTimeout = 10 * time.Second
 
+	jobsStopTimeout = 10 * time.Second
+
 	debugUsername = "debug"
 	debugFullName = "Debug Wisher"
 )
@@ -54,6 +60,15 @@ type AnwilSuite struct {
 	suite.Suite
 
 	apiHandler http.HandlerFunc
+
+	// directory containing messages sent by `file` mailer
+	mailDir string
+
+	db *sqlx.DB
+
+	jobs   *jobs.Runner
+	events *events.Bus
+	stream *stream.Broker
 }
 
 // requestJSON sends request with `content-type: application/json`.
@@ -128,6 +143,8 @@ func (s *AnwilSuite) SetupSuite() {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
+	s.mailDir = cfg.Mail.Directory
+
 	startDBContainer(ctx, t, cfg.DB)
 	require.NoError(t, storage.ApplyMigrations(cfg.DB, "up"))
 
@@ -136,6 +153,19 @@ func (s *AnwilSuite) SetupSuite() {
 
 	createDebugUser(ctx, t, apiState)
 
+	db, ok := apiState.Storage().(*sqlx.DB)
+	require.True(t, ok)
+
+	s.db = db
+
+	s.jobs = apiState.Jobs()
+	require.NoError(t, s.jobs.Start())
+
+	s.events = apiState.Events()
+
+	s.stream = apiState.Stream()
+	require.NoError(t, s.stream.Start())
+
 	srv, err := apiState.Server(ctx)
 	require.NoError(t, err)
 
@@ -145,6 +175,15 @@ func (s *AnwilSuite) SetupSuite() {
 	s.apiHandler = srv.Handler.ServeHTTP
 }
 
+func (s *AnwilSuite) TearDownSuite() {
+	ctx, cancel := context.WithTimeout(context.Background(), jobsStopTimeout)
+	defer cancel()
+
+	require.NoError(s.T(), s.jobs.Stop(ctx))
+	require.NoError(s.T(), s.events.Stop(ctx))
+	require.NoError(s.T(), s.stream.Stop())
+}
+
 func mapToSlice(src map[string]string) []string {
 	result := make([]string, 0, len(src))
 
@@ -0,0 +1,246 @@
+//go:build integration
+
+package testing
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	webhooks "github.com/outcatcher/anwil/domains/webhooks/service"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	webhookWaitTimeout = 10 * time.Second
+	webhookWaitPeriod  = 100 * time.Millisecond
+)
+
+func (s *AnwilSuite) TestWebhooks() {
+	t := s.T()
+
+	t.Parallel()
+
+	_, token := s.newUser(t)
+
+	received := make(chan *http.Request, 1)
+	receivedBodies := make(chan []byte, 1)
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		received <- r
+		receivedBodies <- body
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(receiver.Close)
+
+	resp := s.requestJSON(
+		http.MethodPost,
+		parseRequestURL(t, "/api/v1/me/webhooks"),
+		mapBody{"url": receiver.URL, "event_types": []string{"unknown"}},
+		addAuthHeader(token, nil),
+	)
+	require.EqualValues(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+
+	resp = s.requestJSON(
+		http.MethodPost,
+		parseRequestURL(t, "/api/v1/me/webhooks"),
+		mapBody{"url": receiver.URL, "event_types": []string{"export.ready"}},
+		addAuthHeader(token, nil),
+	)
+	require.EqualValues(t, http.StatusCreated, resp.Code, resp.Body.String())
+
+	var webhook struct {
+		UUID   string `json:"uuid"`
+		Secret string `json:"secret"`
+	}
+
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &webhook))
+	require.NotEmpty(t, webhook.Secret)
+
+	resp = s.request(http.MethodGet, parseRequestURL(t, "/api/v1/me/webhooks"), nil, addAuthHeader(token, nil))
+	require.EqualValues(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.NotContains(t, resp.Body.String(), webhook.Secret)
+
+	resp = s.request(
+		http.MethodPost,
+		parseRequestURL(t, "/api/v1/me/webhooks/"+webhook.UUID+"/test"),
+		nil,
+		addAuthHeader(token, nil),
+	)
+	require.EqualValues(t, http.StatusAccepted, resp.Code, resp.Body.String())
+
+	var request *http.Request
+
+	select {
+	case request = <-received:
+	case <-time.After(webhookWaitTimeout):
+		require.FailNow(t, "webhook is not called")
+	}
+
+	body := <-receivedBodies
+
+	require.Equal(t, "webhook.test", request.Header.Get(webhooks.HeaderEvent))
+	require.Equal(t,
+		"sha256="+webhooks.Sign(webhook.Secret, request.Header.Get(webhooks.HeaderTimestamp), body),
+		request.Header.Get(webhooks.HeaderSignature),
+	)
+
+	var deliveries []mapBody
+
+	require.Eventually(t, func() bool {
+		resp := s.request(
+			http.MethodGet,
+			parseRequestURL(t, "/api/v1/me/webhooks/"+webhook.UUID+"/deliveries"),
+			nil,
+			addAuthHeader(token, nil),
+		)
+		require.EqualValues(t, http.StatusOK, resp.Code, resp.Body.String())
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &deliveries))
+
+		return len(deliveries) == 1 && deliveries[0]["status"] == "delivered"
+	}, webhookWaitTimeout, webhookWaitPeriod)
+
+	require.Equal(t, request.Header.Get(webhooks.HeaderDelivery), deliveries[0]["uuid"])
+
+	// webhooks of other users are not visible
+	_, otherToken := s.newUser(t)
+
+	resp = s.request(
+		http.MethodDelete,
+		parseRequestURL(t, "/api/v1/me/webhooks/"+webhook.UUID),
+		nil,
+		addAuthHeader(otherToken, nil),
+	)
+	require.EqualValues(t, http.StatusNotFound, resp.Code, resp.Body.String())
+
+	resp = s.request(
+		http.MethodDelete,
+		parseRequestURL(t, "/api/v1/me/webhooks/"+webhook.UUID),
+		nil,
+		addAuthHeader(token, nil),
+	)
+	require.EqualValues(t, http.StatusNoContent, resp.Code, resp.Body.String())
+}
+
+func (s *AnwilSuite) TestAdminWebhooks() {
+	t := s.T()
+
+	t.Parallel()
+
+	_, adminToken := s.newUser(t)
+
+	resp := s.request(http.MethodGet, parseRequestURL(t, "/api/v1/me"), nil, addAuthHeader(adminToken, nil))
+	require.EqualValues(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var admin struct {
+		UUID string `json:"uuid"`
+	}
+
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &admin))
+
+	// notifications of all users are sent, so only the user of this test is reported
+	_, userToken := s.newUser(t)
+
+	resp = s.request(http.MethodGet, parseRequestURL(t, "/api/v1/me"), nil, addAuthHeader(userToken, nil))
+	require.EqualValues(t, http.StatusOK, resp.Code, resp.Body.String())
+
+	var user struct {
+		UUID string `json:"uuid"`
+	}
+
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &user))
+
+	reported := make(chan struct{}, 1)
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Data struct {
+				UserUUID string `json:"user_uuid"`
+				Title    string `json:"title"`
+			} `json:"data"`
+		}
+
+		// global webhooks receive redacted notifications only
+		if json.NewDecoder(r.Body).Decode(&payload) == nil && payload.Data.UserUUID == user.UUID &&
+			payload.Data.Title == "" {
+			select {
+			case reported <- struct{}{}:
+			default:
+			}
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(receiver.Close)
+
+	webhookBody := mapBody{"url": receiver.URL, "event_types": []string{"export.ready"}}
+
+	resp = s.requestJSON(
+		http.MethodPost, parseRequestURL(t, "/api/v1/admin/webhooks"), webhookBody, addAuthHeader(adminToken, nil),
+	)
+	require.EqualValues(t, http.StatusForbidden, resp.Code, resp.Body.String())
+
+	_, err := s.db.ExecContext(context.Background(), `UPDATE wishers SET role = 'admin' WHERE uuid = $1;`, admin.UUID)
+	require.NoError(t, err)
+
+	// notifications exposing personal data are not sent to global webhooks
+	resp = s.requestJSON(
+		http.MethodPost,
+		parseRequestURL(t, "/api/v1/admin/webhooks"),
+		mapBody{"url": receiver.URL, "event_types": []string{"santa.invite"}},
+		addAuthHeader(adminToken, nil),
+	)
+	require.EqualValues(t, http.StatusBadRequest, resp.Code, resp.Body.String())
+
+	resp = s.requestJSON(
+		http.MethodPost, parseRequestURL(t, "/api/v1/admin/webhooks"), webhookBody, addAuthHeader(adminToken, nil),
+	)
+	require.EqualValues(t, http.StatusCreated, resp.Code, resp.Body.String())
+
+	var webhook struct {
+		UUID  string `json:"uuid"`
+		Scope string `json:"scope"`
+	}
+
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &webhook))
+	require.Equal(t, "global", webhook.Scope)
+
+	// global webhooks are managed in admin scope only
+	resp = s.request(
+		http.MethodGet, parseRequestURL(t, "/api/v1/me/webhooks"), nil, addAuthHeader(adminToken, nil),
+	)
+	require.EqualValues(t, http.StatusOK, resp.Code, resp.Body.String())
+	require.NotContains(t, resp.Body.String(), webhook.UUID)
+
+	resp = s.request(
+		http.MethodDelete,
+		parseRequestURL(t, "/api/v1/me/webhooks/"+webhook.UUID),
+		nil,
+		addAuthHeader(adminToken, nil),
+	)
+	require.EqualValues(t, http.StatusNotFound, resp.Code, resp.Body.String())
+
+	// the user has no webhooks, but notification is sent to the global one
+	resp = s.requestJSON(http.MethodPost, parseRequestURL(t, "/api/v1/me/exports"), nil, addAuthHeader(userToken, nil))
+	require.EqualValues(t, http.StatusAccepted, resp.Code, resp.Body.String())
+
+	select {
+	case <-reported:
+	case <-time.After(webhookWaitTimeout):
+		require.FailNow(t, "global webhook is not called")
+	}
+
+	resp = s.request(
+		http.MethodDelete,
+		parseRequestURL(t, "/api/v1/admin/webhooks/"+webhook.UUID),
+		nil,
+		addAuthHeader(adminToken, nil),
+	)
+	require.EqualValues(t, http.StatusNoContent, resp.Code, resp.Body.String())
+}